@@ -1,4 +1,5 @@
 import AppKit
+import ApplicationServices
 import ContextGrabberCore
 import Foundation
 
@@ -18,16 +19,21 @@ private struct HostCLIConfiguration {
   var bundleIdentifier: String?
   var captureMethod: HostCLICaptureMethod = .auto
   var outputFormat: HostCLIOutputFormat = .markdown
+  var includeBounds = false
+  var windowIndex: Int?
 }
 
 private let cliTargetActivationTimeoutNanoseconds: UInt64 = 1_500_000_000
 private let cliTargetActivationPollIntervalNanoseconds: UInt64 = 50_000_000
+private let cliWindowRaiseSettleNanoseconds: UInt64 = 150_000_000
 
 struct HostCLIParsedArguments: Equatable {
   let appName: String?
   let bundleIdentifier: String?
   let captureMethod: String
   let outputFormat: String
+  var includeBounds = false
+  var windowIndex: Int?
 }
 
 private struct HostCLICaptureResult {
@@ -35,6 +41,14 @@ private struct HostCLICaptureResult {
   let capturedAt: String
   let resolution: DesktopCaptureResolution
   let markdown: String
+  let windowBounds: HostCLIWindowBounds?
+}
+
+private struct HostCLIWindowBounds: Codable {
+  let x: Double
+  let y: Double
+  let width: Double
+  let height: Double
 }
 
 private struct HostCLIJSONOutput: Codable {
@@ -46,6 +60,7 @@ private struct HostCLIJSONOutput: Codable {
   let errorCode: String?
   let payload: BrowserContextPayload
   let markdown: String
+  let windowBounds: HostCLIWindowBounds?
 }
 
 private enum HostCLIError: LocalizedError {
@@ -55,6 +70,7 @@ private enum HostCLIError: LocalizedError {
   case unknownArgument(String)
   case targetApplicationNotFound(name: String?, bundleIdentifier: String?)
   case targetApplicationActivationFailed(name: String?, bundleIdentifier: String?)
+  case windowIndexOutOfRange(index: Int, windowCount: Int)
   case captureExecutionFailed(String)
 
   var errorDescription: String? {
@@ -83,6 +99,8 @@ private enum HostCLIError: LocalizedError {
         return "Timed out waiting for target application \(name) to become frontmost."
       }
       return "Timed out waiting for target application to become frontmost."
+    case .windowIndexOutOfRange(let index, let windowCount):
+      return "Window index \(index) is out of range; the target application has \(windowCount) window(s)."
     case .captureExecutionFailed(let message):
       return "Headless capture failed: \(message)"
     }
@@ -123,7 +141,9 @@ enum CLIEntryPoint {
       appName: configuration.appName,
       bundleIdentifier: configuration.bundleIdentifier,
       captureMethod: configuration.captureMethod.rawValue,
-      outputFormat: configuration.outputFormat.rawValue
+      outputFormat: configuration.outputFormat.rawValue,
+      includeBounds: configuration.includeBounds,
+      windowIndex: configuration.windowIndex
     )
   }
 
@@ -153,6 +173,15 @@ enum CLIEntryPoint {
           throw HostCLIError.invalidValue(flag: argument, value: value)
         }
         configuration.outputFormat = format
+      case "--include-bounds":
+        configuration.includeBounds = true
+        index += 1
+      case "--window-index":
+        let value = try value(for: argument, args: args, index: &index)
+        guard let windowIndex = Int(value), windowIndex > 0 else {
+          throw HostCLIError.invalidValue(flag: argument, value: value)
+        }
+        configuration.windowIndex = windowIndex
       case "--help", "-h":
         throw HostCLIError.usageRequested
       default:
@@ -160,6 +189,12 @@ enum CLIEntryPoint {
       }
     }
 
+    if let windowIndex = configuration.windowIndex,
+      configuration.appName == nil, configuration.bundleIdentifier == nil
+    {
+      throw HostCLIError.invalidValue(flag: "--window-index", value: "\(windowIndex) (requires --app or --bundle-id)")
+    }
+
     return configuration
   }
 
@@ -193,6 +228,10 @@ enum CLIEntryPoint {
           bundleIdentifier: targetApp.bundleIdentifier ?? configuration.bundleIdentifier
         )
       }
+      if let windowIndex = configuration.windowIndex {
+        try raiseWindow(at: windowIndex, processIdentifier: targetApp.processIdentifier)
+        try? await Task.sleep(nanoseconds: cliWindowRaiseSettleNanoseconds)
+      }
     }
 
     let fallbackFrontmost = targetApp == nil ? NSWorkspace.shared.frontmostApplication : nil
@@ -223,14 +262,70 @@ enum CLIEntryPoint {
       includeProductContextLine: HostSettings.defaultIncludeProductContextLine
     )
 
+    let windowBounds = configuration.includeBounds
+      ? frontmostWindowBounds(processIdentifier: processIdentifier)
+      : nil
+
     return HostCLICaptureResult(
       requestID: requestID,
       capturedAt: capturedAt,
       resolution: resolution,
-      markdown: markdown
+      markdown: markdown,
+      windowBounds: windowBounds
     )
   }
 
+  /// Raises the target application's window at the 1-based `windowIndex`,
+  /// in the front-to-back order Accessibility reports, so the frontmost-window
+  /// capture reads that window.
+  private static func raiseWindow(at windowIndex: Int, processIdentifier: pid_t) throws {
+    let application = AXUIElementCreateApplication(processIdentifier)
+    var windowsValue: CFTypeRef?
+    let status = AXUIElementCopyAttributeValue(application, kAXWindowsAttribute as CFString, &windowsValue)
+    guard status == .success else {
+      throw HostCLIError.captureExecutionFailed(
+        "Unable to read the target application's windows for --window-index; Accessibility permission may be missing."
+      )
+    }
+    let windows = (windowsValue as? [AXUIElement]) ?? []
+    guard windowIndex <= windows.count else {
+      throw HostCLIError.windowIndexOutOfRange(index: windowIndex, windowCount: windows.count)
+    }
+
+    let window = windows[windowIndex - 1]
+    AXUIElementSetAttributeValue(window, kAXMainAttribute as CFString, kCFBooleanTrue)
+    AXUIElementPerformAction(window, kAXRaiseAction as CFString)
+  }
+
+  private static func frontmostWindowBounds(processIdentifier: pid_t?) -> HostCLIWindowBounds? {
+    guard let windowList = CGWindowListCopyWindowInfo(
+      [.optionOnScreenOnly, .excludeDesktopElements],
+      kCGNullWindowID
+    ) as? [[String: Any]],
+      let windowID = frontmostWindowIDFromWindowList(windowList, frontmostProcessIdentifier: processIdentifier)
+    else {
+      return nil
+    }
+
+    for window in windowList {
+      let number = (window[kCGWindowNumber as String] as? Int).flatMap { UInt32(exactly: $0) }
+        ?? window[kCGWindowNumber as String] as? UInt32
+      guard number == windowID,
+        let boundsDictionary = window[kCGWindowBounds as String] as? NSDictionary,
+        let frame = CGRect(dictionaryRepresentation: boundsDictionary as CFDictionary)
+      else {
+        continue
+      }
+      return HostCLIWindowBounds(
+        x: Double(frame.origin.x),
+        y: Double(frame.origin.y),
+        width: Double(frame.width),
+        height: Double(frame.height)
+      )
+    }
+    return nil
+  }
+
   private static func resolveTargetApplication(
     configuration: HostCLIConfiguration
   ) throws -> NSRunningApplication? {
@@ -348,7 +443,8 @@ enum CLIEntryPoint {
         warning: result.resolution.warning,
         errorCode: result.resolution.errorCode,
         payload: result.resolution.payload,
-        markdown: result.markdown
+        markdown: result.markdown,
+        windowBounds: result.windowBounds
       )
       let encoder = JSONEncoder()
       encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
@@ -368,11 +464,13 @@ enum CLIEntryPoint {
   ContextGrabberHost CLI mode
 
   Usage:
-    ContextGrabberHost --capture [--app <name>] [--bundle-id <id>] [--method auto|ax|ocr] [--format markdown|json]
+    ContextGrabberHost --capture [--app <name>] [--bundle-id <id>] [--method auto|ax|ocr] [--format markdown|json] [--include-bounds] [--window-index <n>]
 
   Examples:
     ContextGrabberHost --capture --app Finder
     ContextGrabberHost --capture --bundle-id com.apple.dt.Xcode --method ax
     ContextGrabberHost --capture --format json
+    ContextGrabberHost --capture --app Finder --format json --include-bounds
+    ContextGrabberHost --capture --app Finder --window-index 2
   """
 }
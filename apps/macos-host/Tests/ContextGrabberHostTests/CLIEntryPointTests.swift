@@ -45,6 +45,33 @@ final class CLIEntryPointTests: XCTestCase {
     XCTAssertEqual(parsed.outputFormat, "json")
   }
 
+  func testParseArgumentsForTestingParsesIncludeBounds() throws {
+    let parsed = try CLIEntryPoint.parseArgumentsForTesting(
+      arguments: ["ContextGrabberHost", "--capture", "--format", "json", "--include-bounds"]
+    )
+
+    XCTAssertTrue(parsed.includeBounds)
+    XCTAssertEqual(parsed.outputFormat, "json")
+  }
+
+  func testParseArgumentsForTestingParsesWindowIndex() throws {
+    let parsed = try CLIEntryPoint.parseArgumentsForTesting(
+      arguments: ["ContextGrabberHost", "--capture", "--app", "Finder", "--window-index", "2"]
+    )
+
+    XCTAssertEqual(parsed.windowIndex, 2)
+  }
+
+  func testParseArgumentsForTestingRejectsInvalidWindowIndex() {
+    for arguments in [
+      ["ContextGrabberHost", "--capture", "--app", "Finder", "--window-index", "0"],
+      ["ContextGrabberHost", "--capture", "--app", "Finder", "--window-index", "two"],
+      ["ContextGrabberHost", "--capture", "--window-index", "1"],
+    ] {
+      XCTAssertThrowsError(try CLIEntryPoint.parseArgumentsForTesting(arguments: arguments))
+    }
+  }
+
   func testParseArgumentsForTestingThrowsWhenFlagValueMissing() {
     XCTAssertThrowsError(
       try CLIEntryPoint.parseArgumentsForTesting(
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newAppsCommand(global *globalOptions) *cobra.Command {
+	appsCmd := &cobra.Command{
+		Use:   "apps",
+		Short: "Control running desktop apps",
+	}
+	appsCmd.AddCommand(newAppsActivateCommand(global))
+	return appsCmd
+}
+
+func newAppsActivateCommand(global *globalOptions) *cobra.Command {
+	var appName string
+	var bundleID string
+	activateCmd := &cobra.Command{
+		Use:   "activate",
+		Short: "Bring an app to the front without capturing it",
+		Example: "  cgrab apps activate --app Finder\n" +
+			"  cgrab apps activate --bundle-id com.apple.Notes",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			appName = strings.TrimSpace(appName)
+			bundleID = strings.TrimSpace(bundleID)
+			if (appName == "") == (bundleID == "") {
+				return usageError(fmt.Errorf("apps activate requires exactly one of --app or --bundle-id"))
+			}
+
+			if bundleID != "" {
+				if err := activateAppByBundleFunc(cmd.Context(), bundleID); err != nil {
+					return classifyPermissionError(fmt.Errorf("failed to activate app %s: %w", bundleID, err))
+				}
+				fmt.Fprintf(global.warnings(cmd.OutOrStdout()), "Activated %s\n", bundleID)
+				return nil
+			}
+
+			// As with capture --app, the name resolves to the running app's
+			// canonical name; an app that is not running keeps the name as
+			// given, which activation can still launch.
+			apps, warnings, err := listAppsFunc(cmd.Context())
+			writeWarnings(global.warnings(cmd.ErrOrStderr()), warnings)
+			if err == nil {
+				if matched := findAppByExactName(apps, appName); matched != nil {
+					appName = matched.AppName
+				}
+			}
+			if err := activateAppByNameFunc(cmd.Context(), appName); err != nil {
+				return classifyPermissionError(fmt.Errorf("failed to activate app %s: %w", appName, err))
+			}
+			fmt.Fprintf(global.warnings(cmd.OutOrStdout()), "Activated %s\n", appName)
+			return nil
+		},
+	}
+	activateCmd.Flags().StringVar(&appName, "app", "", "app by exact name (case- and whitespace-insensitive against running apps)")
+	activateCmd.Flags().StringVar(&bundleID, "bundle-id", "", "app by bundle identifier")
+	return activateCmd
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestAppsActivateBringsAppToFront(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1}}, nil, nil
+	})
+	defer restore()
+	var activated []string
+	activateAppByNameFunc = func(_ context.Context, name string) error {
+		activated = append(activated, "name:"+name)
+		return nil
+	}
+	previousBundle := activateAppByBundleFunc
+	t.Cleanup(func() { activateAppByBundleFunc = previousBundle })
+	activateAppByBundleFunc = func(_ context.Context, bundleID string) error {
+		activated = append(activated, "bundle:"+bundleID)
+		return nil
+	}
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		t.Fatalf("apps activate should not capture")
+		return nil, nil
+	}
+
+	stdout, _, err := runRootCommand("apps", "activate", "--app", "finder")
+	if err != nil {
+		t.Fatalf("apps activate --app returned error: %v", err)
+	}
+	if stdout != "Activated Finder\n" {
+		t.Fatalf("unexpected output %q", stdout)
+	}
+	if _, _, err := runRootCommand("apps", "activate", "--bundle-id", "com.apple.Notes", "--quiet"); err != nil {
+		t.Fatalf("apps activate --bundle-id returned error: %v", err)
+	}
+	if len(activated) != 2 || activated[0] != "name:Finder" || activated[1] != "bundle:com.apple.Notes" {
+		t.Fatalf("unexpected activations: %v", activated)
+	}
+
+	for _, args := range [][]string{
+		{"apps", "activate"},
+		{"apps", "activate", "--app", "Finder", "--bundle-id", "com.apple.finder"},
+		{"apps", "activate", "--app", "  "},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+)
+
+// captureBatchEntry is one element of a capture --batch file. Selector keys
+// mirror the capture flags; unset method, timeout, and format fall back to
+// the command-line values.
+type captureBatchEntry struct {
+	Focused        bool   `json:"focused,omitempty"`
+	Tab            string `json:"tab,omitempty"`
+	URLMatch       string `json:"urlMatch,omitempty"`
+	URL            string `json:"url,omitempty"`
+	TitleMatch     string `json:"titleMatch,omitempty"`
+	ChromeApp      string `json:"chromeApp,omitempty"`
+	App            string `json:"app,omitempty"`
+	AppIndex       int    `json:"appIndex,omitempty"`
+	NameMatch      string `json:"nameMatch,omitempty"`
+	AppRegex       string `json:"appRegex,omitempty"`
+	BundleID       string `json:"bundleId,omitempty"`
+	BundleIDPrefix string `json:"bundleIdPrefix,omitempty"`
+	FocusedApp     bool   `json:"focusedApp,omitempty"`
+	Browser        string `json:"browser,omitempty"`
+	Method         string `json:"method,omitempty"`
+	TimeoutMs      int    `json:"timeoutMs,omitempty"`
+	Format         string `json:"format,omitempty"`
+	First          bool   `json:"first,omitempty"`
+	Output         string `json:"output"`
+}
+
+type captureBatchResult struct {
+	Index  int    `json:"index"`
+	Output string `json:"output,omitempty"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
+
+type captureBatchSummary struct {
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []captureBatchResult `json:"results"`
+}
+
+func readCaptureBatch(path string) ([]captureBatchEntry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read batch file: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var entries []captureBatchEntry
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("parse batch file %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("batch file %s contains no entries", path)
+	}
+	return entries, nil
+}
+
+// request builds the captureRequest for an entry, taking unset fields from
+// defaults (the request built from the command-line flags).
+func (e captureBatchEntry) request(defaults captureRequest) captureRequest {
+	request := defaults
+	request.focused = e.Focused
+	request.tabReference = strings.TrimSpace(e.Tab)
+	request.urlMatch = strings.TrimSpace(e.URLMatch)
+	request.pageURL = strings.TrimSpace(e.URL)
+	request.titleMatch = strings.TrimSpace(e.TitleMatch)
+	request.chromeApp = strings.TrimSpace(e.ChromeApp)
+	request.appName = strings.TrimSpace(e.App)
+	request.appIndex = e.AppIndex
+	request.nameMatch = strings.TrimSpace(e.NameMatch)
+	request.appRegex = strings.TrimSpace(e.AppRegex)
+	request.bundleID = strings.TrimSpace(e.BundleID)
+	request.bundleIDPrefix = strings.TrimSpace(e.BundleIDPrefix)
+	request.focusedApp = e.FocusedApp
+	request.browser = strings.TrimSpace(e.Browser)
+	request.first = e.First
+	if method := strings.ToLower(strings.TrimSpace(e.Method)); method != "" {
+		request.method = method
+	}
+	if e.TimeoutMs != 0 {
+		request.timeoutMs = e.TimeoutMs
+	}
+	if format := strings.ToLower(strings.TrimSpace(e.Format)); format != "" {
+		request.outputFormat = format
+	}
+	return request
+}
+
+// runCaptureBatch captures every entry into its own output file, running up
+// to concurrency captures at once. A failed entry is recorded in the summary
+// and does not stop the remaining entries. Results and each entry's warnings
+// are reported in input order regardless of completion order; entries not
+// started before ctx is canceled fail with the context error.
+func runCaptureBatch(
+	ctx context.Context,
+	entries []captureBatchEntry,
+	defaults captureRequest,
+	concurrency int,
+	writeOptions func(format string) []output.Option,
+	stderr io.Writer,
+) captureBatchSummary {
+	results := make([]captureBatchResult, len(entries))
+	warnings := make([]bytes.Buffer, len(entries))
+	semaphore := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for index, entry := range entries {
+		results[index] = captureBatchResult{Index: index + 1, Output: strings.TrimSpace(entry.Output)}
+		if ctx.Err() != nil {
+			results[index].Error = ctx.Err().Error()
+			continue
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			results[index].Error = ctx.Err().Error()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if err := captureBatchEntryToFile(ctx, entry, defaults, writeOptions, &warnings[index]); err != nil {
+				results[index].Error = err.Error()
+				return
+			}
+			results[index].OK = true
+		}()
+	}
+	wg.Wait()
+
+	summary := captureBatchSummary{Results: results}
+	for index, result := range results {
+		_, _ = warnings[index].WriteTo(stderr)
+		if result.OK {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+func captureBatchEntryToFile(
+	ctx context.Context,
+	entry captureBatchEntry,
+	defaults captureRequest,
+	writeOptions func(format string) []output.Option,
+	stderr io.Writer,
+) error {
+	outputFile := strings.TrimSpace(entry.Output)
+	if outputFile == "" {
+		return fmt.Errorf("batch entry requires an output path")
+	}
+	request := entry.request(defaults)
+	mode, err := request.validate()
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(stderr, captureFileExtensionWarning(outputFile, request.outputFormat, request.method))
+	rendered, _, err := executeCapture(ctx, request, mode, outputFile, stderr)
+	if err != nil {
+		return err
+	}
+	if request.method == browserMethodURLPDF {
+		return nil
+	}
+	return output.Write(ctx, rendered, outputFile, false, writeOptions(request.outputFormat)...)
+}
+
+func renderCaptureBatchSummary(summary captureBatchSummary, format string) ([]byte, error) {
+	if format == formatJSON || format == formatYAML {
+		encoded, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode batch summary: %w", err)
+		}
+		if format == formatYAML {
+			return output.JSONToYAML(encoded)
+		}
+		return append(encoded, '\n'), nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("# Capture Batch\n\n")
+	for _, result := range summary.Results {
+		if result.OK {
+			fmt.Fprintf(&builder, "- %d: ok (%s)\n", result.Index, result.Output)
+			continue
+		}
+		fmt.Fprintf(&builder, "- %d: failed: %s\n", result.Index, result.Error)
+	}
+	fmt.Fprintf(&builder, "\n%d captured, %d failed\n", summary.Succeeded, summary.Failed)
+	return []byte(builder.String()), nil
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+)
+
+func TestRunCaptureBatchCapturesEachEntryAndReportsFailures(t *testing.T) {
+	stubCaptureEnvironment(t)
+	var requests []bridge.DesktopCaptureRequest
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		requests = append(requests, request)
+		return []byte("# " + request.AppName + "\n"), nil
+	}
+
+	dir := t.TempDir()
+	entries := []captureBatchEntry{
+		{App: "Finder", Output: filepath.Join(dir, "finder.md")},
+		{App: "Notes", Method: "ocr", Output: filepath.Join(dir, "notes.md")},
+		{App: "Finder", NameMatch: "xcode", Output: filepath.Join(dir, "mixed.md")},
+		{App: "Finder"},
+	}
+	defaults := captureRequest{method: "auto", timeoutMs: 1200, outputFormat: formatMarkdown}
+
+	summary := runCaptureBatch(context.Background(), entries, defaults, 1, defaultGlobalOptions().writeOptions, &strings.Builder{})
+	if summary.Succeeded != 2 || summary.Failed != 2 {
+		t.Fatalf("unexpected summary counts: %+v", summary)
+	}
+	if len(requests) != 2 || requests[1].Method != bridge.DesktopCaptureMethodOCR {
+		t.Fatalf("expected per-entry method override, got %+v", requests)
+	}
+	if !strings.Contains(summary.Results[2].Error, "desktop capture accepts only one selector") {
+		t.Fatalf("expected validation failure for entry 3, got %+v", summary.Results[2])
+	}
+	if !strings.Contains(summary.Results[3].Error, "requires an output path") {
+		t.Fatalf("expected missing output failure for entry 4, got %+v", summary.Results[3])
+	}
+	payload, err := os.ReadFile(filepath.Join(dir, "notes.md"))
+	if err != nil || string(payload) != "# Notes\n" {
+		t.Fatalf("expected notes capture written, got %q err=%v", payload, err)
+	}
+
+	rendered, err := renderCaptureBatchSummary(summary, formatMarkdown)
+	if err != nil {
+		t.Fatalf("render summary: %v", err)
+	}
+	if !strings.Contains(string(rendered), "- 1: ok (") || !strings.Contains(string(rendered), "2 captured, 2 failed") {
+		t.Fatalf("unexpected markdown summary: %s", rendered)
+	}
+}
+
+func TestRunCaptureBatchBoundsConcurrencyAndKeepsInputOrder(t *testing.T) {
+	stubCaptureEnvironment(t)
+	var mu sync.Mutex
+	running, peak := 0, 0
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return []byte("# " + request.AppName + "\n"), nil
+	}
+
+	dir := t.TempDir()
+	entries := []captureBatchEntry{
+		{App: "A", Output: filepath.Join(dir, "a.json")},
+		{App: "B", Output: filepath.Join(dir, "b.md")},
+		{App: "C", Output: filepath.Join(dir, "c.json")},
+		{App: "D", Output: filepath.Join(dir, "d.md")},
+		{App: "E", Output: filepath.Join(dir, "e.md")},
+	}
+	defaults := captureRequest{method: "auto", timeoutMs: 1200, outputFormat: formatMarkdown}
+
+	var stderr strings.Builder
+	summary := runCaptureBatch(context.Background(), entries, defaults, 2, defaultGlobalOptions().writeOptions, &stderr)
+	if summary.Succeeded != 5 || peak != 2 {
+		t.Fatalf("expected 5 captures with at most 2 at once, got %+v peak=%d", summary, peak)
+	}
+	for index, result := range summary.Results {
+		if result.Index != index+1 || result.Output != entries[index].Output {
+			t.Fatalf("results out of input order: %+v", summary.Results)
+		}
+	}
+	if first, second := strings.Index(stderr.String(), "a.json"), strings.Index(stderr.String(), "c.json"); first < 0 || second < first {
+		t.Fatalf("expected per-entry warnings in input order, got %q", stderr.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	summary = runCaptureBatch(ctx, entries, defaults, 2, defaultGlobalOptions().writeOptions, &strings.Builder{})
+	if summary.Failed != 5 || !strings.Contains(summary.Results[0].Error, "context canceled") {
+		t.Fatalf("expected canceled batch to fail every entry, got %+v", summary)
+	}
+}
+
+func TestReadCaptureBatchRejectsUnknownKeysAndEmptyFiles(t *testing.T) {
+	dir := t.TempDir()
+	typo := filepath.Join(dir, "typo.json")
+	if err := os.WriteFile(typo, []byte(`[{"ap": "Finder", "output": "x.md"}]`), 0o644); err != nil {
+		t.Fatalf("write batch file: %v", err)
+	}
+	if _, err := readCaptureBatch(typo); err == nil || !strings.Contains(err.Error(), "ap") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte(`[]`), 0o644); err != nil {
+		t.Fatalf("write batch file: %v", err)
+	}
+	if _, err := readCaptureBatch(empty); err == nil || !strings.Contains(err.Error(), "no entries") {
+		t.Fatalf("expected empty batch error, got %v", err)
+	}
+}
+
+func TestCaptureBatchRejectsPerCaptureFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"capture", "--batch", "targets.json", "--app", "Finder"},
+		{"capture", "--batch", "targets.json", "--clipboard"},
+	} {
+		_, _, err := runRootCommand(args...)
+		if err == nil || !strings.Contains(err.Error(), "--batch cannot be combined") || ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args=%v: expected --batch usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestCaptureConcurrencyRequiresBatch(t *testing.T) {
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--concurrency", "4"},
+		{"capture", "--batch", "targets.json", "--concurrency", "0"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args=%v: expected usage error, got %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+var listBridgeTabsFunc = bridge.ListTabs
+
+const (
+	tabSortIndex  = "index"
+	tabSortRecent = "recent"
+)
+
+// listExtensionTabs lists the tabs of every browser matching the --browser
+// filter through the extension bridges. Release channels share their
+// family's bridge, so "all" asks the Safari and Chrome bridges once each.
+func listExtensionTabs(ctx context.Context, browser string) ([]osascript.TabEntry, error) {
+	target, err := parseOptionalBrowserTarget(browser)
+	if err != nil {
+		return nil, usageError(err)
+	}
+	targets := []bridge.BrowserTarget{target}
+	if target == "" {
+		targets = []bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome}
+	}
+	var tabs []osascript.TabEntry
+	for _, target := range targets {
+		entries, err := listBridgeTabsFunc(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		tabs = append(tabs, entries...)
+	}
+	return tabs, nil
+}
+
+// listTabsByRecent lists tabs newest first using the extension bridge's
+// open times. AppleScript listings carry no open times, so when the bridge
+// is unavailable or reports none the sort fails rather than silently
+// returning index order.
+func listTabsByRecent(ctx context.Context, browser string) ([]osascript.TabEntry, []string, error) {
+	tabs, err := listExtensionTabs(ctx, browser)
+	if err != nil {
+		if ExitCode(err) == ExitCodeUsage {
+			return nil, nil, err
+		}
+		return nil, nil, unavailableError(fmt.Errorf("--sort recent: %w; tab open times come only from the extension bridge", err))
+	}
+	if !sortTabsByOpenedAt(tabs) {
+		return nil, nil, unavailableError(fmt.Errorf("--sort recent: the extension bridge reported no tab open times"))
+	}
+	return tabs, nil, nil
+}
+
+// sortTabsByOpenedAt orders tabs newest first, keeping tabs without an open
+// time last in their listed order. It reports false, leaving tabs
+// untouched, when no tab has an open time.
+func sortTabsByOpenedAt(tabs []osascript.TabEntry) bool {
+	known := false
+	for _, tab := range tabs {
+		if !tab.OpenedAt.IsZero() {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return false
+	}
+	sort.SliceStable(tabs, func(i, j int) bool {
+		if tabs[i].OpenedAt.IsZero() || tabs[j].OpenedAt.IsZero() {
+			return !tabs[i].OpenedAt.IsZero() && tabs[j].OpenedAt.IsZero()
+		}
+		return tabs[i].OpenedAt.After(tabs[j].OpenedAt)
+	})
+	return true
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func stubBridgeTabs(t *testing.T, list func(context.Context, bridge.BrowserTarget) ([]osascript.TabEntry, error)) {
+	t.Helper()
+	previous := listBridgeTabsFunc
+	listBridgeTabsFunc = list
+	t.Cleanup(func() {
+		listBridgeTabsFunc = previous
+	})
+}
+
+func TestListTabsSortRecentUsesBridgeOpenTimes(t *testing.T) {
+	opened := time.Date(2026, time.February, 15, 12, 0, 0, 0, time.UTC)
+	var queried []bridge.BrowserTarget
+	stubBridgeTabs(t, func(_ context.Context, target bridge.BrowserTarget) ([]osascript.TabEntry, error) {
+		queried = append(queried, target)
+		if target == bridge.BrowserTargetSafari {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Unknown", URL: "https://a.example"}}, nil
+		}
+		return []osascript.TabEntry{
+			{Browser: "chrome", WindowIndex: 1, TabIndex: 1, Title: "Older", URL: "https://b.example", OpenedAt: opened},
+			{Browser: "chrome", WindowIndex: 1, TabIndex: 2, Title: "Newer", URL: "https://c.example", OpenedAt: opened.Add(time.Minute)},
+		}, nil
+	})
+	restore := stubListSources(func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+		t.Fatalf("AppleScript listing should not run when the bridge reports open times")
+		return nil, nil, nil
+	}, nil)
+	defer restore()
+
+	payload, stderr, err := runRootCommandToFile(t, "list", "tabs", "--sort", "recent")
+	if err != nil {
+		t.Fatalf("list tabs --sort recent returned error: %v", err)
+	}
+	if len(queried) != 2 {
+		t.Fatalf("expected the Safari and Chrome bridges to be queried once each, got %v", queried)
+	}
+	expected := "# Open Tabs\n" +
+		"- chrome w1:t2 - Newer - https://c.example\n" +
+		"- chrome w1:t1 - Older - https://b.example\n" +
+		"- safari w1:t1 - Unknown - https://a.example\n"
+	if string(payload) != expected {
+		t.Fatalf("unexpected listing:\n%s", payload)
+	}
+	if stderr != "" {
+		t.Fatalf("expected no warnings, got %q", stderr)
+	}
+}
+
+func TestListTabsSortRecentFailsWithoutOpenTimes(t *testing.T) {
+	restore := stubListSources(func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+		t.Fatalf("--sort recent should not fall back to the AppleScript listing")
+		return nil, nil, nil
+	}, nil)
+	defer restore()
+
+	stubBridgeTabs(t, func(context.Context, bridge.BrowserTarget) ([]osascript.TabEntry, error) {
+		return nil, errors.New("Chrome tab listing failed: bun not available")
+	})
+	_, _, err := runRootCommand("list", "tabs", "--sort", "recent", "--browser", "chrome")
+	if ExitCode(err) != ExitCodeUnavailable {
+		t.Fatalf("expected an unavailable bridge to fail --sort recent, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "bun not available") {
+		t.Fatalf("expected the bridge failure in the error, got %v", err)
+	}
+
+	stubBridgeTabs(t, func(context.Context, bridge.BrowserTarget) ([]osascript.TabEntry, error) {
+		return []osascript.TabEntry{{Browser: "chrome", WindowIndex: 1, TabIndex: 1, Title: "B", URL: "https://b.example"}}, nil
+	})
+	_, _, err = runRootCommand("list", "tabs", "--sort", "recent", "--browser", "chrome")
+	if ExitCode(err) != ExitCodeUnavailable || !strings.Contains(err.Error(), "no tab open times") {
+		t.Fatalf("expected tabs without open times to fail --sort recent, got %v", err)
+	}
+
+	if _, _, err := runRootCommand("list", "tabs", "--sort", "oldest"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected unsupported --sort to be a usage error, got %v", err)
+	}
+}
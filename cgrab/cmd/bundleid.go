@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+// bundleIDPattern accepts reverse-DNS identifiers: at least two dot-separated
+// segments of letters, digits, hyphens, and (for older apps) underscores.
+var bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+
+// maxBundleIDSuggestions caps the running apps listed when --verify-bundle
+// finds no match.
+const maxBundleIDSuggestions = 3
+
+// validateBundleID rejects values that cannot be a bundle identifier (e.g.
+// an app name passed to --bundle-id) before anything is activated.
+func validateBundleID(flag string, bundleID string) error {
+	if bundleIDPattern.MatchString(bundleID) {
+		return nil
+	}
+	return fmt.Errorf("%s %q is not a bundle identifier (expected reverse-DNS form such as com.apple.finder; use --app for app names)", flag, bundleID)
+}
+
+// verifyBundleID checks bundleID against the running apps and returns the
+// matching app's identifier as System Events reports it. When no app
+// matches, the error suggests the closest running bundle identifiers.
+func verifyBundleID(apps []osascript.AppEntry, bundleID string) (string, error) {
+	for _, app := range apps {
+		if strings.EqualFold(app.BundleIdentifier, bundleID) {
+			return app.BundleIdentifier, nil
+		}
+	}
+	message := fmt.Sprintf("no running app has bundle id %q", bundleID)
+	if suggestions := suggestBundleIDs(apps, bundleID); len(suggestions) > 0 {
+		message += "; did you mean: " + strings.Join(suggestions, ", ")
+	}
+	return "", noMatchError(fmt.Errorf("%s", message))
+}
+
+// suggestBundleIDs returns up to maxBundleIDSuggestions running bundle
+// identifiers that contain bundleID's last segment or are within a few edits
+// of it, closest first.
+func suggestBundleIDs(apps []osascript.AppEntry, bundleID string) []string {
+	needle := strings.ToLower(bundleID)
+	lastSegment := needle[strings.LastIndex(needle, ".")+1:]
+	maxDistance := max(3, len(needle)/4)
+
+	type candidate struct {
+		label    string
+		distance int
+	}
+	var candidates []candidate
+	seen := map[string]bool{}
+	for _, app := range apps {
+		id := strings.ToLower(app.BundleIdentifier)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		distance := editDistance(needle, id)
+		if distance > maxDistance && !strings.Contains(id, lastSegment) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			label:    fmt.Sprintf("%s (%s)", app.BundleIdentifier, app.AppName),
+			distance: distance,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	suggestions := make([]string, 0, maxBundleIDSuggestions)
+	for _, match := range candidates {
+		if len(suggestions) == maxBundleIDSuggestions {
+			break
+		}
+		suggestions = append(suggestions, match.label)
+	}
+	return suggestions
+}
+
+// editDistance is the Levenshtein distance between a and b in bytes, which
+// is enough for ASCII bundle identifiers.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestValidateBundleIDRejectsMalformedValues(t *testing.T) {
+	for _, valid := range []string{"com.apple.finder", "com.google.Chrome.beta", "org.mozilla.firefox", "com.example.my-app", "com.example.old_app"} {
+		if err := validateBundleID("--bundle-id", valid); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"Finder", "com.apple.", "com apple finder", ".com.apple", "com..apple"} {
+		if err := validateBundleID("--bundle-id", invalid); err == nil {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestVerifyBundleIDSuggestsClosestRunningApps(t *testing.T) {
+	apps := []osascript.AppEntry{
+		{AppName: "Finder", BundleIdentifier: "com.apple.finder"},
+		{AppName: "Notes", BundleIdentifier: "com.apple.Notes"},
+		{AppName: "Slack", BundleIdentifier: "com.tinyspeck.slackmacgap"},
+	}
+
+	canonical, err := verifyBundleID(apps, "com.apple.notes")
+	if err != nil || canonical != "com.apple.Notes" {
+		t.Fatalf("expected case-insensitive match, got %q err=%v", canonical, err)
+	}
+
+	_, err = verifyBundleID(apps, "com.apple.findr")
+	if err == nil || ExitCode(err) != ExitCodeNoMatch {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "did you mean: com.apple.finder (Finder)") || strings.Contains(err.Error(), "slack") {
+		t.Fatalf("unexpected suggestions: %v", err)
+	}
+}
+
+func TestCaptureVerifyBundleFailsBeforeActivation(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder"}}, nil, nil
+	})
+	defer restore()
+	activated := false
+	previousActivate := activateAppByBundleFunc
+	t.Cleanup(func() { activateAppByBundleFunc = previousActivate })
+	activateAppByBundleFunc = func(context.Context, string) error {
+		activated = true
+		return nil
+	}
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte("# Finder\n"), nil
+	}
+
+	if _, _, err := runRootCommand("capture", "--bundle-id", "Finder"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for malformed bundle id, got %v", err)
+	}
+	_, _, err := runRootCommand("capture", "--bundle-id", "com.apple.findr", "--verify-bundle")
+	if ExitCode(err) != ExitCodeNoMatch || activated {
+		t.Fatalf("expected no-match error before activation, got %v (activated=%v)", err, activated)
+	}
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--verify-bundle"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for --verify-bundle without --bundle-id, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+// noHostLabel groups tabs whose URL has no host, such as about:blank or
+// file:// pages.
+const noHostLabel = "(no host)"
+
+type hostGroup struct {
+	host   string
+	titles []string
+}
+
+// groupTabsByHost buckets tab titles by URL host, largest group first and
+// hosts alphabetical within the same count. Titles keep the tab order.
+func groupTabsByHost(tabs []osascript.TabEntry) []hostGroup {
+	indexByHost := map[string]int{}
+	var groups []hostGroup
+	for _, tab := range tabs {
+		host := urlHost(tab.URL)
+		if host == "" {
+			host = noHostLabel
+		}
+		index, ok := indexByHost[host]
+		if !ok {
+			index = len(groups)
+			indexByHost[host] = index
+			groups = append(groups, hostGroup{host: host})
+		}
+		groups[index].titles = append(groups[index].titles, tab.Title)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].titles) != len(groups[j].titles) {
+			return len(groups[i].titles) > len(groups[j].titles)
+		}
+		return groups[i].host < groups[j].host
+	})
+	return groups
+}
+
+// renderTabsByHost prints the --by-host summary: a {host: [titles]} object
+// for --format json, or one heading per host with its tab count otherwise.
+func renderTabsByHost(format string, tabs []osascript.TabEntry) ([]byte, error) {
+	groups := groupTabsByHost(tabs)
+	if format == formatJSON {
+		byHost := make(map[string][]string, len(groups))
+		for _, group := range groups {
+			byHost[group.host] = group.titles
+		}
+		return json.MarshalIndent(byHost, "", "  ")
+	}
+
+	if len(groups) == 0 {
+		return []byte("No tabs found.\n"), nil
+	}
+	lines := []string{"# Tabs by Host"}
+	for _, group := range groups {
+		lines = append(lines, "", fmt.Sprintf("## %s (%d)", group.host, len(group.titles)))
+		for _, title := range group.titles {
+			lines = append(lines, "- "+title)
+		}
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
@@ -3,19 +3,26 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
 	"github.com/anthonylu23/context_grabber/cgrab/internal/config"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/eventlog"
 	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
 	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/textdiff"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -26,7 +33,15 @@ var (
 	activateAppByBundleFunc  = osascript.ActivateAppByBundleID
 	captureBrowserFunc       = bridge.CaptureBrowser
 	captureDesktopFunc       = bridge.CaptureDesktop
+	renderURLToPDFFunc       = bridge.RenderURLToPDF
 	ensureHostAppRunningFunc = bridge.EnsureHostAppRunning
+	safariPageTextFunc       = osascript.SafariPageText
+	frontmostAppFunc         = osascript.FrontmostApp
+	frontmostBrowserFunc     = osascript.FrontmostBrowser
+	browserWindowCountFunc   = osascript.BrowserWindowCount
+	openTabFunc              = osascript.OpenTab
+	closeTabFunc             = osascript.CloseTab
+	openCaptureFileFunc      = openCaptureFile
 	nowFunc                  = time.Now
 )
 
@@ -34,76 +49,311 @@ func newCaptureCommand(global *globalOptions) *cobra.Command {
 	var focused bool
 	var tabReference string
 	var urlMatch string
+	var pageURL string
 	var titleMatch string
+	var chromeApp string
 	var appName string
+	var appIndex int
 	var nameMatch string
+	var appRegex string
 	var bundleID string
+	var bundleIDPrefix string
+	var focusedApp bool
 	var browser string
 	var method string
 	var timeoutMs int
+	var frontMatter bool
+	var minContentLength int
+	var ignoreUnreachable bool
+	var filter tabFilter
+	var urls urlCleaner
+	var first bool
+	var raw bool
+	var noSave bool
+	var targetOrder string
+	var saveAs string
+	var overwrite bool
+	var batchFile string
+	var concurrency int
+	var openFile bool
+	var includeBounds bool
+	var verifyBundle bool
+	var timings bool
+	var maxChars int
+	var maxTokens int
+	var normalizeWhitespace bool
+	var noHostLaunch bool
+	var alsoMetadata bool
+	var selection bool
+	var revealFile bool
+	var showDiff bool
+	var diffOnly bool
+	var dryRun bool
+	var hash bool
+	var sidecar bool
 
 	captureCmd := &cobra.Command{
 		Use:   "capture",
 		Short: "Capture browser or desktop context",
 		Example: "  cgrab capture --focused\n" +
 			"  cgrab capture --tab w1:t2 --browser safari\n" +
+			"  cgrab capture --url https://example.com/docs --browser chrome\n" +
 			"  cgrab capture --app Finder --method auto\n" +
-			"  cgrab capture --app --name-match xcode --format json",
+			"  cgrab capture --app --name-match xcode --format json\n" +
+			"  cgrab capture --batch targets.json\n" +
+			"  cgrab capture last --clipboard",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
-				return fmt.Errorf("capture does not accept positional args: %s", strings.Join(args, " "))
+				return usageError(fmt.Errorf("capture does not accept positional args: %s", strings.Join(args, " ")))
+			}
+			if err := applyCaptureEnvDefaults(cmd.LocalNonPersistentFlags()); err != nil {
+				return usageError(err)
 			}
 
 			request := captureRequest{
-				focused:      focused,
-				tabReference: strings.TrimSpace(tabReference),
-				urlMatch:     strings.TrimSpace(urlMatch),
-				titleMatch:   strings.TrimSpace(titleMatch),
-				appName:      strings.TrimSpace(appName),
-				nameMatch:    strings.TrimSpace(nameMatch),
-				bundleID:     strings.TrimSpace(bundleID),
-				browser:      strings.TrimSpace(browser),
-				method:       strings.ToLower(strings.TrimSpace(method)),
-				timeoutMs:    timeoutMs,
-				outputFormat: global.format,
+				focused:             focused,
+				tabReference:        strings.TrimSpace(tabReference),
+				urlMatch:            strings.TrimSpace(urlMatch),
+				pageURL:             strings.TrimSpace(pageURL),
+				titleMatch:          strings.TrimSpace(titleMatch),
+				chromeApp:           strings.TrimSpace(chromeApp),
+				appName:             strings.TrimSpace(appName),
+				appIndex:            appIndex,
+				nameMatch:           strings.TrimSpace(nameMatch),
+				appRegex:            strings.TrimSpace(appRegex),
+				bundleID:            strings.TrimSpace(bundleID),
+				bundleIDPrefix:      strings.TrimSpace(bundleIDPrefix),
+				focusedApp:          focusedApp,
+				browser:             strings.TrimSpace(browser),
+				method:              normalizeBrowserMethod(method),
+				timeoutMs:           timeoutMs,
+				outputFormat:        global.format,
+				frontMatter:         frontMatter,
+				minContentLength:    minContentLength,
+				minContentLengthSet: minContentLengthSet(cmd.Flags()),
+				ignoreUnreachable:   ignoreUnreachable,
+				filter:              filter,
+				urls:                urls,
+				first:               first,
+				raw:                 raw,
+				targetOrder:         strings.TrimSpace(targetOrder),
+				includeBounds:       includeBounds,
+				verifyBundle:        verifyBundle,
+				timings:             timings,
+				maxChars:            maxChars,
+				maxTokens:           maxTokens,
+				normalizeWhitespace: normalizeWhitespace,
+				noHostLaunch:        noHostLaunch,
+				alsoMetadata:        alsoMetadata,
+				selection:           selection,
+			}
+
+			if batchFile = strings.TrimSpace(batchFile); batchFile != "" {
+				for _, name := range captureBatchExclusiveFlags {
+					if cmd.Flags().Changed(name) {
+						return usageError(fmt.Errorf("--batch cannot be combined with --%s; set selectors per entry in the batch file", name))
+					}
+				}
+				if global.clipboard || strings.TrimSpace(global.outputFile) != "" {
+					return usageError(fmt.Errorf("--batch cannot be combined with --file or --clipboard; set output per entry in the batch file"))
+				}
+				if concurrency < 1 {
+					return usageError(fmt.Errorf("--concurrency must be at least 1"))
+				}
+				entries, err := readCaptureBatch(batchFile)
+				if err != nil {
+					return usageError(err)
+				}
+				summary := runCaptureBatch(cmd.Context(), entries, request, concurrency, global.writeOptions, global.warnings(cmd.ErrOrStderr()))
+				rendered, err := renderCaptureBatchSummary(summary, global.format)
+				if err != nil {
+					return err
+				}
+				if err := output.Write(cmd.Context(), rendered, "", false, global.writeOptions(global.format)...); err != nil {
+					return err
+				}
+				if summary.Failed > 0 {
+					return fmt.Errorf("%d of %d batch captures failed", summary.Failed, len(entries))
+				}
+				return nil
 			}
 
+			if cmd.Flags().Changed("concurrency") {
+				return usageError(fmt.Errorf("--concurrency requires --batch"))
+			}
 			mode, err := request.validate()
 			if err != nil {
-				return err
+				return usageError(err)
+			}
+			if request.raw {
+				request.outputFormat = formatJSON
+			}
+			if noSave && strings.TrimSpace(global.outputFile) != "" {
+				return usageError(fmt.Errorf("--no-save cannot be combined with --file"))
+			}
+			if noSave && !global.clipboard {
+				return usageError(fmt.Errorf("--no-save requires --clipboard"))
+			}
+			if request.method == browserMethodURLPDF && global.clipboard {
+				return usageError(fmt.Errorf("--method url-pdf writes a PDF file and cannot be combined with --clipboard"))
+			}
+			saveAs = strings.TrimSpace(saveAs)
+			if saveAs != "" && (strings.TrimSpace(global.outputFile) != "" || noSave) {
+				return usageError(fmt.Errorf("--save-as cannot be combined with --file or --no-save"))
+			}
+			if saveAs != "" && sanitizeCaptureName(saveAs) == "" {
+				return usageError(fmt.Errorf("--save-as %q does not contain any usable file name characters", saveAs))
+			}
+			if overwrite && saveAs == "" {
+				return usageError(fmt.Errorf("--overwrite requires --save-as"))
+			}
+			if (hash || sidecar) && request.method == browserMethodURLPDF {
+				return usageError(fmt.Errorf("--hash and --sidecar cannot be combined with --method url-pdf"))
+			}
+			if hash && global.envelope {
+				return usageError(fmt.Errorf("--hash cannot be combined with --envelope, which would wrap the hashed bytes"))
+			}
+			if sidecar && noSave {
+				return usageError(fmt.Errorf("--sidecar writes next to the saved capture and cannot be combined with --no-save"))
+			}
+			if openFile && revealFile {
+				return usageError(fmt.Errorf("--open and --reveal cannot be combined"))
+			}
+			showDiff = showDiff || diffOnly
+			if showDiff {
+				if request.outputFormat != formatMarkdown && request.outputFormat != formatJSON {
+					return usageError(fmt.Errorf("--diff requires --format markdown or json"))
+				}
+				if request.method == browserMethodURLPDF || noSave {
+					return usageError(fmt.Errorf("--diff cannot be combined with --method url-pdf or --no-save"))
+				}
 			}
 
-			stderr := cmd.ErrOrStderr()
-			var rendered []byte
-			switch mode {
-			case captureModeBrowser:
-				rendered, err = runBrowserCapture(cmd.Context(), request, stderr)
-			case captureModeDesktop:
-				rendered, err = runDesktopCapture(cmd.Context(), request)
-			default:
-				err = fmt.Errorf("unsupported capture mode")
+			if dryRun {
+				if mode != captureModeBrowser || request.method == browserMethodURLPDF {
+					return usageError(fmt.Errorf("--dry-run applies only to browser capture without --method url-pdf"))
+				}
+				for _, name := range captureDryRunExclusiveFlags {
+					if cmd.Flags().Changed(name) {
+						return usageError(fmt.Errorf("--dry-run cannot be combined with --%s", name))
+					}
+				}
+				if global.clipboard {
+					return usageError(fmt.Errorf("--dry-run cannot be combined with --clipboard"))
+				}
+				report, err := planBrowserCapture(cmd.Context(), request, global.warnings(cmd.ErrOrStderr()))
+				if err != nil {
+					return err
+				}
+				rendered, err := renderCaptureDryRun(report, request.outputFormat)
+				if err != nil {
+					return err
+				}
+				return output.Write(cmd.Context(), rendered, strings.TrimSpace(global.outputFile), false, global.writeOptions(request.outputFormat)...)
 			}
+
+			stderr := global.warnings(cmd.ErrOrStderr())
+			if outputFile := strings.TrimSpace(global.outputFile); outputFile != "" {
+				fmt.Fprint(stderr, captureFileExtensionWarning(outputFile, request.outputFormat, request.method))
+			}
+			pdfOutputFile := ""
+			if request.method == browserMethodURLPDF {
+				pdfOutputFile = strings.TrimSpace(global.outputFile)
+				if saveAs != "" {
+					if pdfOutputFile, err = resolveNamedCaptureOutputFilePath(saveAs, captureOutputExtension(request.outputFormat, request.method), overwrite); err != nil {
+						return err
+					}
+				}
+			}
+			rendered, pdfPath, err := executeCapture(cmd.Context(), request, mode, pdfOutputFile, stderr)
 			if err != nil {
 				return err
 			}
+			if pdfPath != "" {
+				fmt.Fprintf(global.warnings(cmd.OutOrStdout()), "Saved capture to %s\n", pdfPath)
+				openWrittenCapture(cmd.Context(), pdfPath, openFile, revealFile, stderr)
+				return nil
+			}
+
+			if hash {
+				if rendered, err = addCaptureHash(rendered, request.outputFormat, !global.pretty); err != nil {
+					return err
+				}
+			}
 
 			outputFile := strings.TrimSpace(global.outputFile)
+			if noSave {
+				return output.Write(cmd.Context(), rendered, "", true, append(global.writeOptions(request.outputFormat), output.WithoutStdout())...)
+			}
 			autoSave := false
+			if outputFile == "" && saveAs != "" {
+				extension := captureOutputExtension(request.outputFormat, request.method)
+				if global.gzip {
+					extension += gzipExtension
+				}
+				namedOutputFile, pathErr := resolveNamedCaptureOutputFilePath(saveAs, extension, overwrite)
+				if pathErr != nil {
+					return pathErr
+				}
+				outputFile = namedOutputFile
+				autoSave = true
+			}
 			if outputFile == "" {
-				defaultOutputFile, pathErr := resolveDefaultCaptureOutputFilePath(request.outputFormat)
+				defaultOutputFile, pathErr := resolveDefaultCaptureOutputFilePath(request.outputFormat, request.method)
 				if pathErr != nil {
 					return pathErr
 				}
 				outputFile = defaultOutputFile
+				if global.gzip {
+					outputFile += gzipExtension
+				}
 				autoSave = true
 			}
 
-			if err := output.Write(cmd.Context(), rendered, outputFile, global.clipboard); err != nil {
+			// Look up the diff baseline before saving, so the new capture
+			// is not found as its own predecessor.
+			targetKey := captureTargetKey(request, mode)
+			var previous historyEntry
+			hasPrevious := false
+			if showDiff {
+				captureDir, dirErr := resolveCaptureDir()
+				if dirErr != nil {
+					return dirErr
+				}
+				if previous, hasPrevious, err = findPreviousCapture(captureDir, targetKey, request.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			writeErr := output.Write(cmd.Context(), rendered, outputFile, global.clipboard, append(global.writeOptions(request.outputFormat), output.WithChecksumFile(sidecar))...)
+			if err := global.clipboardResult(writeErr, cmd.ErrOrStderr()); err != nil {
 				return err
 			}
-			if autoSave {
-				fmt.Fprintf(cmd.OutOrStdout(), "Saved capture to %s\n", outputFile)
+			if autoSave && !diffOnly {
+				fmt.Fprintf(global.warnings(cmd.OutOrStdout()), "Saved capture to %s\n", outputFile)
+			}
+			if autoSave && saveAs == "" {
+				if err := recordCaptureTarget(filepath.Dir(outputFile), outputFile, targetKey); err != nil {
+					fmt.Fprintf(stderr, "warning: %v\n", err)
+				}
 			}
+			if showDiff {
+				if !hasPrevious {
+					fmt.Fprintln(stderr, "warning: no previous capture of this target to diff against")
+				} else {
+					diff, err := renderCaptureDiff(previous, outputFile)
+					if errors.Is(err, textdiff.ErrTooLarge) {
+						// The capture is saved; only the comparison is skipped.
+						fmt.Fprintf(stderr, "warning: --diff skipped: %v\n", err)
+					} else if err != nil {
+						return err
+					} else if diff == "" {
+						fmt.Fprintf(stderr, "No changes since %s\n", previous.Path)
+					}
+					fmt.Fprint(cmd.OutOrStdout(), diff)
+				}
+			}
+			openWrittenCapture(cmd.Context(), outputFile, openFile, revealFile, stderr)
 			return nil
 		},
 	}
@@ -111,19 +361,125 @@ func newCaptureCommand(global *globalOptions) *cobra.Command {
 	captureCmd.Flags().BoolVar(&focused, "focused", false, "focused browser tab")
 	captureCmd.Flags().StringVar(&tabReference, "tab", "", "tab by window:tab index (e.g. 1:2 or w1:t2)")
 	captureCmd.Flags().StringVar(&urlMatch, "url-match", "", "match tab by URL substring")
+	captureCmd.Flags().StringVar(&pageURL, "url", "", "open this http(s) URL in a temporary background tab, capture it, then close the tab")
 	captureCmd.Flags().StringVar(&titleMatch, "title-match", "", "match tab by title substring")
-	captureCmd.Flags().StringVar(&appName, "app", "", "app by exact name")
+	captureCmd.Flags().StringVar(&chromeApp, "chrome-app", "", "Chrome web app (PWA) window by app name, as marked in list tabs")
+	captureCmd.Flags().StringVar(&appName, "app", "", "app by exact name (case- and whitespace-insensitive against running apps)")
+	captureCmd.Flags().IntVar(&appIndex, "app-index", 0, "with --app, capture the app's Nth window (1-based, front to back) instead of its frontmost one")
 	captureCmd.Flags().StringVar(&nameMatch, "name-match", "", "match app by name substring")
+	captureCmd.Flags().StringVar(&appRegex, "app-regex", "", "match app by regular expression over the app name")
 	captureCmd.Flags().StringVar(&bundleID, "bundle-id", "", "app by bundle identifier")
-	captureCmd.Flags().StringVar(&browser, "browser", "", "browser: safari or chrome")
-	captureCmd.Flags().StringVar(&method, "method", "auto", "method: auto|applescript|extension|ax|ocr")
+	captureCmd.Flags().BoolVar(&verifyBundle, "verify-bundle", false, "with --bundle-id, fail fast with suggestions when no running app has that bundle id")
+	captureCmd.Flags().StringVar(&bundleIDPrefix, "bundle-id-prefix", "", "app by bundle identifier prefix (e.g. com.apple.)")
+	captureCmd.Flags().BoolVar(&focusedApp, "focused-app", false, "frontmost desktop app")
+	captureCmd.Flags().StringVar(&browser, "browser", "", "browser: all (default), safari, safari-tp, chrome, chrome-beta, chrome-dev, or chrome-canary")
+	captureCmd.Flags().StringVar(&method, "method", "auto", "method: auto|applescript|extension|url-pdf (alias pdf; re-renders the URL logged out in headless Chrome)|ax|ocr")
 	captureCmd.Flags().IntVar(&timeoutMs, "timeout-ms", 1200, "timeout in milliseconds")
-
+	captureCmd.Flags().BoolVar(&frontMatter, "front-matter", false, "prepend YAML provenance front matter to markdown browser captures")
+	captureCmd.Flags().IntVar(&minContentLength, "min-content-length", 0, "treat browser captures shorter than N characters as failures (0 disables; overrides the min-content config setting)")
+	captureCmd.Flags().BoolVar(&ignoreUnreachable, "ignore-unreachable", false, "when every browser bridge is unreachable, output metadata-only title/URL markdown with a warning instead of failing")
+	filter.registerDomains(captureCmd)
+	urls.register(captureCmd)
+	captureCmd.Flags().BoolVar(&includeBounds, "include-bounds", false, "desktop only: add the captured window's {x,y,width,height} to JSON output")
+	captureCmd.Flags().BoolVar(&timings, "timings", false, "add activateMs, captureMs, and totalMs phase durations to JSON/YAML output")
+	captureCmd.Flags().IntVar(&maxChars, "max-chars", 0, "browser only: trim captured markdown to N characters at a paragraph boundary and append [truncated] (0 disables)")
+	captureCmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "browser only: like --max-chars with a budget of N tokens, estimated as 4 characters each (0 disables)")
+	captureCmd.Flags().BoolVar(&normalizeWhitespace, "normalize-whitespace", true, "markdown browser captures: trim trailing spaces, collapse 3+ blank lines to 2, and end with one newline (--normalize-whitespace=false keeps the bridge output as is)")
+	captureCmd.Flags().BoolVar(&noHostLaunch, "no-host-launch", false, "browser capture: do not auto-launch the host app (with --method applescript, capture needs no host app at all)")
+	captureCmd.Flags().BoolVar(&alsoMetadata, "also-metadata", false, "browser capture: prepend a \"> Source: <title> — <url>\" line to the captured markdown, whatever the extraction method")
+	captureCmd.Flags().BoolVar(&selection, "selection", false, "browser capture: capture only the text selected in the page (extractionMethod \"selection\"), or the full page with a warning when nothing is selected")
+	captureCmd.Flags().BoolVar(&hash, "hash", false, "annotate the capture with the SHA-256 of its rendered bytes (a trailing <!-- sha256: ... --> line in markdown, a sha256 field in JSON/YAML)")
+	captureCmd.Flags().BoolVar(&sidecar, "sidecar", false, "also write <file>.sha256 next to the saved capture, checkable with shasum -a 256 -c")
+	captureCmd.Flags().BoolVar(&raw, "raw", false, "print the complete bridge capture attempt as JSON (implies --format json)")
+	captureCmd.Flags().BoolVar(&noSave, "no-save", false, "with --clipboard, copy the capture without auto-saving it or printing to stdout")
+	captureCmd.Flags().StringVar(&targetOrder, "target-order", "", "with --focused, comma-separated browsers to try in order (e.g. chrome,safari)")
+	captureCmd.Flags().StringVar(&saveAs, "save-as", "", "auto-save under this name in the capture directory instead of a timestamp")
+	captureCmd.Flags().BoolVar(&openFile, "open", false, "open the saved capture file in its default app")
+	captureCmd.Flags().BoolVar(&revealFile, "reveal", false, "reveal the saved capture file in Finder")
+	captureCmd.Flags().BoolVar(&showDiff, "diff", false, "after the capture, print a unified diff against the previous auto-saved capture of the same target")
+	captureCmd.Flags().BoolVar(&diffOnly, "diff-only", false, "like --diff, but print only the diff (no \"Saved capture to\" line)")
+	captureCmd.Flags().BoolVar(&dryRun, "dry-run", false, "resolve the browser target and ping its extension bridge to report the expected extraction method, without capturing")
+	captureCmd.Flags().BoolVar(&overwrite, "overwrite", false, "with --save-as, replace an existing file instead of adding a numeric suffix")
+	captureCmd.Flags().StringVar(&batchFile, "batch", "", "capture every entry of a JSON array of capture requests, each into its own output file")
+	captureCmd.Flags().IntVar(&concurrency, "concurrency", 1, "with --batch, run up to N captures at once (captures that activate apps or tabs may steal focus from each other)")
+	captureCmd.Flags().BoolVar(&first, "first", false, "with --title-match or --app-regex, take the first match instead of failing when several match")
+
+	captureCmd.AddCommand(newCaptureLastCommand(global))
+	captureCmd.AddCommand(newCaptureOpenCommand(global))
 	return captureCmd
 }
 
 type captureMode string
 
+// captureBatchExclusiveFlags are per-capture flags that --batch rejects
+// because each batch entry carries its own selector and output.
+var captureBatchExclusiveFlags = []string{
+	"focused", "tab", "url-match", "url", "title-match", "chrome-app", "app", "app-index", "name-match", "app-regex",
+	"bundle-id", "bundle-id-prefix", "focused-app", "browser", "first", "raw",
+	"no-save", "target-order", "save-as", "overwrite", "open", "reveal",
+	"diff", "diff-only", "dry-run", "hash", "sidecar",
+}
+
+// eventFields describes the request for the --log-file event log. Only the
+// selectors that were set are included.
+func (r captureRequest) eventFields(mode captureMode) map[string]any {
+	fields := map[string]any{
+		"mode":   string(mode),
+		"method": r.method,
+	}
+	selectors := map[string]string{
+		"tab":            r.tabReference,
+		"urlMatch":       r.urlMatch,
+		"url":            r.pageURL,
+		"titleMatch":     r.titleMatch,
+		"chromeApp":      r.chromeApp,
+		"app":            r.appName,
+		"nameMatch":      r.nameMatch,
+		"appRegex":       r.appRegex,
+		"bundleId":       r.bundleID,
+		"bundleIdPrefix": r.bundleIDPrefix,
+		"browser":        r.browser,
+	}
+	for key, value := range selectors {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	if r.focused {
+		fields["focused"] = true
+	}
+	if r.focusedApp {
+		fields["focusedApp"] = true
+	}
+	return fields
+}
+
+// browserMethodURLPDF is the --method value that re-renders a tab's URL to a
+// PDF in headless Chrome (the live tab and its session are not printed).
+const browserMethodURLPDF = "url-pdf"
+
+// browserMethodPDF is the original name of browserMethodURLPDF, kept as an
+// alias.
+const browserMethodPDF = "pdf"
+
+// normalizeBrowserMethod lowercases a --method value and resolves aliases.
+func normalizeBrowserMethod(method string) string {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == browserMethodPDF {
+		return browserMethodURLPDF
+	}
+	return method
+}
+
+// urlPDFSafariError reports that --method url-pdf cannot render for a
+// Safari-family browser.
+func urlPDFSafariError(displayName string) error {
+	return fmt.Errorf(
+		"--method url-pdf re-renders the URL in headless Chrome and does not support %s; select a Chrome tab or pass --browser chrome",
+		displayName,
+	)
+}
+
 const (
 	captureModeBrowser captureMode = "browser"
 	captureModeDesktop captureMode = "desktop"
@@ -133,21 +489,65 @@ type captureRequest struct {
 	focused      bool
 	tabReference string
 	urlMatch     string
-	titleMatch   string
-	appName      string
-	nameMatch    string
-	bundleID     string
-	browser      string
-	method       string
-	timeoutMs    int
-	outputFormat string
+	// pageURL is the --url address opened in a temporary tab for capture.
+	pageURL          string
+	titleMatch       string
+	chromeApp        string
+	appName          string
+	appIndex         int
+	nameMatch        string
+	appRegex         string
+	bundleID         string
+	bundleIDPrefix   string
+	focusedApp       bool
+	browser          string
+	method           string
+	timeoutMs        int
+	outputFormat     string
+	frontMatter      bool
+	minContentLength int
+	// minContentLengthSet reports a --min-content-length from the command
+	// line or environment, which overrides the min-content config setting.
+	minContentLengthSet bool
+	// ignoreUnreachable emits a metadata-only capture instead of failing
+	// when every browser bridge is unreachable.
+	ignoreUnreachable bool
+	filter            tabFilter
+	urls              urlCleaner
+	first             bool
+	raw               bool
+	targetOrder       string
+	includeBounds     bool
+	// timings adds per-phase durations to JSON and YAML output.
+	timings bool
+	// maxChars and maxTokens cap the captured markdown; see
+	// captureCharBudget.
+	maxChars  int
+	maxTokens int
+	// normalizeWhitespace tidies markdown-format browser output; JSON and
+	// YAML keep the bridge's markdown verbatim.
+	normalizeWhitespace bool
+	// verifyBundle checks --bundle-id against the running apps before
+	// activating it.
+	verifyBundle bool
+	// noHostLaunch skips auto-launching the ContextGrabber app before
+	// browser capture.
+	noHostLaunch bool
+	// alsoMetadata prepends a "> Source:" title/URL blockquote to browser
+	// captures.
+	alsoMetadata bool
+	// selection captures only the page's selected text.
+	selection bool
 }
 
 func (r captureRequest) validate() (captureMode, error) {
 	if r.timeoutMs <= 0 {
 		return "", fmt.Errorf("timeout must be positive")
 	}
-	if r.outputFormat != formatJSON && r.outputFormat != formatMarkdown {
+	if r.minContentLength < 0 {
+		return "", fmt.Errorf("--min-content-length cannot be negative")
+	}
+	if r.outputFormat != formatJSON && r.outputFormat != formatYAML && r.outputFormat != formatMarkdown {
 		return "", fmt.Errorf("unsupported --format value %q", r.outputFormat)
 	}
 
@@ -164,6 +564,12 @@ func (r captureRequest) validate() (captureMode, error) {
 	if r.titleMatch != "" {
 		browserSelectors++
 	}
+	if r.pageURL != "" {
+		browserSelectors++
+	}
+	if r.chromeApp != "" {
+		browserSelectors++
+	}
 
 	desktopSelectors := 0
 	if r.appName != "" {
@@ -172,21 +578,128 @@ func (r captureRequest) validate() (captureMode, error) {
 	if r.nameMatch != "" {
 		desktopSelectors++
 	}
+	if r.appRegex != "" {
+		desktopSelectors++
+	}
 	if r.bundleID != "" {
 		desktopSelectors++
 	}
+	if r.bundleIDPrefix != "" {
+		desktopSelectors++
+	}
+	if r.focusedApp {
+		desktopSelectors++
+	}
 
 	if browserSelectors == 0 && desktopSelectors == 0 {
-		return "", fmt.Errorf("capture requires one target selector (e.g. --focused, --tab, --url-match, --app, --name-match, --bundle-id)")
+		return "", fmt.Errorf("capture requires one target selector (e.g. --focused, --tab, --url-match, --url, --app, --name-match, --app-regex, --bundle-id, --bundle-id-prefix, --focused-app)")
 	}
 	if browserSelectors > 0 && desktopSelectors > 0 {
 		return "", fmt.Errorf("capture selectors must be either browser-targeted or app-targeted, not both")
 	}
 	if browserSelectors > 1 {
-		return "", fmt.Errorf("browser capture accepts only one selector: --focused, --tab, --url-match, or --title-match")
+		return "", fmt.Errorf("browser capture accepts only one selector: --focused, --tab, --url-match, --title-match, --chrome-app, or --url")
 	}
 	if desktopSelectors > 1 {
-		return "", fmt.Errorf("desktop capture accepts only one selector: --app, --name-match, or --bundle-id")
+		return "", fmt.Errorf("desktop capture accepts only one selector: --app, --name-match, --app-regex, --bundle-id, --bundle-id-prefix, or --focused-app")
+	}
+	if r.method == browserMethodURLPDF {
+		if r.focused {
+			return "", fmt.Errorf("--method url-pdf requires --tab, --url-match, --title-match, or --url")
+		}
+		if r.raw || r.frontMatter || r.alsoMetadata {
+			return "", fmt.Errorf("--method url-pdf cannot be combined with --raw, --front-matter, or --also-metadata")
+		}
+		if app, ok := osascript.LookupBrowser(r.browser); ok && app.Family != osascript.BrowserFamilyChrome {
+			return "", urlPDFSafariError(app.DisplayName)
+		}
+	}
+	if r.pageURL != "" {
+		if err := validatePageURL(r.pageURL); err != nil {
+			return "", err
+		}
+	}
+	if r.chromeApp != "" && !osascript.IsAllBrowsers(r.browser) {
+		if app, ok := osascript.LookupBrowser(r.browser); ok && app.Family != osascript.BrowserFamilyChrome {
+			return "", fmt.Errorf("--chrome-app requires a Chrome --browser (chrome, chrome-beta, chrome-dev, or chrome-canary)")
+		}
+	}
+	if r.appRegex != "" {
+		if _, err := compileMatchPattern("--app-regex", r.appRegex); err != nil {
+			return "", err
+		}
+	}
+	if r.bundleID != "" {
+		if err := validateBundleID("--bundle-id", r.bundleID); err != nil {
+			return "", err
+		}
+	}
+	if r.appIndex < 0 {
+		return "", fmt.Errorf("--app-index must be positive")
+	}
+	if r.appIndex > 0 && r.appName == "" {
+		return "", fmt.Errorf("--app-index requires --app")
+	}
+	if r.verifyBundle && r.bundleID == "" {
+		return "", fmt.Errorf("--verify-bundle requires --bundle-id")
+	}
+	if r.noHostLaunch && desktopSelectors > 0 {
+		return "", fmt.Errorf("--no-host-launch applies only to browser capture")
+	}
+	if r.includeBounds && desktopSelectors == 0 {
+		return "", fmt.Errorf("--include-bounds applies only to desktop capture")
+	}
+	if r.includeBounds && r.outputFormat == formatMarkdown {
+		return "", fmt.Errorf("--include-bounds requires --format json or yaml")
+	}
+	if r.timings && r.outputFormat == formatMarkdown {
+		return "", fmt.Errorf("--timings requires --format json or yaml")
+	}
+	if r.timings && (r.raw || r.method == browserMethodURLPDF) {
+		return "", fmt.Errorf("--timings cannot be combined with --raw or --method url-pdf")
+	}
+	if err := r.validateCharBudget(desktopSelectors > 0); err != nil {
+		return "", err
+	}
+	if r.frontMatter && r.outputFormat != formatMarkdown {
+		return "", fmt.Errorf("--front-matter applies only to --format markdown")
+	}
+	if desktopSelectors > 0 && r.raw {
+		return "", fmt.Errorf("--raw applies only to browser capture")
+	}
+	if r.raw && r.frontMatter {
+		return "", fmt.Errorf("--raw cannot be combined with --front-matter")
+	}
+	if r.selection {
+		if desktopSelectors > 0 {
+			return "", fmt.Errorf("--selection applies only to browser capture")
+		}
+		if r.method != "" && r.method != "auto" {
+			return "", fmt.Errorf("--selection cannot be combined with --method %s", r.method)
+		}
+	}
+	if r.alsoMetadata && desktopSelectors > 0 {
+		return "", fmt.Errorf("--also-metadata applies only to browser capture")
+	}
+	if r.alsoMetadata && r.raw {
+		return "", fmt.Errorf("--raw cannot be combined with --also-metadata")
+	}
+	if r.targetOrder != "" {
+		if !r.focused {
+			return "", fmt.Errorf("--target-order applies only to --focused")
+		}
+		if !osascript.IsAllBrowsers(r.browser) {
+			return "", fmt.Errorf("--target-order cannot be combined with --browser")
+		}
+		if _, err := parseTargetOrder(r.targetOrder); err != nil {
+			return "", err
+		}
+	}
+	if desktopSelectors > 0 && r.urls.active() {
+		return "", fmt.Errorf("--strip-query, --strip-fragment, and --keep-params apply only to browser capture")
+	}
+	if desktopSelectors > 0 && (len(r.filter.includeDomains) > 0 || len(r.filter.excludeDomains) > 0) {
+		return "", fmt.Errorf("--include-domain and --exclude-domain apply only to browser capture")
 	}
 
 	if browserSelectors > 0 {
@@ -205,177 +718,676 @@ func (r captureRequest) validate() (captureMode, error) {
 	return captureModeDesktop, nil
 }
 
-func runBrowserCapture(ctx context.Context, request captureRequest, stderr io.Writer) ([]byte, error) {
-	if _, launchErr := ensureHostAppRunningFunc(ctx); launchErr != nil {
-		fmt.Fprintf(
-			stderr,
-			"warning: unable to auto-launch ContextGrabber app before browser capture (%v)\n",
-			launchErr,
-		)
+// executeCapture runs a validated request and emits the capture_start and
+// capture_end events around it. PDF captures are written to pdfOutputFile
+// (or the default location) and return its path instead of rendered output.
+func executeCapture(ctx context.Context, request captureRequest, mode captureMode, pdfOutputFile string, stderr io.Writer) ([]byte, string, error) {
+	startedAt := nowFunc()
+	eventlog.Emit(ctx, "capture_start", request.eventFields(mode))
+	var rendered []byte
+	var pdfPath string
+	var err error
+	switch {
+	case mode == captureModeBrowser && request.method == browserMethodURLPDF:
+		pdfPath, err = runURLPDFCapture(ctx, request, pdfOutputFile, stderr)
+	case mode == captureModeBrowser:
+		rendered, err = runBrowserCapture(ctx, request, stderr)
+	case mode == captureModeDesktop:
+		rendered, err = runDesktopCapture(ctx, request, stderr)
+	default:
+		err = fmt.Errorf("unsupported capture mode")
+	}
+	endFields := map[string]any{
+		"mode":       string(mode),
+		"ok":         err == nil,
+		"durationMs": nowFunc().Sub(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		endFields["error"] = err.Error()
 	}
+	eventlog.Emit(ctx, "capture_end", endFields)
+	return rendered, pdfPath, err
+}
 
-	targetOverride, envErr := resolveBrowserTargetOverrideEnv()
-	if envErr != nil {
-		return nil, envErr
+func runBrowserCapture(ctx context.Context, request captureRequest, stderr io.Writer) ([]byte, error) {
+	startedAt := nowFunc()
+	var timings captureTimings
+	if !request.noHostLaunch {
+		if _, launchErr := ensureHostAppRunningFunc(ctx); launchErr != nil {
+			fmt.Fprintf(
+				stderr,
+				"warning: unable to auto-launch ContextGrabber app before browser capture (%v)\n",
+				launchErr,
+			)
+		}
 	}
-	flagTarget, err := parseOptionalBrowserTarget(request.browser)
+
+	targetOverride, err := resolveBrowserTargetOverride(request)
 	if err != nil {
 		return nil, err
 	}
-	if flagTarget != "" {
-		targetOverride = flagTarget
-	}
 
 	source, err := toBrowserCaptureSource(request.method)
+	if err != nil {
+		return nil, usageError(err)
+	}
+	if request.selection {
+		source = bridge.BrowserCaptureSourceSelection
+	}
+	minContent, err := resolveMinContentLengths(request)
 	if err != nil {
 		return nil, err
 	}
 
 	if request.focused {
-		targets := focusedTargetOrder(targetOverride)
-		attempt, target, captureErr := captureBrowserWithFallback(
+		var order []bridge.BrowserTarget
+		if osascript.IsAllBrowsers(request.browser) {
+			if order, err = resolveFocusedTargetOrder(request); err != nil {
+				return nil, err
+			}
+		}
+		targets := focusedTargetOrder(ctx, targetOverride, order)
+		captureStartedAt := nowFunc()
+		attempt, target, attempts, captureErr := captureBrowserWithFallback(
 			ctx,
 			targets,
 			source,
 			request.timeoutMs,
 			bridge.BrowserCaptureMetadata{},
+			minContent,
+			request.ignoreUnreachable,
 		)
 		if captureErr != nil {
-			return nil, captureErr
+			return nil, explainFocusedCaptureFailure(ctx, targets, captureErr)
 		}
-		return encodeBrowserCaptureOutput(request.outputFormat, target, attempt)
+		timings.CaptureMs = elapsedMs(captureStartedAt)
+		warnDegradedCapture(stderr, target, attempt)
+		warnSelectionFallback(stderr, source, attempt)
+		// The focused tab's URL is only known after capture, so domain
+		// filters are enforced on the result instead of up front.
+		if capturedURL, _ := attempt.Payload["url"].(string); !request.filter.allowsURL(capturedURL) {
+			return nil, noMatchError(fmt.Errorf("focused %s tab is excluded by domain filters", browserDisplayName(target)))
+		}
+		return encodeBrowserCaptureOutput(request, target, attempt, bridge.BrowserCaptureMetadata{}, attempts, request.reportTimings(&timings, startedAt))
 	}
 
-	selectedTab, err := resolveTargetTab(ctx, request, targetOverride, stderr)
-	if err != nil {
+	var selectedTab *osascript.TabEntry
+	if request.pageURL != "" {
+		var closeTab func()
+		selectedTab, closeTab, err = openCaptureTab(ctx, request, targetOverride, stderr)
+		if err != nil {
+			return nil, err
+		}
+		defer closeTab()
+	} else if selectedTab, err = resolveTargetTab(ctx, request, targetOverride, stderr); err != nil {
 		return nil, err
 	}
 
+	activateStartedAt := nowFunc()
 	if err := activateTabFunc(
 		ctx,
 		selectedTab.Browser,
 		selectedTab.WindowIndex,
 		selectedTab.TabIndex,
 	); err != nil {
-		return nil, fmt.Errorf(
+		return nil, classifyPermissionError(fmt.Errorf(
 			"failed to activate %s tab w%d:t%d: %w",
 			selectedTab.Browser,
 			selectedTab.WindowIndex,
 			selectedTab.TabIndex,
 			err,
-		)
+		))
 	}
+	timings.ActivateMs = elapsedMs(activateStartedAt)
 
 	target, err := parseOptionalBrowserTarget(selectedTab.Browser)
 	if err != nil {
 		return nil, err
 	}
-	attempt, _, captureErr := captureBrowserWithFallback(
+	metadata := bridge.BrowserCaptureMetadata{
+		Title: selectedTab.Title,
+		URL:   selectedTab.URL,
+	}
+	if request.chromeApp != "" {
+		// The web app window belongs to its Chrome channel, which the
+		// bridge's AppleScript fallback has to address by name.
+		if app, ok := osascript.LookupBrowser(selectedTab.Browser); ok {
+			metadata.ChromeAppName = app.AppName
+		}
+	}
+	captureStartedAt := nowFunc()
+	attempt, _, attempts, captureErr := captureBrowserWithFallback(
 		ctx,
 		[]bridge.BrowserTarget{target},
 		source,
 		request.timeoutMs,
-		bridge.BrowserCaptureMetadata{
-			Title: selectedTab.Title,
-			URL:   selectedTab.URL,
-		},
+		metadata,
+		minContent,
+		request.ignoreUnreachable,
 	)
 	if captureErr != nil {
 		return nil, captureErr
 	}
-	return encodeBrowserCaptureOutput(request.outputFormat, target, attempt)
+	timings.CaptureMs = elapsedMs(captureStartedAt)
+	warnDegradedCapture(stderr, target, attempt)
+	warnSelectionFallback(stderr, source, attempt)
+	return encodeBrowserCaptureOutput(request, target, attempt, metadata, attempts, request.reportTimings(&timings, startedAt))
+}
+
+// warnSelectionFallback flags a --selection capture that fell back to the
+// full page because no text was selected.
+func warnSelectionFallback(stderr io.Writer, source bridge.BrowserCaptureSource, attempt bridge.BrowserCaptureAttempt) {
+	if source != bridge.BrowserCaptureSourceSelection || attempt.ExtractionMethod == "selection" || attempt.ErrorCode != "" {
+		return
+	}
+	fmt.Fprintln(stderr, "warning: no selected text was captured; output is the full page (--selection)")
+}
+
+// warnDegradedCapture flags a --ignore-unreachable fallback so a
+// metadata-only capture is not mistaken for page content.
+func warnDegradedCapture(stderr io.Writer, target bridge.BrowserTarget, attempt bridge.BrowserCaptureAttempt) {
+	if attempt.ErrorCode != "ERR_EXTENSION_UNAVAILABLE" {
+		return
+	}
+	fmt.Fprintf(
+		stderr,
+		"warning: %s bridge is unreachable; output is metadata only (title and URL), not page content (--ignore-unreachable)\n",
+		browserDisplayName(target),
+	)
+}
+
+// resolveBrowserTargetOverride returns the browser named by --browser, else
+// CONTEXT_GRABBER_BROWSER_TARGET, else "" for automatic selection. An
+// explicit --browser all also selects automatically, ignoring the env var.
+func resolveBrowserTargetOverride(request captureRequest) (bridge.BrowserTarget, error) {
+	if strings.EqualFold(request.browser, osascript.BrowserTargetAll) {
+		return "", nil
+	}
+	targetOverride, envErr := resolveBrowserTargetOverrideEnv()
+	if envErr != nil {
+		return "", usageError(envErr)
+	}
+	flagTarget, err := parseOptionalBrowserTarget(request.browser)
+	if err != nil {
+		return "", usageError(err)
+	}
+	if flagTarget != "" {
+		targetOverride = flagTarget
+	}
+	return targetOverride, nil
 }
 
-func runDesktopCapture(ctx context.Context, request captureRequest) ([]byte, error) {
+// runURLPDFCapture re-renders the selected tab's URL (or --url) to a PDF and
+// returns the file path. The URL is loaded again in headless Chrome, so the
+// tab is not activated and its session state does not carry over.
+func runURLPDFCapture(ctx context.Context, request captureRequest, outputFile string, stderr io.Writer) (string, error) {
+	targetOverride, err := resolveBrowserTargetOverride(request)
+	if err != nil {
+		return "", err
+	}
+	// A --url page is rendered straight from its address; no tab is opened.
+	pageURL := request.pageURL
+	target := targetOverride
+	if pageURL != "" {
+		if !request.filter.allowsURL(pageURL) {
+			return "", noMatchError(fmt.Errorf("--url %s is excluded by domain filters", pageURL))
+		}
+		if target == "" {
+			target = bridge.BrowserTargetChrome
+		}
+	} else {
+		selectedTab, err := resolveTargetTab(ctx, request, targetOverride, stderr)
+		if err != nil {
+			return "", err
+		}
+		if target, err = parseOptionalBrowserTarget(selectedTab.Browser); err != nil {
+			return "", err
+		}
+		pageURL = selectedTab.URL
+	}
+	if app, ok := osascript.LookupBrowser(string(target)); ok && app.Family != osascript.BrowserFamilyChrome {
+		return "", usageError(urlPDFSafariError(app.DisplayName))
+	}
+
+	if outputFile == "" {
+		if outputFile, err = resolveDefaultCaptureOutputFilePath(request.outputFormat, request.method); err != nil {
+			return "", err
+		}
+	}
+
+	attempt, err := renderURLToPDFFunc(ctx, target, pageURL, outputFile, request.timeoutMs)
+	if err != nil {
+		return "", err
+	}
+	eventlog.Emit(ctx, "capture_target", map[string]any{"browser": string(target), "extractionMethod": attempt.ExtractionMethod})
+	return outputFile, nil
+}
+
+func runDesktopCapture(ctx context.Context, request captureRequest, stderr io.Writer) ([]byte, error) {
+	startedAt := nowFunc()
+	var timings captureTimings
 	targetAppName := request.appName
 	targetBundleID := request.bundleID
 
+	if request.appName != "" {
+		// AppleScript activation is case- and whitespace-sensitive, so
+		// --app finder resolves to the running app's canonical name. An app
+		// that is not running (or an unavailable listing) keeps the name as
+		// given, which activation can still launch.
+		apps, warnings, err := listAppsFunc(ctx)
+		writeWarnings(stderr, warnings)
+		if err == nil {
+			if matched := findAppByExactName(apps, request.appName); matched != nil {
+				targetAppName = matched.AppName
+				if request.appIndex > matched.WindowCount {
+					return nil, noMatchError(fmt.Errorf(
+						"--app-index %d is out of range: %s has %d window(s)",
+						request.appIndex,
+						matched.AppName,
+						matched.WindowCount,
+					))
+				}
+			}
+		}
+	}
+
 	if request.nameMatch != "" {
-		apps, err := listAppsFunc(ctx)
+		apps, warnings, err := listAppsFunc(ctx)
+		writeWarnings(stderr, warnings)
 		if err != nil {
-			return nil, err
+			return nil, classifyPermissionError(err)
 		}
 		matched := findAppByNameMatch(apps, request.nameMatch)
 		if matched == nil {
-			return nil, fmt.Errorf("no running app matched --name-match %q", request.nameMatch)
+			return nil, noMatchError(fmt.Errorf("no running app matched --name-match %q", request.nameMatch))
+		}
+		targetAppName = matched.AppName
+		targetBundleID = matched.BundleIdentifier
+	}
+
+	if request.appRegex != "" {
+		pattern, err := compileMatchPattern("--app-regex", request.appRegex)
+		if err != nil {
+			return nil, usageError(err)
+		}
+		apps, warnings, err := listAppsFunc(ctx)
+		writeWarnings(stderr, warnings)
+		if err != nil {
+			return nil, classifyPermissionError(err)
+		}
+		matched, err := findAppByRegex(apps, pattern, request.first)
+		if err != nil {
+			return nil, err
+		}
+		targetAppName = matched.AppName
+		targetBundleID = matched.BundleIdentifier
+	}
+
+	if request.verifyBundle {
+		apps, warnings, err := listAppsFunc(ctx)
+		writeWarnings(stderr, warnings)
+		if err != nil {
+			return nil, classifyPermissionError(err)
+		}
+		if targetBundleID, err = verifyBundleID(apps, request.bundleID); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.bundleIDPrefix != "" {
+		apps, warnings, err := listAppsFunc(ctx)
+		writeWarnings(stderr, warnings)
+		if err != nil {
+			return nil, classifyPermissionError(err)
+		}
+		matched, err := findAppByBundleIDPrefix(apps, request.bundleIDPrefix)
+		if err != nil {
+			return nil, err
 		}
 		targetAppName = matched.AppName
 		targetBundleID = matched.BundleIdentifier
 	}
 
-	if targetBundleID != "" {
-		if err := activateAppByBundleFunc(ctx, targetBundleID); err != nil {
-			return nil, fmt.Errorf("failed to activate app %s: %w", targetBundleID, err)
+	if request.focusedApp {
+		// The frontmost app is already in front, so it is not re-activated.
+		frontmost, err := frontmostAppFunc(ctx)
+		if err != nil {
+			return nil, classifyPermissionError(fmt.Errorf("failed to read the frontmost app: %w", err))
 		}
-	} else if targetAppName != "" {
-		if err := activateAppByNameFunc(ctx, targetAppName); err != nil {
-			return nil, fmt.Errorf("failed to activate app %s: %w", targetAppName, err)
+		targetAppName = frontmost.AppName
+		targetBundleID = frontmost.BundleIdentifier
+	} else {
+		activateStartedAt := nowFunc()
+		if targetBundleID != "" {
+			if err := activateAppByBundleFunc(ctx, targetBundleID); err != nil {
+				return nil, classifyPermissionError(fmt.Errorf("failed to activate app %s: %w", targetBundleID, err))
+			}
+		} else if targetAppName != "" {
+			if err := activateAppByNameFunc(ctx, targetAppName); err != nil {
+				return nil, classifyPermissionError(fmt.Errorf("failed to activate app %s: %w", targetAppName, err))
+			}
 		}
+		timings.ActivateMs = elapsedMs(activateStartedAt)
 	}
 
 	method, err := toDesktopCaptureMethod(request.method)
 	if err != nil {
-		return nil, err
+		return nil, usageError(err)
 	}
 
 	captureFormat := bridge.DesktopCaptureFormatMarkdown
-	if request.outputFormat == formatJSON {
+	if request.outputFormat != formatMarkdown {
 		captureFormat = bridge.DesktopCaptureFormatJSON
 	}
 
-	return captureDesktopFunc(ctx, bridge.DesktopCaptureRequest{
+	eventlog.Emit(ctx, "capture_target", map[string]any{"app": targetAppName, "bundleId": targetBundleID, "method": string(method)})
+	captureStartedAt := nowFunc()
+	rendered, err := captureDesktopFunc(ctx, bridge.DesktopCaptureRequest{
 		AppName:          targetAppName,
 		BundleIdentifier: targetBundleID,
 		Method:           method,
 		Format:           captureFormat,
+		IncludeBounds:    request.includeBounds,
+		WindowIndex:      request.appIndex,
 	})
+	if errors.Is(err, bridge.ErrHostBinaryNotFound) {
+		return nil, unavailableError(err)
+	}
+	if err != nil {
+		return nil, classifyPermissionError(err)
+	}
+	timings.CaptureMs = elapsedMs(captureStartedAt)
+	if request.includeBounds {
+		if bounds, boundsErr := bridge.DesktopCaptureWindowBounds(rendered); boundsErr != nil || bounds == nil {
+			fmt.Fprintf(stderr, "warning: ContextGrabberHost did not report window bounds for %s\n", firstNonEmpty(targetAppName, targetBundleID))
+		}
+	}
+	if reported := request.reportTimings(&timings, startedAt); reported != nil {
+		if rendered, err = appendCaptureTimings(rendered, reported); err != nil {
+			return nil, err
+		}
+	}
+	if request.outputFormat == formatYAML {
+		return output.JSONToYAML(rendered)
+	}
+	return rendered, nil
 }
 
+// captureAttemptRecord describes one target tried by
+// captureBrowserWithFallback, for the attempts array of JSON output.
+type captureAttemptRecord struct {
+	Target           string `json:"target"`
+	ExtractionMethod string `json:"extractionMethod,omitempty"`
+	ErrorCode        string `json:"errorCode,omitempty"`
+	Error            string `json:"error,omitempty"`
+	Chosen           bool   `json:"chosen"`
+}
+
+// captureBrowserWithFallback tries targets in order and returns the first
+// usable capture, the target it came from, and a record of every attempt.
 func captureBrowserWithFallback(
 	ctx context.Context,
 	targets []bridge.BrowserTarget,
 	source bridge.BrowserCaptureSource,
 	timeoutMs int,
 	metadata bridge.BrowserCaptureMetadata,
-) (bridge.BrowserCaptureAttempt, bridge.BrowserTarget, error) {
+	minContent minContentLengths,
+	ignoreUnreachable bool,
+) (bridge.BrowserCaptureAttempt, bridge.BrowserTarget, []captureAttemptRecord, error) {
+	var attempts []captureAttemptRecord
+	record := func(target bridge.BrowserTarget, attempt bridge.BrowserCaptureAttempt, failure string, chosen bool) {
+		attempts = append(attempts, captureAttemptRecord{
+			Target:           string(target),
+			ExtractionMethod: attempt.ExtractionMethod,
+			ErrorCode:        attempt.ErrorCode,
+			Error:            failure,
+			Chosen:           chosen,
+		})
+	}
 	unavailableCount := 0
 	lastUnavailableError := ""
+	// safariUnavailable is the first Safari-family target whose extension
+	// bridge was unreachable; it is retried via AppleScript page text.
+	var safariUnavailable bridge.BrowserTarget
+	var shortContentFailures []string
+	// degraded is the best metadata-only attempt from an unreachable
+	// bridge, returned with --ignore-unreachable when nothing better exists.
+	var degraded bridge.BrowserCaptureAttempt
+	var degradedTarget bridge.BrowserTarget
 
 	for _, target := range targets {
 		attempt, err := captureBrowserFunc(ctx, target, source, timeoutMs, metadata)
 		if err != nil {
+			record(target, bridge.BrowserCaptureAttempt{}, err.Error(), false)
 			unavailableCount++
 			lastUnavailableError = fmt.Sprintf("%s capture failed: %v", browserDisplayName(target), err)
+			eventlog.Emit(ctx, "capture_attempt_failed", map[string]any{"browser": string(target), "error": err.Error()})
 			continue
 		}
 
-		if attempt.ExtractionMethod == "browser_extension" {
-			return attempt, target, nil
+		if attempt.ExtractionMethod == "browser_extension" || attempt.ExtractionMethod == "selection" {
+			if failure := checkMinContentLength(target, attempt, minContent); failure != "" {
+				record(target, attempt, failure, false)
+				shortContentFailures = append(shortContentFailures, failure)
+				continue
+			}
+			record(target, attempt, "", true)
+			eventlog.Emit(ctx, "capture_target", map[string]any{"browser": string(target), "extractionMethod": attempt.ExtractionMethod})
+			return attempt, target, attempts, nil
 		}
+		record(target, attempt, "", false)
 		if attempt.ErrorCode == "ERR_EXTENSION_UNAVAILABLE" {
 			unavailableCount++
 			lastUnavailableError = describeBrowserAttemptFailure(target, attempt)
+			eventlog.Emit(ctx, "capture_attempt_failed", map[string]any{"browser": string(target), "errorCode": attempt.ErrorCode})
+			if degradedTarget == "" || (strings.TrimSpace(degraded.Markdown) == "" && strings.TrimSpace(attempt.Markdown) != "") {
+				degraded, degradedTarget = attempt, target
+			}
+			if app, ok := osascript.LookupBrowser(string(target)); ok &&
+				app.Family == osascript.BrowserFamilySafari && safariUnavailable == "" {
+				safariUnavailable = target
+			}
 			continue
 		}
 
-		return bridge.BrowserCaptureAttempt{}, target, fmt.Errorf("%s", describeBrowserAttemptFailure(target, attempt))
+		return bridge.BrowserCaptureAttempt{}, target, attempts, fmt.Errorf("%s", describeBrowserAttemptFailure(target, attempt))
 	}
 
 	if unavailableCount == len(targets) && len(targets) > 0 {
-		if len(targets) > 1 {
-			return bridge.BrowserCaptureAttempt{}, "", fmt.Errorf(
-				"%s Neither Safari nor Chrome bridge is currently reachable.",
+		if safariUnavailable != "" && source != bridge.BrowserCaptureSourceRuntime {
+			attempt, ok := captureSafariPageText(ctx, safariUnavailable, metadata)
+			if ok && checkMinContentLength(safariUnavailable, attempt, minContent) == "" {
+				if source == bridge.BrowserCaptureSourceSelection {
+					attempt.Warnings = append(attempt.Warnings, "The selection is only available from the extension bridge; captured the full page instead.")
+				}
+				record(safariUnavailable, attempt, "", true)
+				eventlog.Emit(ctx, "capture_target", map[string]any{"browser": string(safariUnavailable), "extractionMethod": attempt.ExtractionMethod})
+				return attempt, safariUnavailable, attempts, nil
+			}
+		}
+		if ignoreUnreachable {
+			if attempt, target, ok := degradedCaptureAttempt(degraded, degradedTarget, targets[0], metadata); ok {
+				attempts = append(attempts, captureAttemptRecord{
+					Target:           string(target),
+					ExtractionMethod: attempt.ExtractionMethod,
+					ErrorCode:        attempt.ErrorCode,
+					Chosen:           true,
+				})
+				eventlog.Emit(ctx, "capture_target", map[string]any{"browser": string(target), "extractionMethod": attempt.ExtractionMethod})
+				return attempt, target, attempts, nil
+			}
+		}
+		if len(targets) == 2 {
+			return bridge.BrowserCaptureAttempt{}, "", attempts, unavailableError(fmt.Errorf(
+				"%s Neither %s nor %s bridge is currently reachable.",
 				lastUnavailableError,
-			)
+				browserDisplayName(targets[0]),
+				browserDisplayName(targets[1]),
+			))
+		}
+		if len(targets) > 2 {
+			names := make([]string, 0, len(targets))
+			for _, target := range targets {
+				names = append(names, browserDisplayName(target))
+			}
+			return bridge.BrowserCaptureAttempt{}, "", attempts, unavailableError(fmt.Errorf(
+				"%s None of the %s bridges is currently reachable.",
+				lastUnavailableError,
+				strings.Join(names, ", "),
+			))
 		}
-		return bridge.BrowserCaptureAttempt{}, "", fmt.Errorf(
+		return bridge.BrowserCaptureAttempt{}, "", attempts, unavailableError(fmt.Errorf(
 			"%s %s bridge is currently unreachable.",
 			lastUnavailableError,
 			browserDisplayName(targets[0]),
-		)
+		))
 	}
 
-	return bridge.BrowserCaptureAttempt{}, "", fmt.Errorf("capture failed for an unknown reason")
+	if len(shortContentFailures) > 0 {
+		failures := shortContentFailures
+		if lastUnavailableError != "" {
+			failures = append([]string{lastUnavailableError}, failures...)
+		}
+		return bridge.BrowserCaptureAttempt{}, "", attempts, fmt.Errorf("%s", strings.Join(failures, " "))
+	}
+
+	return bridge.BrowserCaptureAttempt{}, "", attempts, fmt.Errorf("capture failed for an unknown reason")
+}
+
+// degradedCaptureAttempt returns the metadata-only attempt --ignore-unreachable
+// falls back to: the bridge's own metadata-only attempt when it carries
+// markdown, otherwise title/URL markdown built from the listed tab. It
+// reports false when neither is available (e.g. --focused with no bridge
+// response).
+func degradedCaptureAttempt(
+	degraded bridge.BrowserCaptureAttempt,
+	degradedTarget bridge.BrowserTarget,
+	fallbackTarget bridge.BrowserTarget,
+	metadata bridge.BrowserCaptureMetadata,
+) (bridge.BrowserCaptureAttempt, bridge.BrowserTarget, bool) {
+	if degradedTarget != "" && strings.TrimSpace(degraded.Markdown) != "" {
+		return degraded, degradedTarget, true
+	}
+	if strings.TrimSpace(metadata.Title) == "" && strings.TrimSpace(metadata.URL) == "" {
+		return bridge.BrowserCaptureAttempt{}, "", false
+	}
+	target := firstNonEmpty(string(degradedTarget), string(fallbackTarget))
+	title := firstNonEmpty(metadata.Title, metadata.URL)
+	markdown := "# " + title + "\n"
+	if metadata.URL != "" {
+		markdown += "\nSource: " + metadata.URL + "\n"
+	}
+	return bridge.BrowserCaptureAttempt{
+		ExtractionMethod: "metadata_only",
+		ErrorCode:        "ERR_EXTENSION_UNAVAILABLE",
+		Warnings:         degraded.Warnings,
+		Markdown:         markdown,
+		Payload:          map[string]any{"title": metadata.Title, "url": metadata.URL},
+	}, bridge.BrowserTarget(target), true
+}
+
+// minContentLengths is the minimum content check: an explicit
+// --min-content-length applies to every extraction method, otherwise the
+// min-content config setting gives per-method lengths.
+type minContentLengths struct {
+	flag     int
+	byMethod map[string]int
+}
+
+// minContentLengthSet reports whether --min-content-length was passed or
+// filled from CGRAB_CAPTURE_MIN_CONTENT_LENGTH, which does not mark the flag
+// as changed.
+func minContentLengthSet(flags *pflag.FlagSet) bool {
+	if flags.Changed("min-content-length") {
+		return true
+	}
+	value, ok := os.LookupEnv(captureEnvVar("min-content-length"))
+	return ok && strings.TrimSpace(value) != ""
+}
+
+// resolveMinContentLengths returns --min-content-length when it was passed,
+// else the min-content config setting.
+func resolveMinContentLengths(request captureRequest) (minContentLengths, error) {
+	if request.minContentLengthSet {
+		return minContentLengths{flag: request.minContentLength}, nil
+	}
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return minContentLengths{}, err
+	}
+	return minContentLengths{flag: request.minContentLength, byMethod: settings.MinContentByMethod}, nil
+}
+
+// forMethod returns the minimum length for an extraction method and the
+// setting it came from, for error messages.
+func (m minContentLengths) forMethod(method string) (int, string) {
+	if length, ok := m.byMethod[method]; ok {
+		return length, fmt.Sprintf("min-content %s=%d", method, length)
+	}
+	return m.flag, fmt.Sprintf("--min-content-length %d", m.flag)
+}
+
+// checkMinContentLength returns a failure description when the captured
+// markdown is shorter than the minimum for its extraction method. Zero
+// disables it.
+func checkMinContentLength(
+	target bridge.BrowserTarget,
+	attempt bridge.BrowserCaptureAttempt,
+	minContent minContentLengths,
+) string {
+	minContentLength, setting := minContent.forMethod(attempt.ExtractionMethod)
+	if minContentLength <= 0 {
+		return ""
+	}
+	length := len(strings.TrimSpace(attempt.Markdown))
+	if length >= minContentLength {
+		return ""
+	}
+	return fmt.Sprintf(
+		"%s capture returned %d characters of content (%s).",
+		browserDisplayName(target),
+		length,
+		setting,
+	)
+}
+
+// captureSafariPageText reads the current tab's text of a Safari-family
+// browser via AppleScript as a degraded content source when the extension
+// bridge is unreachable.
+func captureSafariPageText(
+	ctx context.Context,
+	target bridge.BrowserTarget,
+	metadata bridge.BrowserCaptureMetadata,
+) (bridge.BrowserCaptureAttempt, bool) {
+	page, err := safariPageTextFunc(ctx, string(target))
+	if err != nil || page.Text == "" {
+		return bridge.BrowserCaptureAttempt{}, false
+	}
+
+	title := firstNonEmpty(metadata.Title, page.Title, "Untitled")
+	url := firstNonEmpty(metadata.URL, page.URL)
+	lines := []string{"# " + title, ""}
+	if url != "" {
+		lines = append(lines, "Source: "+url, "")
+	}
+	lines = append(lines, page.Text)
+
+	return bridge.BrowserCaptureAttempt{
+		ExtractionMethod: "applescript_dom",
+		Warnings: []string{
+			browserDisplayName(target) + " extension bridge unavailable; captured page text via AppleScript.",
+		},
+		Markdown: strings.Join(lines, "\n") + "\n",
+	}, true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
 }
 
 type browserCaptureOutput struct {
@@ -385,31 +1397,140 @@ type browserCaptureOutput struct {
 	Warnings         []string       `json:"warnings"`
 	Markdown         string         `json:"markdown"`
 	Payload          map[string]any `json:"payload,omitempty"`
+	// Attempts lists every target tried, in order, including the chosen one.
+	Attempts []captureAttemptRecord `json:"attempts,omitempty"`
+	// Timings is set with --timings.
+	Timings *captureTimings `json:"timings,omitempty"`
+	// Truncated reports that --max-chars or --max-tokens trimmed Markdown.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 func encodeBrowserCaptureOutput(
-	format string,
+	request captureRequest,
 	target bridge.BrowserTarget,
 	attempt bridge.BrowserCaptureAttempt,
+	metadata bridge.BrowserCaptureMetadata,
+	attempts []captureAttemptRecord,
+	timings *captureTimings,
 ) ([]byte, error) {
-	switch format {
+	if request.raw {
+		// --raw skips the trimmed browserCaptureOutput view so the
+		// normalized context, response, and request envelopes are kept.
+		return json.MarshalIndent(attempt, "", "  ")
+	}
+	if request.urls.active() {
+		attempt, metadata = cleanCapturedURLs(request.urls, attempt, metadata)
+	}
+	if request.normalizeWhitespace && request.outputFormat == formatMarkdown {
+		attempt.Markdown = normalizeMarkdownWhitespace(attempt.Markdown)
+	}
+	var truncated bool
+	attempt.Markdown, truncated = truncateMarkdown(attempt.Markdown, request.captureCharBudget())
+	if request.alsoMetadata {
+		// Added after truncation so the provenance line is never cut.
+		attempt.Markdown = renderCaptureSourceLine(attempt, metadata) + attempt.Markdown
+	}
+	switch format := request.outputFormat; format {
 	case formatMarkdown:
-		if strings.HasSuffix(attempt.Markdown, "\n") {
-			return []byte(attempt.Markdown), nil
+		markdown := attempt.Markdown
+		if !strings.HasSuffix(markdown, "\n") {
+			markdown += "\n"
 		}
-		return []byte(attempt.Markdown + "\n"), nil
-	case formatJSON:
-		return json.MarshalIndent(browserCaptureOutput{
+		if request.frontMatter {
+			markdown = renderCaptureFrontMatter(target, attempt, metadata) + markdown
+		}
+		return []byte(markdown), nil
+	case formatJSON, formatYAML:
+		encoded, err := json.MarshalIndent(browserCaptureOutput{
 			Target:           string(target),
 			ExtractionMethod: attempt.ExtractionMethod,
 			ErrorCode:        attempt.ErrorCode,
 			Warnings:         attempt.Warnings,
 			Markdown:         attempt.Markdown,
 			Payload:          attempt.Payload,
+			Attempts:         attempts,
+			Timings:          timings,
+			Truncated:        truncated,
 		}, "", "  ")
+		if err != nil || format == formatJSON {
+			return encoded, err
+		}
+		return output.JSONToYAML(encoded)
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
+		return nil, usageError(fmt.Errorf("unsupported format: %s", format))
+	}
+}
+
+// cleanCapturedURLs applies --strip-query and friends to the capture
+// metadata, the payload url, and occurrences of those URLs in the rendered
+// markdown. The payload map is copied rather than modified.
+func cleanCapturedURLs(
+	urls urlCleaner,
+	attempt bridge.BrowserCaptureAttempt,
+	metadata bridge.BrowserCaptureMetadata,
+) (bridge.BrowserCaptureAttempt, bridge.BrowserCaptureMetadata) {
+	replacements := []string{}
+	if metadata.URL != "" {
+		cleaned := urls.clean(metadata.URL)
+		replacements = append(replacements, metadata.URL, cleaned)
+		metadata.URL = cleaned
+	}
+	if payloadURL, ok := attempt.Payload["url"].(string); ok && payloadURL != "" {
+		payload := make(map[string]any, len(attempt.Payload))
+		for key, value := range attempt.Payload {
+			payload[key] = value
+		}
+		cleaned := urls.clean(payloadURL)
+		payload["url"] = cleaned
+		attempt.Payload = payload
+		replacements = append(replacements, payloadURL, cleaned)
+	}
+	if len(replacements) > 0 {
+		attempt.Markdown = strings.NewReplacer(replacements...).Replace(attempt.Markdown)
+	}
+	return attempt, metadata
+}
+
+// renderCaptureSourceLine returns the --also-metadata "> Source: <title> —
+// <url>" blockquote and a blank line, or "" when neither is known.
+func renderCaptureSourceLine(attempt bridge.BrowserCaptureAttempt, metadata bridge.BrowserCaptureMetadata) string {
+	payloadTitle, _ := attempt.Payload["title"].(string)
+	payloadURL, _ := attempt.Payload["url"].(string)
+	var parts []string
+	if title := strings.TrimSpace(firstNonEmpty(metadata.Title, payloadTitle)); title != "" {
+		parts = append(parts, title)
+	}
+	if pageURL := strings.TrimSpace(firstNonEmpty(metadata.URL, payloadURL)); pageURL != "" {
+		parts = append(parts, pageURL)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "> Source: " + strings.Join(parts, " — ") + "\n\n"
+}
+
+// renderCaptureFrontMatter builds a YAML provenance block for archived
+// markdown captures. Title and URL fall back to the bridge payload when the
+// capture was not resolved from a listed tab (e.g. --focused).
+func renderCaptureFrontMatter(
+	target bridge.BrowserTarget,
+	attempt bridge.BrowserCaptureAttempt,
+	metadata bridge.BrowserCaptureMetadata,
+) string {
+	payloadTitle, _ := attempt.Payload["title"].(string)
+	payloadURL, _ := attempt.Payload["url"].(string)
+
+	lines := []string{
+		"---",
+		"title: " + strconv.Quote(firstNonEmpty(metadata.Title, payloadTitle)),
+		"url: " + strconv.Quote(firstNonEmpty(metadata.URL, payloadURL)),
+		"browser: " + string(target),
+		"extractionMethod: " + strconv.Quote(attempt.ExtractionMethod),
+		"capturedAt: " + nowFunc().UTC().Format(time.RFC3339),
+		"---",
+		"",
 	}
+	return strings.Join(lines, "\n") + "\n"
 }
 
 func resolveTargetTab(
@@ -422,27 +1543,44 @@ func resolveTargetTab(
 	if targetOverride != "" {
 		browserFilter = string(targetOverride)
 	}
+	if request.chromeApp != "" {
+		if browserFilter == "" {
+			browserFilter = string(bridge.BrowserTargetChrome)
+		} else if app, ok := osascript.LookupBrowser(browserFilter); ok && app.Family != osascript.BrowserFamilyChrome {
+			return nil, usageError(fmt.Errorf("--chrome-app requires a Chrome browser target, got %s", browserFilter))
+		}
+	}
 
 	tabs, warnings, err := listTabsFunc(ctx, browserFilter)
 	writeWarnings(stderr, warnings)
 	if err != nil {
-		return nil, err
+		return nil, classifyPermissionError(err)
 	}
+	tabs = request.filter.apply(tabs)
 
 	if request.tabReference != "" {
 		windowIndex, tabIndex, parseErr := parseTabReference(request.tabReference)
 		if parseErr != nil {
-			return nil, parseErr
+			return nil, usageError(parseErr)
 		}
 		matched := findTabByIndex(tabs, windowIndex, tabIndex)
 		if targetOverride != "" {
 			matched = filterTabsByTarget(matched, targetOverride)
 		}
 		if len(matched) == 0 {
-			return nil, fmt.Errorf("no tab found for --tab %s", request.tabReference)
+			return nil, noMatchError(fmt.Errorf("no tab found for --tab %s", request.tabReference))
+		}
+		if len(matched) > 1 && targetOverride == "" {
+			// Without --browser, the same index in several browsers
+			// resolves to the one the user is looking at.
+			if frontmost, ok := frontmostBrowserTarget(ctx); ok {
+				if inFrontmost := filterTabsByTarget(matched, frontmost); len(inFrontmost) == 1 {
+					matched = inFrontmost
+				}
+			}
 		}
 		if len(matched) > 1 {
-			return nil, fmt.Errorf("multiple tabs matched --tab %s; pass --browser safari|chrome", request.tabReference)
+			return nil, usageError(fmt.Errorf("multiple tabs matched --tab %s; pass --browser safari|chrome", request.tabReference))
 		}
 		return &matched[0], nil
 	}
@@ -454,22 +1592,63 @@ func resolveTargetTab(
 				return &tabCopy, nil
 			}
 		}
-		return nil, fmt.Errorf("no tab matched --url-match %q", request.urlMatch)
+		return nil, noMatchError(fmt.Errorf("no tab matched --url-match %q", request.urlMatch))
 	}
 
 	if request.titleMatch != "" {
+		matched := []osascript.TabEntry{}
 		for _, tab := range tabs {
 			if strings.Contains(strings.ToLower(tab.Title), strings.ToLower(request.titleMatch)) {
-				tabCopy := tab
-				return &tabCopy, nil
+				matched = append(matched, tab)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, noMatchError(fmt.Errorf("no tab matched --title-match %q", request.titleMatch))
+		}
+		if len(matched) > 1 && !request.first {
+			candidates := make([]string, 0, len(matched))
+			for _, tab := range matched {
+				candidates = append(candidates, fmt.Sprintf("  %s w%d:t%d - %s", tab.Browser, tab.WindowIndex, tab.TabIndex, tab.URL))
 			}
+			return nil, usageError(fmt.Errorf(
+				"multiple tabs matched --title-match %q; pass --tab <w:t> --browser <browser> or --first:\n%s",
+				request.titleMatch,
+				strings.Join(candidates, "\n"),
+			))
+		}
+		return &matched[0], nil
+	}
+
+	if request.chromeApp != "" {
+		if matched := findWebAppTab(tabs, request.chromeApp); matched != nil {
+			return matched, nil
 		}
-		return nil, fmt.Errorf("no tab matched --title-match %q", request.titleMatch)
+		return nil, noMatchError(fmt.Errorf("no Chrome web app window matched --chrome-app %q (see list tabs --browser %s)", request.chromeApp, browserFilter))
 	}
 
 	return nil, fmt.Errorf("missing tab selector")
 }
 
+// findWebAppTab returns the active tab of the frontmost app-mode window whose
+// web app name equals name, ignoring case and runs of whitespace.
+func findWebAppTab(tabs []osascript.TabEntry, name string) *osascript.TabEntry {
+	needle := normalizeAppName(name)
+	var matched *osascript.TabEntry
+	for _, tab := range tabs {
+		if tab.WebAppName == "" || normalizeAppName(tab.WebAppName) != needle {
+			continue
+		}
+		better := matched == nil ||
+			tab.WindowIndex < matched.WindowIndex ||
+			(tab.WindowIndex == matched.WindowIndex && tab.IsActive && !matched.IsActive)
+		if better {
+			tabCopy := tab
+			matched = &tabCopy
+		}
+	}
+	return matched
+}
+
 func findTabByIndex(tabs []osascript.TabEntry, windowIndex int, tabIndex int) []osascript.TabEntry {
 	matches := []osascript.TabEntry{}
 	for _, tab := range tabs {
@@ -533,30 +1712,250 @@ func findAppByNameMatch(apps []osascript.AppEntry, match string) *osascript.AppE
 	return nil
 }
 
+// findAppByExactName returns the app whose name equals name, ignoring case
+// and runs of whitespace. A byte-for-byte match wins over a folded one.
+func findAppByExactName(apps []osascript.AppEntry, name string) *osascript.AppEntry {
+	needle := normalizeAppName(name)
+	if needle == "" {
+		return nil
+	}
+	var folded *osascript.AppEntry
+	for _, app := range apps {
+		if app.AppName == name {
+			appCopy := app
+			return &appCopy
+		}
+		if folded == nil && normalizeAppName(app.AppName) == needle {
+			appCopy := app
+			folded = &appCopy
+		}
+	}
+	return folded
+}
+
+func normalizeAppName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// compileMatchPattern compiles a regular expression selector, naming the
+// flag in the error so usage mistakes point at the right option.
+func compileMatchPattern(flag string, pattern string) (*regexp.Regexp, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern %q: %w", flag, pattern, err)
+	}
+	return compiled, nil
+}
+
+// findAppByRegex resolves an app name pattern to a single running app.
+// Several processes sharing one app name count as one match; several
+// distinct names are an error unless first is set.
+func findAppByRegex(apps []osascript.AppEntry, pattern *regexp.Regexp, first bool) (*osascript.AppEntry, error) {
+	var matches []osascript.AppEntry
+	seen := map[string]bool{}
+	for _, app := range apps {
+		if !pattern.MatchString(app.AppName) || seen[app.AppName] {
+			continue
+		}
+		seen[app.AppName] = true
+		matches = append(matches, app)
+	}
+
+	switch {
+	case len(matches) == 0:
+		return nil, noMatchError(fmt.Errorf("no running app matched --app-regex %q", pattern.String()))
+	case len(matches) == 1 || first:
+		return &matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, app := range matches {
+			names = append(names, app.AppName)
+		}
+		return nil, usageError(fmt.Errorf(
+			"multiple apps matched --app-regex %q: %s; pass a narrower pattern or --first",
+			pattern.String(),
+			strings.Join(names, ", "),
+		))
+	}
+}
+
+// findAppByBundleIDPrefix resolves a bundle identifier prefix to a single
+// running app. An exact bundle id match wins; otherwise the prefix must
+// identify exactly one distinct bundle id.
+func findAppByBundleIDPrefix(apps []osascript.AppEntry, prefix string) (*osascript.AppEntry, error) {
+	needle := strings.ToLower(strings.TrimSpace(prefix))
+	var matches []osascript.AppEntry
+	seen := map[string]bool{}
+	for _, app := range apps {
+		bundleID := strings.ToLower(app.BundleIdentifier)
+		if bundleID == "" || !strings.HasPrefix(bundleID, needle) {
+			continue
+		}
+		if bundleID == needle {
+			appCopy := app
+			return &appCopy, nil
+		}
+		if seen[bundleID] {
+			continue
+		}
+		seen[bundleID] = true
+		matches = append(matches, app)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, noMatchError(fmt.Errorf("no running app matched --bundle-id-prefix %q", prefix))
+	case 1:
+		return &matches[0], nil
+	default:
+		candidates := make([]string, 0, len(matches))
+		for _, app := range matches {
+			candidates = append(candidates, fmt.Sprintf("%s (%s)", app.BundleIdentifier, app.AppName))
+		}
+		return nil, usageError(fmt.Errorf(
+			"multiple apps matched --bundle-id-prefix %q: %s; pass a longer prefix or --bundle-id",
+			prefix,
+			strings.Join(candidates, ", "),
+		))
+	}
+}
+
 func resolveBrowserTargetOverrideEnv() (bridge.BrowserTarget, error) {
 	raw := strings.ToLower(strings.TrimSpace(os.Getenv("CONTEXT_GRABBER_BROWSER_TARGET")))
 	return parseOptionalBrowserTarget(raw)
 }
 
+// parseOptionalBrowserTarget returns "" for an empty value or "all", which
+// both leave the browser to automatic selection.
 func parseOptionalBrowserTarget(raw string) (bridge.BrowserTarget, error) {
-	normalized := strings.ToLower(strings.TrimSpace(raw))
-	switch normalized {
-	case "":
+	if osascript.IsAllBrowsers(raw) {
 		return "", nil
-	case "safari":
-		return bridge.BrowserTargetSafari, nil
-	case "chrome":
-		return bridge.BrowserTargetChrome, nil
-	default:
-		return "", fmt.Errorf("unsupported browser %q (expected safari or chrome)", raw)
 	}
+	app, ok := osascript.LookupBrowser(raw)
+	if !ok {
+		return "", fmt.Errorf(
+			"unsupported browser %q (expected one of: %s)",
+			raw,
+			strings.Join(osascript.BrowserFilterValues(), ", "),
+		)
+	}
+	return bridge.BrowserTarget(app.Target), nil
+}
+
+// parseTargetOrder parses a comma-separated --target-order value such as
+// "chrome,safari" into browser targets, dropping blanks and duplicates.
+func parseTargetOrder(raw string) ([]bridge.BrowserTarget, error) {
+	normalized := config.NormalizeTargetOrder(raw)
+	if err := validateTargetOrder(normalized); err != nil {
+		return nil, err
+	}
+	if normalized == "" {
+		return nil, fmt.Errorf("--target-order requires at least one browser")
+	}
+	var targets []bridge.BrowserTarget
+	for _, target := range strings.Split(normalized, ",") {
+		targets = append(targets, bridge.BrowserTarget(target))
+	}
+	return targets, nil
+}
+
+// resolveFocusedTargetOrder returns the --target-order list, else the
+// target-order config setting, else nil for the built-in order.
+func resolveFocusedTargetOrder(request captureRequest) ([]bridge.BrowserTarget, error) {
+	if request.targetOrder != "" {
+		order, err := parseTargetOrder(request.targetOrder)
+		if err != nil {
+			return nil, usageError(err)
+		}
+		return order, nil
+	}
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if settings.FocusedTargetOrder == "" {
+		return nil, nil
+	}
+	return parseTargetOrder(settings.FocusedTargetOrder)
 }
 
-func focusedTargetOrder(override bridge.BrowserTarget) []bridge.BrowserTarget {
-	if override != "" {
-		return []bridge.BrowserTarget{override}
+// explainFocusedCaptureFailure replaces a --focused capture failure against a
+// single browser with a specific reason when that browser is not running or
+// has no open windows, since the bridge only reports that it found no tab.
+func explainFocusedCaptureFailure(ctx context.Context, targets []bridge.BrowserTarget, captureErr error) error {
+	if len(targets) != 1 {
+		return captureErr
 	}
-	return []bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome}
+	name := browserDisplayName(targets[0])
+	count, err := browserWindowCountFunc(ctx, string(targets[0]))
+	switch {
+	case errors.Is(err, osascript.ErrBrowserNotRunning):
+		return unavailableError(fmt.Errorf("%s is not running", name))
+	case err == nil && count == 0:
+		return unavailableError(fmt.Errorf("%s is running but has no open windows", name))
+	}
+	return captureErr
+}
+
+// focusedTargetOrder tries the frontmost browser first so --focused captures
+// the browser the user is looking at. When the frontmost app cannot be read
+// or is not a supported browser, the fixed Safari-then-Chrome order is used.
+//
+// A non-empty preferred order replaces the fixed order and limits the
+// fallback to the listed browsers. A single-target override then only moves
+// its browser to the front, and is ignored when it is not in the list.
+func focusedTargetOrder(ctx context.Context, override bridge.BrowserTarget, preferred []bridge.BrowserTarget) []bridge.BrowserTarget {
+	order := preferred
+	if len(order) == 0 {
+		if override != "" {
+			return []bridge.BrowserTarget{override}
+		}
+		order = []bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome}
+	} else if override != "" {
+		if containsBrowserTarget(order, override) {
+			return moveBrowserTargetFirst(order, override)
+		}
+	}
+
+	frontmostTarget, ok := frontmostBrowserTarget(ctx)
+	if !ok {
+		return order
+	}
+	if len(preferred) > 0 && !containsBrowserTarget(preferred, frontmostTarget) {
+		return order
+	}
+	return moveBrowserTargetFirst(order, frontmostTarget)
+}
+
+func containsBrowserTarget(targets []bridge.BrowserTarget, target bridge.BrowserTarget) bool {
+	for _, candidate := range targets {
+		if candidate == target {
+			return true
+		}
+	}
+	return false
+}
+
+// moveBrowserTargetFirst returns order with first at the front, adding it
+// when it is not already present.
+func moveBrowserTargetFirst(order []bridge.BrowserTarget, first bridge.BrowserTarget) []bridge.BrowserTarget {
+	reordered := []bridge.BrowserTarget{first}
+	for _, target := range order {
+		if target != first {
+			reordered = append(reordered, target)
+		}
+	}
+	return reordered
+}
+
+// frontmostBrowserTarget returns the frontmost app's browser target, or
+// false when it is not a supported browser or cannot be determined.
+func frontmostBrowserTarget(ctx context.Context) (bridge.BrowserTarget, bool) {
+	target, err := frontmostBrowserFunc(ctx)
+	if err != nil || target == "" {
+		return "", false
+	}
+	return bridge.BrowserTarget(target), true
 }
 
 func toBrowserCaptureSource(method string) (bridge.BrowserCaptureSource, error) {
@@ -567,9 +1966,11 @@ func toBrowserCaptureSource(method string) (bridge.BrowserCaptureSource, error)
 		return bridge.BrowserCaptureSourceLive, nil
 	case "extension":
 		return bridge.BrowserCaptureSourceRuntime, nil
+	case browserMethodURLPDF:
+		return bridge.BrowserCaptureSourceURLPDF, nil
 	default:
 		return "", fmt.Errorf(
-			"unsupported browser --method value %q (expected auto, applescript, or extension)",
+			"unsupported browser --method value %q (expected auto, applescript, extension, or url-pdf)",
 			method,
 		)
 	}
@@ -592,10 +1993,10 @@ func toDesktopCaptureMethod(method string) (bridge.DesktopCaptureMethod, error)
 }
 
 func browserDisplayName(target bridge.BrowserTarget) string {
-	if target == bridge.BrowserTargetSafari {
-		return "Safari"
+	if app, ok := osascript.LookupBrowser(string(target)); ok {
+		return app.DisplayName
 	}
-	return "Chrome"
+	return string(target)
 }
 
 func describeBrowserAttemptFailure(target bridge.BrowserTarget, attempt bridge.BrowserCaptureAttempt) string {
@@ -610,7 +2011,15 @@ func describeBrowserAttemptFailure(target bridge.BrowserTarget, attempt bridge.B
 	return fmt.Sprintf("%s capture failed (%s): %s", browserDisplayName(target), code, warning)
 }
 
-func resolveDefaultCaptureOutputFilePath(format string) (string, error) {
+// Auto-saved captures are named capture-<UTC timestamp>.<md|json>; history
+// parses the same layout back out of the file names.
+const (
+	captureFilePrefix          = "capture-"
+	captureFileTimestampLayout = "20060102-150405.000"
+	gzipExtension              = ".gz"
+)
+
+func resolveCaptureDir() (string, error) {
 	settings, err := config.LoadSettings()
 	if err != nil {
 		return "", err
@@ -619,12 +2028,127 @@ func resolveDefaultCaptureOutputFilePath(format string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return captureDir, nil
+}
+
+func resolveDefaultCaptureOutputFilePath(format string, method string) (string, error) {
+	captureDir, err := resolveCaptureDir()
+	if err != nil {
+		return "", err
+	}
+
+	timestamp := nowFunc().UTC().Format(captureFileTimestampLayout)
+	return filepath.Join(captureDir, captureFilePrefix+timestamp+captureOutputExtension(format, method)), nil
+}
+
+// captureOutputExtension is the file extension for a capture written with
+// format and method. The method wins when it produces its own file type
+// (--method url-pdf); otherwise the output format decides.
+func captureOutputExtension(format string, method string) string {
+	if method == browserMethodURLPDF {
+		return ".pdf"
+	}
+	switch format {
+	case formatJSON:
+		return ".json"
+	case formatYAML:
+		return ".yaml"
+	default:
+		return ".md"
+	}
+}
+
+// captureExtensionAliases maps other recognized capture file extensions to
+// the extension captureOutputExtension uses for the same content.
+var captureExtensionAliases = map[string]string{
+	".md":       ".md",
+	".markdown": ".md",
+	".json":     ".json",
+	".yaml":     ".yaml",
+	".yml":      ".yaml",
+	".pdf":      ".pdf",
+}
+
+// captureFileExtensionWarning returns a warning when path has a recognized
+// capture extension that does not match what format and method write (e.g.
+// JSON into notes.md). Unrecognized or missing extensions are not checked.
+func captureFileExtensionWarning(path string, format string, method string) string {
+	extension := strings.ToLower(filepath.Ext(strings.TrimSuffix(path, gzipExtension)))
+	normalized, ok := captureExtensionAliases[extension]
+	if !ok {
+		return ""
+	}
+	expected := captureOutputExtension(format, method)
+	if normalized == expected {
+		return ""
+	}
+	return fmt.Sprintf("warning: %s has a %s extension but this capture writes %s content\n", path, extension, strings.TrimPrefix(expected, "."))
+}
+
+// openWrittenCapture handles --open and --reveal once a capture file has been
+// written. Failing to open the file is only a warning: the capture itself
+// succeeded.
+func openWrittenCapture(ctx context.Context, path string, open bool, reveal bool, stderr io.Writer) {
+	if path == "" || (!open && !reveal) {
+		return
+	}
+	if err := openCaptureFileFunc(ctx, path, reveal); err != nil {
+		fmt.Fprintf(stderr, "warning: unable to open %s (%v)\n", path, err)
+	}
+}
+
+// openCaptureFile runs `open <path>`, or `open -R <path>` to reveal the file
+// in Finder.
+func openCaptureFile(ctx context.Context, path string, reveal bool) error {
+	args := []string{path}
+	if reveal {
+		args = []string{"-R", path}
+	}
+	return exec.CommandContext(ctx, "open", args...).Run()
+}
+
+// resolveNamedCaptureOutputFilePath returns <captureDir>/<name><extension>
+// for --save-as. An existing file is kept and the name gets a numeric
+// suffix (name-2, name-3, ...) unless overwrite is set.
+func resolveNamedCaptureOutputFilePath(name string, extension string, overwrite bool) (string, error) {
+	captureDir, err := resolveCaptureDir()
+	if err != nil {
+		return "", err
+	}
+	base := sanitizeCaptureName(strings.TrimSuffix(name, extension))
+	if base == "" {
+		return "", usageError(fmt.Errorf("--save-as %q does not contain any usable file name characters", name))
+	}
 
-	timestamp := nowFunc().UTC().Format("20060102-150405.000")
-	extension := ".md"
-	if format == formatJSON {
-		extension = ".json"
+	path := filepath.Join(captureDir, base+extension)
+	if overwrite {
+		return path, nil
+	}
+	for suffix := 2; ; suffix++ {
+		if _, statErr := os.Stat(path); errors.Is(statErr, os.ErrNotExist) {
+			return path, nil
+		} else if statErr != nil {
+			return "", fmt.Errorf("check capture file %s: %w", path, statErr)
+		}
+		path = filepath.Join(captureDir, fmt.Sprintf("%s-%d%s", base, suffix, extension))
 	}
+}
 
-	return filepath.Join(captureDir, "capture-"+timestamp+extension), nil
+// sanitizeCaptureName keeps letters, digits, '.', '_' and '-' and collapses
+// everything else into single dashes, so the name stays a plain file name
+// inside the capture directory.
+func sanitizeCaptureName(name string) string {
+	var builder strings.Builder
+	lastDash := false
+	for _, r := range name {
+		switch {
+		case r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-'):
+			builder.WriteRune(r)
+			lastDash = r == '-'
+		case !lastDash:
+			builder.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.Trim(builder.String(), "-.")
 }
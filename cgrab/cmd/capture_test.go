@@ -3,14 +3,19 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
 )
 
 func TestToBrowserCaptureSource(t *testing.T) {
@@ -118,13 +123,15 @@ func TestCaptureRequestValidateRejectsMixedSelectors(t *testing.T) {
 }
 
 func TestCaptureBrowserWithFallbackUsesSecondTargetOnUnavailable(t *testing.T) {
+	restorePageText := stubSafariPageText(osascript.PageText{}, errors.New("javascript disabled"))
+	defer restorePageText()
 	previousCaptureBrowserFunc := captureBrowserFunc
 	previousEnsureHostAppRunningFunc := ensureHostAppRunningFunc
 	t.Cleanup(func() {
 		captureBrowserFunc = previousCaptureBrowserFunc
 		ensureHostAppRunningFunc = previousEnsureHostAppRunningFunc
 	})
-	ensureHostAppRunningFunc = func(context.Context) (bool, error) {
+	ensureHostAppRunningFunc = func(context.Context, ...bridge.HostAppLaunchOption) (bool, error) {
 		return false, nil
 	}
 
@@ -150,12 +157,14 @@ func TestCaptureBrowserWithFallbackUsesSecondTargetOnUnavailable(t *testing.T) {
 		}, nil
 	}
 
-	attempt, target, err := captureBrowserWithFallback(
+	attempt, target, _, err := captureBrowserWithFallback(
 		context.Background(),
 		[]bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome},
 		bridge.BrowserCaptureSourceAuto,
 		1200,
 		bridge.BrowserCaptureMetadata{},
+		minContentLengths{},
+		false,
 	)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -168,6 +177,68 @@ func TestCaptureBrowserWithFallbackUsesSecondTargetOnUnavailable(t *testing.T) {
 	}
 }
 
+func TestCaptureBrowserWithFallbackUsesSafariPageTextWhenBridgesUnavailable(t *testing.T) {
+	restorePageText := stubSafariPageText(osascript.PageText{
+		Title: "Article",
+		URL:   "https://example.com/article",
+		Text:  "Body text",
+	}, nil)
+	defer restorePageText()
+	previousCaptureBrowserFunc := captureBrowserFunc
+	t.Cleanup(func() {
+		captureBrowserFunc = previousCaptureBrowserFunc
+	})
+	captureBrowserFunc = func(
+		_ context.Context,
+		_ bridge.BrowserTarget,
+		_ bridge.BrowserCaptureSource,
+		_ int,
+		_ bridge.BrowserCaptureMetadata,
+	) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{
+			ExtractionMethod: "metadata_only",
+			ErrorCode:        "ERR_EXTENSION_UNAVAILABLE",
+			Warnings:         []string{"bridge unavailable"},
+		}, nil
+	}
+
+	attempt, target, _, err := captureBrowserWithFallback(
+		context.Background(),
+		[]bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome},
+		bridge.BrowserCaptureSourceLive,
+		1200,
+		bridge.BrowserCaptureMetadata{},
+		minContentLengths{},
+		false,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target != bridge.BrowserTargetSafari {
+		t.Fatalf("expected safari target, got %q", target)
+	}
+	if attempt.ExtractionMethod != "applescript_dom" {
+		t.Fatalf("expected applescript_dom extraction, got %q", attempt.ExtractionMethod)
+	}
+	want := "# Article\n\nSource: https://example.com/article\n\nBody text\n"
+	if attempt.Markdown != want {
+		t.Fatalf("unexpected markdown:\nwant: %q\ngot:  %q", want, attempt.Markdown)
+	}
+
+	_, _, _, err = captureBrowserWithFallback(
+		context.Background(),
+		[]bridge.BrowserTarget{bridge.BrowserTargetSafari},
+		bridge.BrowserCaptureSourceRuntime,
+		1200,
+		bridge.BrowserCaptureMetadata{},
+		minContentLengths{},
+		false,
+	)
+	if err == nil {
+		t.Fatalf("expected extension-only capture to skip the AppleScript fallback")
+	}
+}
+
 func TestRunBrowserCaptureContinuesWhenHostAppAutolaunchFails(t *testing.T) {
 	previousCaptureBrowserFunc := captureBrowserFunc
 	previousEnsureHostAppRunningFunc := ensureHostAppRunningFunc
@@ -176,7 +247,7 @@ func TestRunBrowserCaptureContinuesWhenHostAppAutolaunchFails(t *testing.T) {
 		ensureHostAppRunningFunc = previousEnsureHostAppRunningFunc
 	})
 
-	ensureHostAppRunningFunc = func(context.Context) (bool, error) {
+	ensureHostAppRunningFunc = func(context.Context, ...bridge.HostAppLaunchOption) (bool, error) {
 		return false, os.ErrNotExist
 	}
 	captureBrowserFunc = func(
@@ -274,3 +345,1203 @@ func TestCaptureCommandWritesToDefaultConfiguredPathWhenFileFlagOmitted(t *testi
 		t.Fatalf("expected command output to include saved path, got %q", stdout.String())
 	}
 }
+
+func stubSafariPageText(page osascript.PageText, err error) func() {
+	previous := safariPageTextFunc
+	safariPageTextFunc = func(context.Context, string) (osascript.PageText, error) {
+		return page, err
+	}
+	return func() {
+		safariPageTextFunc = previous
+	}
+}
+
+func TestEncodeBrowserCaptureOutputRawKeepsBridgeEnvelopes(t *testing.T) {
+	rendered, err := encodeBrowserCaptureOutput(
+		captureRequest{outputFormat: formatJSON, raw: true},
+		bridge.BrowserTargetSafari,
+		bridge.BrowserCaptureAttempt{
+			ExtractionMethod: "browser_extension",
+			Markdown:         "# Page",
+			Payload:          map[string]any{"title": "Page"},
+			Normalized:       map[string]any{"source": "safari"},
+			Response:         map[string]any{"ok": true},
+			Request:          map[string]any{"id": "req-1"},
+		},
+		bridge.BrowserCaptureMetadata{},
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("encodeBrowserCaptureOutput returned error: %v", err)
+	}
+	for _, key := range []string{`"normalizedContext"`, `"response"`, `"request"`, `"payload"`} {
+		if !strings.Contains(string(rendered), key) {
+			t.Fatalf("expected raw output to contain %s, got %s", key, rendered)
+		}
+	}
+	if strings.Contains(string(rendered), `"target"`) {
+		t.Fatalf("expected raw output to skip the trimmed view, got %s", rendered)
+	}
+}
+
+func TestCaptureRequestValidateRejectsRawForDesktop(t *testing.T) {
+	_, err := (captureRequest{
+		appName:      "Finder",
+		method:       "auto",
+		timeoutMs:    1200,
+		outputFormat: formatMarkdown,
+		raw:          true,
+	}).validate()
+	if err == nil || !strings.Contains(err.Error(), "--raw") {
+		t.Fatalf("expected --raw error for desktop capture, got %v", err)
+	}
+}
+
+func TestEncodeBrowserCaptureOutputPrependsFrontMatter(t *testing.T) {
+	previousNowFunc := nowFunc
+	t.Cleanup(func() {
+		nowFunc = previousNowFunc
+	})
+	nowFunc = func() time.Time {
+		return time.Date(2026, time.February, 15, 13, 30, 45, 0, time.UTC)
+	}
+
+	rendered, err := encodeBrowserCaptureOutput(
+		captureRequest{outputFormat: formatMarkdown, frontMatter: true},
+		bridge.BrowserTargetChrome,
+		bridge.BrowserCaptureAttempt{
+			ExtractionMethod: "browser_extension",
+			Markdown:         "# Page",
+			Payload:          map[string]any{"title": "Payload Title", "url": "https://payload.example"},
+		},
+		bridge.BrowserCaptureMetadata{Title: `Tab "Title"`},
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("encodeBrowserCaptureOutput returned error: %v", err)
+	}
+	want := "---\n" +
+		"title: \"Tab \\\"Title\\\"\"\n" +
+		"url: \"https://payload.example\"\n" +
+		"browser: chrome\n" +
+		"extractionMethod: \"browser_extension\"\n" +
+		"capturedAt: 2026-02-15T13:30:45Z\n" +
+		"---\n\n" +
+		"# Page\n"
+	if string(rendered) != want {
+		t.Fatalf("unexpected front matter output:\nwant: %q\ngot:  %q", want, string(rendered))
+	}
+}
+
+func TestCaptureTabIgnoreUnreachableEmitsMetadataOnlyCapture(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "chrome", WindowIndex: 1, TabIndex: 1, Title: "Docs", URL: "https://example.com/docs"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "metadata_only", ErrorCode: "ERR_EXTENSION_UNAVAILABLE"}, nil
+	}
+
+	if _, _, err := runRootCommandToFile(t, "capture", "--tab", "1:1"); ExitCode(err) != ExitCodeUnavailable {
+		t.Fatalf("expected unavailable error without --ignore-unreachable, got %v", err)
+	}
+
+	payload, stderr, err := runRootCommandToFile(t, "capture", "--tab", "1:1", "--ignore-unreachable")
+	if err != nil {
+		t.Fatalf("capture --ignore-unreachable returned error: %v", err)
+	}
+	if string(payload) != "# Docs\n\nSource: https://example.com/docs\n" {
+		t.Fatalf("unexpected metadata-only capture: %q", payload)
+	}
+	if !strings.Contains(stderr, "output is metadata only") {
+		t.Fatalf("expected degraded capture warning, got %q", stderr)
+	}
+}
+
+func TestCaptureFocusedExplainsBrowserWithoutWindows(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "metadata_only", ErrorCode: "ERR_EXTENSION_UNAVAILABLE"}, nil
+	}
+
+	windowCount, windowErr := 0, error(nil)
+	browserWindowCountFunc = func(_ context.Context, browser string) (int, error) {
+		if browser != "chrome" {
+			t.Fatalf("expected chrome window count lookup, got %q", browser)
+		}
+		return windowCount, windowErr
+	}
+	_, _, err := runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome")
+	if ExitCode(err) != ExitCodeUnavailable || err.Error() != "Chrome is running but has no open windows" {
+		t.Fatalf("expected no-windows error, got %v", err)
+	}
+
+	windowErr = osascript.ErrBrowserNotRunning
+	_, _, err = runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome")
+	if ExitCode(err) != ExitCodeUnavailable || err.Error() != "Chrome is not running" {
+		t.Fatalf("expected not-running error, got %v", err)
+	}
+
+	windowCount, windowErr = 2, nil
+	_, _, err = runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome")
+	if err == nil || strings.Contains(err.Error(), "open windows") || strings.Contains(err.Error(), "not running") {
+		t.Fatalf("expected the original bridge failure, got %v", err)
+	}
+}
+
+func TestCaptureBrowserWithFallbackRejectsShortContent(t *testing.T) {
+	previousCaptureBrowserFunc := captureBrowserFunc
+	t.Cleanup(func() {
+		captureBrowserFunc = previousCaptureBrowserFunc
+	})
+	chromeMarkdown := "# Full article with plenty of content\n"
+	captureBrowserFunc = func(
+		_ context.Context,
+		target bridge.BrowserTarget,
+		_ bridge.BrowserCaptureSource,
+		_ int,
+		_ bridge.BrowserCaptureMetadata,
+	) (bridge.BrowserCaptureAttempt, error) {
+		markdown := "Loading…"
+		if target == bridge.BrowserTargetChrome {
+			markdown = chromeMarkdown
+		}
+		return bridge.BrowserCaptureAttempt{
+			ExtractionMethod: "browser_extension",
+			Warnings:         []string{},
+			Markdown:         markdown,
+		}, nil
+	}
+
+	targets := []bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome}
+	_, target, _, err := captureBrowserWithFallback(
+		context.Background(), targets, bridge.BrowserCaptureSourceAuto, 1200, bridge.BrowserCaptureMetadata{}, minContentLengths{flag: 20}, false,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target != bridge.BrowserTargetChrome {
+		t.Fatalf("expected fallback to chrome after short safari capture, got %q", target)
+	}
+
+	chromeMarkdown = "tiny"
+	_, _, _, err = captureBrowserWithFallback(
+		context.Background(), targets, bridge.BrowserCaptureSourceAuto, 1200, bridge.BrowserCaptureMetadata{}, minContentLengths{flag: 20}, false,
+	)
+	if err == nil || !strings.Contains(err.Error(), "--min-content-length 20") {
+		t.Fatalf("expected min content length error, got %v", err)
+	}
+
+	// A per-method length from the config replaces the flag's for that
+	// method only.
+	perMethod := minContentLengths{byMethod: map[string]int{"browser_extension": 4}}
+	if _, target, _, err = captureBrowserWithFallback(
+		context.Background(), targets, bridge.BrowserCaptureSourceAuto, 1200, bridge.BrowserCaptureMetadata{}, perMethod, false,
+	); err != nil || target != bridge.BrowserTargetSafari {
+		t.Fatalf("expected the safari capture to pass min-content browser_extension=4, got %q (%v)", target, err)
+	}
+	perMethod.byMethod["browser_extension"] = 20
+	_, _, _, err = captureBrowserWithFallback(
+		context.Background(), targets, bridge.BrowserCaptureSourceAuto, 1200, bridge.BrowserCaptureMetadata{}, perMethod, false,
+	)
+	if err == nil || !strings.Contains(err.Error(), "min-content browser_extension=20") {
+		t.Fatalf("expected per-method min content error, got %v", err)
+	}
+}
+
+func TestFindAppByBundleIDPrefix(t *testing.T) {
+	apps := []osascript.AppEntry{
+		{AppName: "Finder", BundleIdentifier: "com.apple.finder"},
+		{AppName: "Safari", BundleIdentifier: "com.apple.Safari"},
+		{AppName: "Chrome", BundleIdentifier: "com.google.Chrome"},
+		{AppName: "Chrome Helper", BundleIdentifier: "com.google.Chrome"},
+	}
+
+	matched, err := findAppByBundleIDPrefix(apps, "com.google.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matched.BundleIdentifier != "com.google.Chrome" {
+		t.Fatalf("expected chrome match, got %+v", matched)
+	}
+
+	matched, err = findAppByBundleIDPrefix(apps, "com.apple.safari")
+	if err != nil || matched.AppName != "Safari" {
+		t.Fatalf("expected exact bundle id to win, got %+v err=%v", matched, err)
+	}
+
+	if _, err := findAppByBundleIDPrefix(apps, "com.apple."); err == nil || !strings.Contains(err.Error(), "com.apple.finder") {
+		t.Fatalf("expected ambiguous prefix error listing candidates, got %v", err)
+	}
+	if _, err := findAppByBundleIDPrefix(apps, "org.mozilla."); err == nil {
+		t.Fatalf("expected no-match error")
+	}
+}
+
+func TestFindAppByExactName(t *testing.T) {
+	apps := []osascript.AppEntry{
+		{AppName: "Visual Studio Code", BundleIdentifier: "com.microsoft.VSCode"},
+		{AppName: "finder", BundleIdentifier: "example.finder"},
+		{AppName: "Finder", BundleIdentifier: "com.apple.finder"},
+	}
+
+	if matched := findAppByExactName(apps, "Finder"); matched == nil || matched.BundleIdentifier != "com.apple.finder" {
+		t.Fatalf("expected the byte-for-byte match to win, got %+v", matched)
+	}
+	if matched := findAppByExactName(apps, "  visual   studio code "); matched == nil || matched.AppName != "Visual Studio Code" {
+		t.Fatalf("expected case- and whitespace-insensitive match, got %+v", matched)
+	}
+	if matched := findAppByExactName(apps, "Visual"); matched != nil {
+		t.Fatalf("expected no substring match, got %+v", matched)
+	}
+}
+
+func TestCaptureAppResolvesCanonicalName(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder"}}, nil, nil
+	})
+	defer restore()
+	var activated []string
+	activateAppByNameFunc = func(_ context.Context, name string) error {
+		activated = append(activated, name)
+		return nil
+	}
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte("# Finder\n"), nil
+	}
+
+	for _, name := range []string{"finder", "Notes"} {
+		if _, _, err := runRootCommand("capture", "--app", name, "--file", filepath.Join(t.TempDir(), "out.md")); err != nil {
+			t.Fatalf("capture --app %s returned error: %v", name, err)
+		}
+	}
+	if strings.Join(activated, ",") != "Finder,Notes" {
+		t.Fatalf("expected --app finder to activate Finder and an unlisted app to keep its name, got %v", activated)
+	}
+}
+
+func TestCaptureAppIndexTargetsWindow(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 2}}, nil, nil
+	})
+	defer restore()
+	var got bridge.DesktopCaptureRequest
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		got = request
+		return []byte("# Finder\n"), nil
+	}
+
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--app-index", "2", "--file", filepath.Join(t.TempDir(), "out.md")); err != nil {
+		t.Fatalf("capture --app-index returned error: %v", err)
+	}
+	if got.WindowIndex != 2 {
+		t.Fatalf("expected window index 2 in the host request, got %#v", got)
+	}
+
+	_, _, err := runRootCommand("capture", "--app", "Finder", "--app-index", "3")
+	if code := ExitCode(err); code != ExitCodeNoMatch || !strings.Contains(err.Error(), "Finder has 2 window(s)") {
+		t.Fatalf("expected out-of-range no-match error, got %v", err)
+	}
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--app-index", "-1"},
+		{"capture", "--bundle-id", "com.apple.finder", "--app-index", "1"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestCaptureChromeAppTargetsWebAppWindow(t *testing.T) {
+	stubCaptureEnvironment(t)
+	var filters []string
+	restore := stubListSources(func(_ context.Context, browser string) ([]osascript.TabEntry, []string, error) {
+		filters = append(filters, browser)
+		return []osascript.TabEntry{
+			{Browser: "chrome", WindowIndex: 1, TabIndex: 1, IsActive: true, Title: "Search", URL: "https://search.example"},
+			{Browser: "chrome", WindowIndex: 2, TabIndex: 1, IsActive: true, Title: "Inbox", URL: "https://mail.example", WebAppName: "Mail"},
+		}, nil, nil
+	}, nil)
+	defer restore()
+	var activated string
+	activateTabFunc = func(_ context.Context, browser string, windowIndex int, tabIndex int) error {
+		activated = fmt.Sprintf("%s w%d:t%d", browser, windowIndex, tabIndex)
+		return nil
+	}
+	var metadata bridge.BrowserCaptureMetadata
+	captureBrowserFunc = func(
+		_ context.Context,
+		_ bridge.BrowserTarget,
+		_ bridge.BrowserCaptureSource,
+		_ int,
+		captureMetadata bridge.BrowserCaptureMetadata,
+	) (bridge.BrowserCaptureAttempt, error) {
+		metadata = captureMetadata
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Inbox\n"}, nil
+	}
+
+	if _, _, err := runRootCommand("capture", "--chrome-app", "mail", "--file", filepath.Join(t.TempDir(), "out.md")); err != nil {
+		t.Fatalf("capture --chrome-app returned error: %v", err)
+	}
+	if len(filters) != 1 || filters[0] != "chrome" {
+		t.Fatalf("expected only Chrome tabs to be listed, got %v", filters)
+	}
+	if activated != "chrome w2:t1" {
+		t.Fatalf("expected the web app window to be activated, got %q", activated)
+	}
+	if metadata.ChromeAppName != "Google Chrome" || metadata.URL != "https://mail.example" {
+		t.Fatalf("unexpected capture metadata: %#v", metadata)
+	}
+
+	_, _, err := runRootCommand("capture", "--chrome-app", "Music")
+	if ExitCode(err) != ExitCodeNoMatch {
+		t.Fatalf("expected no-match error for an unknown web app, got %v", err)
+	}
+	_, _, err = runRootCommand("capture", "--chrome-app", "Mail", "--browser", "safari")
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for --chrome-app with Safari, got %v", err)
+	}
+}
+
+func TestFindAppByRegex(t *testing.T) {
+	apps := []osascript.AppEntry{
+		{AppName: "Chrome", BundleIdentifier: "com.google.Chrome"},
+		{AppName: "Chrome Helper", BundleIdentifier: "com.google.Chrome"},
+		{AppName: "Safari", BundleIdentifier: "com.apple.Safari"},
+		{AppName: "Safari", BundleIdentifier: "com.apple.Safari"},
+	}
+
+	matched, err := findAppByRegex(apps, regexp.MustCompile(`^Safari$`), false)
+	if err != nil || matched.AppName != "Safari" {
+		t.Fatalf("expected duplicate Safari processes to count as one match, got %+v err=%v", matched, err)
+	}
+
+	pattern := regexp.MustCompile(`^(Safari|Chrome)$`)
+	if _, err := findAppByRegex(apps, pattern, false); err == nil || !strings.Contains(err.Error(), "Chrome, Safari") || ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected ambiguous regex usage error listing apps, got %v", err)
+	}
+	matched, err = findAppByRegex(apps, pattern, true)
+	if err != nil || matched.AppName != "Chrome" {
+		t.Fatalf("expected --first to pick the first match, got %+v err=%v", matched, err)
+	}
+	if _, err := findAppByRegex(apps, regexp.MustCompile(`^Xcode$`), false); ExitCode(err) != ExitCodeNoMatch {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
+
+func TestCaptureRequestValidateRejectsInvalidAppRegex(t *testing.T) {
+	_, err := (captureRequest{
+		appRegex:     "(Safari",
+		method:       "auto",
+		timeoutMs:    1200,
+		outputFormat: formatMarkdown,
+	}).validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid --app-regex pattern") {
+		t.Fatalf("expected invalid pattern error, got %v", err)
+	}
+}
+
+func stubFrontmostApp(entry osascript.AppEntry, err error) func() {
+	previous := frontmostAppFunc
+	previousBrowser := frontmostBrowserFunc
+	frontmostAppFunc = func(context.Context) (osascript.AppEntry, error) {
+		return entry, err
+	}
+	frontmostBrowserFunc = func(context.Context) (string, error) {
+		if err != nil {
+			return "", err
+		}
+		app, _ := osascript.LookupBrowserByBundleID(entry.BundleIdentifier)
+		return app.Target, nil
+	}
+	return func() {
+		frontmostAppFunc = previous
+		frontmostBrowserFunc = previousBrowser
+	}
+}
+
+func TestCaptureTabPrefersFrontmostBrowserWhenIndexIsAmbiguous(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Safari Tab", URL: "https://example.com/safari"},
+				{Browser: "chrome", WindowIndex: 1, TabIndex: 1, Title: "Chrome Tab", URL: "https://example.com/chrome"},
+			}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	var captured bridge.BrowserTarget
+	captureBrowserFunc = func(_ context.Context, target bridge.BrowserTarget, _ bridge.BrowserCaptureSource, _ int, _ bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		captured = target
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Tab\n"}, nil
+	}
+
+	if _, _, err := runRootCommandToFile(t, "capture", "--tab", "1:1"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected ambiguity error when no browser is frontmost, got %v", err)
+	}
+
+	t.Cleanup(stubFrontmostApp(osascript.AppEntry{AppName: "Google Chrome", BundleIdentifier: "com.google.Chrome"}, nil))
+	if _, _, err := runRootCommandToFile(t, "capture", "--tab", "1:1"); err != nil {
+		t.Fatalf("capture --tab returned error: %v", err)
+	}
+	if captured != bridge.BrowserTargetChrome {
+		t.Fatalf("expected frontmost chrome tab to be captured, got %q", captured)
+	}
+}
+
+func TestFocusedTargetOrderPrefersFrontmostBrowser(t *testing.T) {
+	restore := stubFrontmostApp(osascript.AppEntry{AppName: "Google Chrome", BundleIdentifier: "com.google.Chrome"}, nil)
+	defer restore()
+
+	order := focusedTargetOrder(context.Background(), "", nil)
+	if len(order) != 2 || order[0] != bridge.BrowserTargetChrome || order[1] != bridge.BrowserTargetSafari {
+		t.Fatalf("expected chrome first, got %v", order)
+	}
+}
+
+func TestFocusedTargetOrderFallsBackWhenFrontmostIsNotBrowser(t *testing.T) {
+	restore := stubFrontmostApp(osascript.AppEntry{AppName: "Finder", BundleIdentifier: "com.apple.finder"}, nil)
+	defer restore()
+	order := focusedTargetOrder(context.Background(), "", nil)
+	if len(order) != 2 || order[0] != bridge.BrowserTargetSafari {
+		t.Fatalf("expected fixed order, got %v", order)
+	}
+
+	restoreErr := stubFrontmostApp(osascript.AppEntry{}, errors.New("osascript failed"))
+	defer restoreErr()
+	order = focusedTargetOrder(context.Background(), bridge.BrowserTargetChrome, nil)
+	if len(order) != 1 || order[0] != bridge.BrowserTargetChrome {
+		t.Fatalf("expected override to win, got %v", order)
+	}
+}
+
+func TestFocusedTargetOrderPutsFrontmostChannelFirst(t *testing.T) {
+	restore := stubFrontmostApp(osascript.AppEntry{AppName: "Safari Technology Preview", BundleIdentifier: "com.apple.SafariTechnologyPreview"}, nil)
+	defer restore()
+
+	order := focusedTargetOrder(context.Background(), "", nil)
+	if len(order) != 3 || order[0] != bridge.BrowserTargetSafariTP {
+		t.Fatalf("expected safari-tp first, got %v", order)
+	}
+}
+
+func TestFocusedTargetOrderHonorsPreferredOrder(t *testing.T) {
+	restore := stubFrontmostApp(osascript.AppEntry{AppName: "Google Chrome Beta", BundleIdentifier: "com.google.Chrome.beta"}, nil)
+	defer restore()
+	preferred := []bridge.BrowserTarget{bridge.BrowserTargetChrome, bridge.BrowserTargetSafari}
+
+	order := focusedTargetOrder(context.Background(), "", preferred)
+	if len(order) != 2 || order[0] != bridge.BrowserTargetChrome || order[1] != bridge.BrowserTargetSafari {
+		t.Fatalf("expected unlisted frontmost browser to be ignored, got %v", order)
+	}
+
+	order = focusedTargetOrder(context.Background(), bridge.BrowserTargetSafari, preferred)
+	if len(order) != 2 || order[0] != bridge.BrowserTargetSafari {
+		t.Fatalf("expected listed override to move first, got %v", order)
+	}
+
+	order = focusedTargetOrder(context.Background(), bridge.BrowserTargetChromeDev, preferred)
+	if len(order) != 2 || order[0] != bridge.BrowserTargetChrome {
+		t.Fatalf("expected unlisted override to be ignored, got %v", order)
+	}
+}
+
+func TestParseTargetOrder(t *testing.T) {
+	order, err := parseTargetOrder(" Chrome, safari,,chrome ")
+	if err != nil {
+		t.Fatalf("parseTargetOrder returned error: %v", err)
+	}
+	if len(order) != 2 || order[0] != bridge.BrowserTargetChrome || order[1] != bridge.BrowserTargetSafari {
+		t.Fatalf("expected chrome,safari, got %v", order)
+	}
+	if _, err := parseTargetOrder("chrome,firefox"); err == nil || !strings.Contains(err.Error(), "firefox") {
+		t.Fatalf("expected unsupported browser error, got %v", err)
+	}
+	if _, err := parseTargetOrder(" , "); err == nil {
+		t.Fatal("expected error for empty target order")
+	}
+}
+
+func TestCaptureTargetOrderRequiresFocused(t *testing.T) {
+	request := captureRequest{tabReference: "1:1", targetOrder: "chrome", timeoutMs: 1, outputFormat: formatMarkdown}
+	if _, err := request.validate(); err == nil || !strings.Contains(err.Error(), "--focused") {
+		t.Fatalf("expected --focused error, got %v", err)
+	}
+	request = captureRequest{focused: true, browser: "safari", targetOrder: "chrome", timeoutMs: 1, outputFormat: formatMarkdown}
+	if _, err := request.validate(); err == nil || !strings.Contains(err.Error(), "--browser") {
+		t.Fatalf("expected --browser conflict error, got %v", err)
+	}
+}
+
+func TestParseOptionalBrowserTargetAcceptsChannels(t *testing.T) {
+	target, err := parseOptionalBrowserTarget(" Chrome-Canary ")
+	if err != nil || target != bridge.BrowserTargetChromeCanary {
+		t.Fatalf("expected chrome-canary, got %q (%v)", target, err)
+	}
+	if _, err := parseOptionalBrowserTarget("firefox"); err == nil || !strings.Contains(err.Error(), "safari-tp") {
+		t.Fatalf("expected error listing supported browsers, got %v", err)
+	}
+}
+
+func TestBrowserAllSelectsAutomatically(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_BROWSER_TARGET", "chrome")
+	target, err := resolveBrowserTargetOverride(captureRequest{browser: "all"})
+	if err != nil || target != "" {
+		t.Fatalf("expected --browser all to ignore the env override, got %q (%v)", target, err)
+	}
+	target, err = resolveBrowserTargetOverride(captureRequest{})
+	if err != nil || target != bridge.BrowserTargetChrome {
+		t.Fatalf("expected env override without --browser, got %q (%v)", target, err)
+	}
+	if _, err := parseOptionalBrowserTarget("firefox"); err == nil || !strings.Contains(err.Error(), "all, safari") {
+		t.Fatalf("expected error listing all, got %v", err)
+	}
+}
+
+func TestResolveTargetTabTitleMatchRequiresDisambiguation(t *testing.T) {
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "Inbox", URL: "https://mail.example.com"},
+				{Browser: "chrome", WindowIndex: 2, TabIndex: 1, Title: "Inbox (3)", URL: "https://mail.example.org"},
+			}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	request := captureRequest{titleMatch: "inbox"}
+	_, err := resolveTargetTab(context.Background(), request, "", io.Discard)
+	if err == nil {
+		t.Fatalf("expected ambiguous --title-match error")
+	}
+	for _, want := range []string{"safari w1:t2 - https://mail.example.com", "chrome w2:t1 - https://mail.example.org"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+
+	request.first = true
+	tab, err := resolveTargetTab(context.Background(), request, "", io.Discard)
+	if err != nil {
+		t.Fatalf("expected --first to pick a tab, got %v", err)
+	}
+	if tab.Browser != "safari" || tab.TabIndex != 2 {
+		t.Fatalf("expected first match, got %#v", tab)
+	}
+}
+
+func TestCaptureNoSaveRequiresClipboardWithoutFile(t *testing.T) {
+	previousDesktop := captureDesktopFunc
+	t.Cleanup(func() {
+		captureDesktopFunc = previousDesktop
+	})
+	captured := false
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		captured = true
+		return []byte("# Finder\n"), nil
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--no-save"},
+		{"capture", "--app", "Finder", "--no-save", "--clipboard", "--file", filepath.Join(t.TempDir(), "out.md")},
+	} {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+	if captured {
+		t.Fatalf("expected --no-save validation to fail before capturing")
+	}
+}
+
+func TestCaptureMethodPDFWritesToFile(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "chrome", WindowIndex: 1, TabIndex: 2, Title: "Docs", URL: "https://example.com/docs"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	previous := renderURLToPDFFunc
+	t.Cleanup(func() {
+		renderURLToPDFFunc = previous
+	})
+	var gotURL, gotPath string
+	renderURLToPDFFunc = func(_ context.Context, target bridge.BrowserTarget, pageURL string, outputPath string, _ int) (bridge.BrowserCaptureAttempt, error) {
+		gotURL, gotPath = pageURL, outputPath
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "url_pdf", Payload: map[string]any{"path": outputPath}}, nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "docs.pdf")
+	stdout, _, err := runRootCommand("capture", "--tab", "1:2", "--method", "url-pdf", "--file", outputPath)
+	if err != nil {
+		t.Fatalf("capture --method url-pdf returned error: %v", err)
+	}
+	if gotURL != "https://example.com/docs" || gotPath != outputPath {
+		t.Fatalf("unexpected pdf request: url=%q path=%q", gotURL, gotPath)
+	}
+	if !strings.Contains(stdout, "Saved capture to "+outputPath) {
+		t.Fatalf("expected saved path on stdout, got %q", stdout)
+	}
+
+	gotURL, gotPath = "", ""
+	aliasPath := filepath.Join(t.TempDir(), "alias.pdf")
+	if _, _, err := runRootCommand("capture", "--tab", "1:2", "--method", "pdf", "--file", aliasPath); err != nil {
+		t.Fatalf("capture --method pdf returned error: %v", err)
+	}
+	if gotURL != "https://example.com/docs" || gotPath != aliasPath {
+		t.Fatalf("expected --method pdf to alias url-pdf: url=%q path=%q", gotURL, gotPath)
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--focused", "--method", "url-pdf"},
+		{"capture", "--tab", "1:2", "--method", "url-pdf", "--clipboard"},
+		{"capture", "--tab", "1:2", "--method", "pdf", "--browser", "safari"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestCaptureMethodPDFRejectsSafariTab(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Docs", URL: "https://example.com/docs"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	previous := renderURLToPDFFunc
+	t.Cleanup(func() {
+		renderURLToPDFFunc = previous
+	})
+	renderURLToPDFFunc = func(context.Context, bridge.BrowserTarget, string, string, int) (bridge.BrowserCaptureAttempt, error) {
+		t.Fatalf("expected a Safari tab not to be rendered")
+		return bridge.BrowserCaptureAttempt{}, nil
+	}
+
+	_, _, err := runRootCommand("capture", "--url-match", "example.com", "--method", "pdf", "--file", filepath.Join(t.TempDir(), "docs.pdf"))
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(err.Error(), "does not support Safari") {
+		t.Fatalf("expected a Safari usage error, got %v", err)
+	}
+}
+
+func TestCaptureURLOpensAndClosesTemporaryTab(t *testing.T) {
+	stubCaptureEnvironment(t)
+	previousOpen, previousClose := openTabFunc, closeTabFunc
+	t.Cleanup(func() {
+		openTabFunc, closeTabFunc = previousOpen, previousClose
+	})
+	var calls []string
+	openTabFunc = func(_ context.Context, browser string, pageURL string) (osascript.OpenedTab, error) {
+		calls = append(calls, "open "+browser+" "+pageURL)
+		return osascript.OpenedTab{
+			TabEntry: osascript.TabEntry{Browser: browser, WindowIndex: 1, TabIndex: 4, URL: pageURL},
+			TabID:    "812",
+		}, nil
+	}
+	closeTabFunc = func(_ context.Context, tab osascript.OpenedTab) error {
+		calls = append(calls, fmt.Sprintf("close %s tab %s", tab.Browser, tab.TabID))
+		return nil
+	}
+	activateTabFunc = func(_ context.Context, browser string, windowIndex int, tabIndex int) error {
+		calls = append(calls, fmt.Sprintf("activate %s w%d:t%d", browser, windowIndex, tabIndex))
+		return nil
+	}
+	captureErr := error(nil)
+	captureBrowserFunc = func(_ context.Context, _ bridge.BrowserTarget, _ bridge.BrowserCaptureSource, _ int, metadata bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		calls = append(calls, "capture "+metadata.URL)
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Docs\n"}, captureErr
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--url", "https://example.com/docs", "--browser", "chrome")
+	if err != nil {
+		t.Fatalf("capture --url returned error: %v", err)
+	}
+	want := []string{
+		"open chrome https://example.com/docs",
+		"activate chrome w1:t4",
+		"capture https://example.com/docs",
+		"close chrome tab 812",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") || string(payload) != "# Docs\n" {
+		t.Fatalf("unexpected --url flow %q with payload %q", calls, payload)
+	}
+
+	calls, captureErr = nil, errors.New("bridge crashed")
+	if _, _, err := runRootCommandToFile(t, "capture", "--url", "https://example.com/docs", "--browser", "chrome"); err == nil {
+		t.Fatalf("expected capture failure")
+	}
+	if len(calls) == 0 || calls[len(calls)-1] != "close chrome tab 812" {
+		t.Fatalf("expected the temporary tab to be closed after a failed capture, got %q", calls)
+	}
+
+	calls = nil
+	for _, args := range [][]string{
+		{"capture", "--url", "example.com/docs"},
+		{"capture", "--url", "file:///etc/hosts"},
+		{"capture", "--url", "https://example.com", "--tab", "1:1"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+	if len(calls) != 0 {
+		t.Fatalf("expected invalid --url requests to open nothing, got %q", calls)
+	}
+}
+
+func TestCaptureURLWithMethodPDFRendersAddressDirectly(t *testing.T) {
+	stubCaptureEnvironment(t)
+	previousOpen, previousPDF := openTabFunc, renderURLToPDFFunc
+	t.Cleanup(func() {
+		openTabFunc, renderURLToPDFFunc = previousOpen, previousPDF
+	})
+	openTabFunc = func(context.Context, string, string) (osascript.OpenedTab, error) {
+		t.Fatalf("expected --method url-pdf not to open a tab")
+		return osascript.OpenedTab{}, nil
+	}
+	var gotTarget bridge.BrowserTarget
+	var gotURL string
+	renderURLToPDFFunc = func(_ context.Context, target bridge.BrowserTarget, pageURL string, outputPath string, _ int) (bridge.BrowserCaptureAttempt, error) {
+		gotTarget, gotURL = target, pageURL
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "url_pdf", Payload: map[string]any{"path": outputPath}}, nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "docs.pdf")
+	if _, _, err := runRootCommand("capture", "--url", "https://example.com/docs", "--method", "url-pdf", "--file", outputPath); err != nil {
+		t.Fatalf("capture --url --method url-pdf returned error: %v", err)
+	}
+	if gotTarget != bridge.BrowserTargetChrome || gotURL != "https://example.com/docs" {
+		t.Fatalf("unexpected pdf request: target=%q url=%q", gotTarget, gotURL)
+	}
+}
+
+func TestCaptureSaveAsWritesNamedFileWithSuffix(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureDir := setupCaptureHistory(t, "meeting-notes.md")
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte("# Finder\n"), nil
+	}
+
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--save-as", "meeting notes/"); err != nil {
+		t.Fatalf("capture --save-as returned error: %v", err)
+	}
+	written, readErr := os.ReadFile(filepath.Join(captureDir, "meeting-notes-2.md"))
+	if readErr != nil {
+		t.Fatalf("expected suffixed capture file: %v", readErr)
+	}
+	if string(written) != "# Finder\n" {
+		t.Fatalf("unexpected capture content %q", written)
+	}
+
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--save-as", "meeting-notes", "--overwrite"); err != nil {
+		t.Fatalf("capture --save-as --overwrite returned error: %v", err)
+	}
+	if overwritten, _ := os.ReadFile(filepath.Join(captureDir, "meeting-notes.md")); string(overwritten) != "# Finder\n" {
+		t.Fatalf("expected --overwrite to replace meeting-notes.md, got %q", overwritten)
+	}
+}
+
+func TestCaptureSaveAsValidatesFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--overwrite"},
+		{"capture", "--app", "Finder", "--save-as", "notes", "--file", filepath.Join(t.TempDir(), "out.md")},
+		{"capture", "--app", "Finder", "--save-as", "///"},
+	} {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+	if got := sanitizeCaptureName("../Q3 plan: draft"); got != "Q3-plan-draft" {
+		t.Fatalf("unexpected sanitized name %q", got)
+	}
+}
+
+func TestCaptureFocusedAppUsesFrontmostApp(t *testing.T) {
+	stubCaptureEnvironment(t)
+	t.Cleanup(stubFrontmostApp(osascript.AppEntry{AppName: "Notes", BundleIdentifier: "com.apple.Notes"}, nil))
+	previousActivate := activateAppByBundleFunc
+	t.Cleanup(func() {
+		activateAppByBundleFunc = previousActivate
+	})
+	activateAppByBundleFunc = func(context.Context, string) error {
+		t.Fatalf("expected the frontmost app not to be re-activated")
+		return nil
+	}
+	var got bridge.DesktopCaptureRequest
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		got = request
+		return []byte("# Notes\n"), nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "notes.md")
+	if _, _, err := runRootCommand("capture", "--focused-app", "--file", outputPath); err != nil {
+		t.Fatalf("capture --focused-app returned error: %v", err)
+	}
+	if got.AppName != "Notes" || got.BundleIdentifier != "com.apple.Notes" {
+		t.Fatalf("unexpected desktop capture request: %#v", got)
+	}
+
+	_, _, err := runRootCommand("capture", "--focused-app", "--app", "Finder")
+	if code := ExitCode(err); code != ExitCodeUsage {
+		t.Fatalf("expected usage error for --focused-app with --app, got %v", err)
+	}
+}
+
+func TestCaptureOpenAndRevealWrittenFile(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte("# Finder\n"), nil
+	}
+	previousOpen := openCaptureFileFunc
+	t.Cleanup(func() {
+		openCaptureFileFunc = previousOpen
+	})
+	var openedPath string
+	var revealed bool
+	openCaptureFileFunc = func(_ context.Context, path string, reveal bool) error {
+		openedPath = path
+		revealed = reveal
+		return errors.New("open unavailable")
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "finder.md")
+	_, stderr, err := runRootCommand("capture", "--app", "Finder", "--file", outputPath, "--reveal")
+	if err != nil {
+		t.Fatalf("expected open failure to be non-fatal, got %v", err)
+	}
+	if openedPath != outputPath || !revealed {
+		t.Fatalf("expected reveal of %s, got path=%q reveal=%v", outputPath, openedPath, revealed)
+	}
+	if !strings.Contains(stderr, "warning: unable to open") {
+		t.Fatalf("expected open warning, got %q", stderr)
+	}
+
+	openedPath = ""
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--file", outputPath); err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+	if openedPath != "" {
+		t.Fatalf("expected no open without --open, got %q", openedPath)
+	}
+
+	_, _, err = runRootCommand("capture", "--app", "Finder", "--open", "--reveal")
+	if code := ExitCode(err); code != ExitCodeUsage {
+		t.Fatalf("expected usage error for --open with --reveal, got %v", err)
+	}
+}
+
+func TestCaptureIncludeBoundsRequestsHostGeometry(t *testing.T) {
+	stubCaptureEnvironment(t)
+	var got bridge.DesktopCaptureRequest
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		got = request
+		return []byte(`{"markdown":"# Finder"}`), nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "finder.json")
+	_, stderr, err := runRootCommand("capture", "--app", "Finder", "--format", "json", "--include-bounds", "--file", outputPath)
+	if err != nil {
+		t.Fatalf("capture --include-bounds returned error: %v", err)
+	}
+	if !got.IncludeBounds {
+		t.Fatalf("expected IncludeBounds in desktop request, got %#v", got)
+	}
+	if !strings.Contains(stderr, "did not report window bounds for Finder") {
+		t.Fatalf("expected missing bounds warning, got %q", stderr)
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--include-bounds"},
+		{"capture", "--focused", "--format", "json", "--include-bounds"},
+	} {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestCaptureBrowserWithFallbackRecordsAttempts(t *testing.T) {
+	previousCaptureBrowserFunc := captureBrowserFunc
+	t.Cleanup(func() {
+		captureBrowserFunc = previousCaptureBrowserFunc
+	})
+	captureBrowserFunc = func(
+		_ context.Context,
+		target bridge.BrowserTarget,
+		_ bridge.BrowserCaptureSource,
+		_ int,
+		_ bridge.BrowserCaptureMetadata,
+	) (bridge.BrowserCaptureAttempt, error) {
+		if target == bridge.BrowserTargetSafari {
+			return bridge.BrowserCaptureAttempt{ExtractionMethod: "metadata_only", ErrorCode: "ERR_EXTENSION_UNAVAILABLE"}, nil
+		}
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Chrome\n"}, nil
+	}
+
+	attempt, target, attempts, err := captureBrowserWithFallback(
+		context.Background(),
+		[]bridge.BrowserTarget{bridge.BrowserTargetSafari, bridge.BrowserTargetChrome},
+		bridge.BrowserCaptureSourceRuntime,
+		1200,
+		bridge.BrowserCaptureMetadata{},
+		minContentLengths{},
+		false,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []captureAttemptRecord{
+		{Target: "safari", ExtractionMethod: "metadata_only", ErrorCode: "ERR_EXTENSION_UNAVAILABLE"},
+		{Target: "chrome", ExtractionMethod: "browser_extension", Chosen: true},
+	}
+	if len(attempts) != len(want) || attempts[0] != want[0] || attempts[1] != want[1] {
+		t.Fatalf("unexpected attempts: %#v", attempts)
+	}
+
+	rendered, err := encodeBrowserCaptureOutput(captureRequest{outputFormat: formatJSON}, target, attempt, bridge.BrowserCaptureMetadata{}, attempts, nil)
+	if err != nil {
+		t.Fatalf("encodeBrowserCaptureOutput returned error: %v", err)
+	}
+	var decoded browserCaptureOutput
+	if err := json.Unmarshal(rendered, &decoded); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(decoded.Attempts) != 2 || !decoded.Attempts[1].Chosen {
+		t.Fatalf("expected attempts in JSON output, got %s", rendered)
+	}
+}
+
+func TestCaptureYAMLFormatRejectsFrontMatter(t *testing.T) {
+	_, err := (captureRequest{
+		focused:      true,
+		method:       "auto",
+		timeoutMs:    1200,
+		outputFormat: formatYAML,
+		frontMatter:  true,
+	}).validate()
+	if err == nil || !strings.Contains(err.Error(), "--front-matter applies only to --format markdown") {
+		t.Fatalf("expected front matter error for yaml, got %v", err)
+	}
+	if got := captureOutputExtension(formatYAML, "auto"); got != ".yaml" {
+		t.Fatalf("unexpected yaml extension %q", got)
+	}
+}
+
+func TestCaptureOutputExtensionAndFileMismatchWarning(t *testing.T) {
+	cases := []struct {
+		format string
+		method string
+		want   string
+	}{
+		{formatMarkdown, "auto", ".md"},
+		{formatJSON, "extension", ".json"},
+		{formatYAML, "ax", ".yaml"},
+		{formatMarkdown, browserMethodURLPDF, ".pdf"},
+	}
+	for _, tc := range cases {
+		if got := captureOutputExtension(tc.format, tc.method); got != tc.want {
+			t.Fatalf("captureOutputExtension(%q, %q) = %q, want %q", tc.format, tc.method, got, tc.want)
+		}
+	}
+
+	if warning := captureFileExtensionWarning("notes.md", formatJSON, "auto"); !strings.Contains(warning, "notes.md has a .md extension but this capture writes json content") {
+		t.Fatalf("expected mismatch warning, got %q", warning)
+	}
+	for _, path := range []string{"notes.json.gz", "notes.yml", "notes.txt", "notes"} {
+		format := formatJSON
+		if path == "notes.yml" {
+			format = formatYAML
+		}
+		if warning := captureFileExtensionWarning(path, format, "auto"); warning != "" {
+			t.Fatalf("expected no warning for %s, got %q", path, warning)
+		}
+	}
+}
+
+func TestCaptureWarnsWhenFileExtensionMismatchesFormat(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte(`{"markdown":"# Finder"}`), nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "finder.md")
+	_, stderr, err := runRootCommand("capture", "--app", "Finder", "--format", "json", "--file", outputPath)
+	if err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+	if !strings.Contains(stderr, "has a .md extension but this capture writes json content") {
+		t.Fatalf("expected extension warning, got %q", stderr)
+	}
+}
+
+func TestCaptureDiffComparesWithPreviousCaptureOfSameTarget(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureDir := setupCaptureHistory(t)
+	current := time.Date(2026, time.February, 15, 12, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+	body := "# Finder\nfirst\n"
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		if request.AppName == "Notes" {
+			return []byte("# Notes\n"), nil
+		}
+		return []byte(body), nil
+	}
+
+	_, stderr, err := runRootCommand("capture", "--app", "Finder", "--diff")
+	if err != nil {
+		t.Fatalf("first capture returned error: %v", err)
+	}
+	if !strings.Contains(stderr, "no previous capture of this target") {
+		t.Fatalf("expected missing baseline warning, got %q", stderr)
+	}
+	if _, _, err := runRootCommand("capture", "--app", "Notes"); err != nil {
+		t.Fatalf("notes capture returned error: %v", err)
+	}
+
+	body = "# Finder\nsecond\n"
+	stdout, _, err := runRootCommand("capture", "--app", "Finder", "--diff-only")
+	if err != nil {
+		t.Fatalf("diff capture returned error: %v", err)
+	}
+	if !strings.Contains(stdout, "-first\n+second\n") || strings.Contains(stdout, "Saved capture to") {
+		t.Fatalf("expected diff against previous Finder capture only, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "--- "+captureDir) {
+		t.Fatalf("expected diff header naming the previous capture, got %q", stdout)
+	}
+
+	_, stderr, err = runRootCommand("capture", "--app", "Finder", "--diff")
+	if err != nil || !strings.Contains(stderr, "No changes since") {
+		t.Fatalf("expected unchanged capture notice, got stderr=%q err=%v", stderr, err)
+	}
+
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--diff", "--format", "yaml"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for --diff with yaml, got %v", err)
+	}
+}
+
+func TestCaptureNoHostLaunchSkipsHostApp(t *testing.T) {
+	stubCaptureEnvironment(t)
+	launches := 0
+	ensureHostAppRunningFunc = func(context.Context, ...bridge.HostAppLaunchOption) (bool, error) {
+		launches++
+		return true, nil
+	}
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Page\n"}, nil
+	}
+
+	if _, _, err := runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome", "--method", "applescript", "--no-host-launch"); err != nil {
+		t.Fatalf("capture --no-host-launch returned error: %v", err)
+	}
+	if launches != 0 {
+		t.Fatalf("expected no host app launch, got %d", launches)
+	}
+	if _, _, err := runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome"); err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+	if launches != 1 {
+		t.Fatalf("expected the host app to be launched by default, got %d launches", launches)
+	}
+
+	_, _, err := runRootCommand("capture", "--app", "Finder", "--no-host-launch")
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected --no-host-launch with desktop capture to be a usage error, got %v", err)
+	}
+}
+
+func TestCaptureAlsoMetadataPrependsSourceLine(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "chrome", WindowIndex: 1, TabIndex: 1, Title: "Docs", URL: "https://example.com/docs"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Page\n\nBody text that is long enough to trim.\n"}, nil
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--tab", "1:1", "--also-metadata", "--max-chars", "20")
+	if err != nil {
+		t.Fatalf("capture --also-metadata returned error: %v", err)
+	}
+	if !strings.HasPrefix(string(payload), "> Source: Docs — https://example.com/docs\n\n# Page") {
+		t.Fatalf("expected the source line to survive truncation, got %q", payload)
+	}
+
+	if got := renderCaptureSourceLine(bridge.BrowserCaptureAttempt{Payload: map[string]any{"url": "https://example.com"}}, bridge.BrowserCaptureMetadata{}); got != "> Source: https://example.com\n\n" {
+		t.Fatalf("unexpected URL-only source line: %q", got)
+	}
+	if got := renderCaptureSourceLine(bridge.BrowserCaptureAttempt{}, bridge.BrowserCaptureMetadata{}); got != "" {
+		t.Fatalf("expected no source line without metadata, got %q", got)
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--also-metadata"},
+		{"capture", "--tab", "1:1", "--raw", "--also-metadata"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected a usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestCaptureSelectionRequestsSelectionSource(t *testing.T) {
+	stubCaptureEnvironment(t)
+	method := "selection"
+	captureBrowserFunc = func(_ context.Context, _ bridge.BrowserTarget, source bridge.BrowserCaptureSource, _ int, _ bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		if source != bridge.BrowserCaptureSourceSelection {
+			t.Fatalf("expected selection source, got %q", source)
+		}
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: method, Markdown: "Selected words\n"}, nil
+	}
+
+	payload, stderr, err := runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome", "--selection")
+	if err != nil {
+		t.Fatalf("capture --selection returned error: %v", err)
+	}
+	if string(payload) != "Selected words\n" || stderr != "" {
+		t.Fatalf("unexpected selection capture: %q (stderr %q)", payload, stderr)
+	}
+
+	method = "browser_extension"
+	if _, stderr, err = runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome", "--selection"); err != nil {
+		t.Fatalf("capture --selection returned error: %v", err)
+	}
+	if !strings.Contains(stderr, "output is the full page (--selection)") {
+		t.Fatalf("expected full-page fallback warning, got %q", stderr)
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--selection"},
+		{"capture", "--focused", "--selection", "--method", "applescript"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected a usage error, got %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/textdiff"
+)
+
+// captureTargetsFileName is the capture-dir index mapping auto-saved capture
+// file names to the target they captured, so --diff can find the previous
+// capture of the same target among timestamp-named files.
+const captureTargetsFileName = "targets.json"
+
+// captureTargetKey identifies what a request captures by its selectors
+// (e.g. "app=Finder" or "browser=safari urlMatch=github"), independent of
+// method and format.
+func captureTargetKey(request captureRequest, mode captureMode) string {
+	fields := request.eventFields(mode)
+	delete(fields, "mode")
+	delete(fields, "method")
+	parts := make([]string, 0, len(fields))
+	for key, value := range fields {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+func readCaptureTargets(captureDir string) (map[string]string, error) {
+	targets := map[string]string{}
+	raw, err := os.ReadFile(filepath.Join(captureDir, captureTargetsFileName))
+	if os.IsNotExist(err) {
+		return targets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read capture targets: %w", err)
+	}
+	if err := json.Unmarshal(raw, &targets); err != nil {
+		return nil, fmt.Errorf("parse capture targets: %w", err)
+	}
+	return targets, nil
+}
+
+// recordCaptureTarget adds an auto-saved capture to the targets index.
+// Entries for files that no longer exist are dropped on the way.
+func recordCaptureTarget(captureDir string, capturePath string, key string) error {
+	targets, err := readCaptureTargets(captureDir)
+	if err != nil {
+		return err
+	}
+	for name := range targets {
+		if _, err := os.Stat(filepath.Join(captureDir, name)); err != nil {
+			delete(targets, name)
+		}
+	}
+	targets[filepath.Base(capturePath)] = key
+	encoded, err := json.MarshalIndent(targets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode capture targets: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(captureDir, captureTargetsFileName), append(encoded, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write capture targets: %w", err)
+	}
+	return nil
+}
+
+// findPreviousCapture returns the newest auto-saved capture of key in
+// format, or false when there is none.
+func findPreviousCapture(captureDir string, key string, format string) (historyEntry, bool, error) {
+	targets, err := readCaptureTargets(captureDir)
+	if err != nil {
+		return historyEntry{}, false, err
+	}
+	entries, err := scanCaptureHistory(captureDir)
+	if err != nil {
+		return historyEntry{}, false, err
+	}
+	for _, entry := range entries {
+		if entry.Format == format && targets[filepath.Base(entry.Path)] == key {
+			return entry, true, nil
+		}
+	}
+	return historyEntry{}, false, nil
+}
+
+// renderCaptureDiff returns a unified diff from the previous capture to the
+// one just saved at currentPath, or "" when nothing changed. Both sides are
+// read back from disk so --gzip and --envelope output compare like for like.
+func renderCaptureDiff(previous historyEntry, currentPath string) (string, error) {
+	previousContent, err := readSavedCapture(previous.Path)
+	if err != nil {
+		return "", err
+	}
+	currentContent, err := readSavedCapture(currentPath)
+	if err != nil {
+		return "", err
+	}
+	return textdiff.Unified(previous.Path, currentPath, string(previousContent), string(currentContent))
+}
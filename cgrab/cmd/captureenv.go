@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// captureEnvPrefix starts the environment variables that stand in for
+// capture flags: --app reads CGRAB_CAPTURE_APP, --timeout-ms reads
+// CGRAB_CAPTURE_TIMEOUT_MS, and so on.
+const captureEnvPrefix = "CGRAB_CAPTURE_"
+
+func captureEnvVar(flagName string) string {
+	return captureEnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyCaptureEnvDefaults fills every capture flag that was not passed on
+// the command line from its CGRAB_CAPTURE_* variable, so precedence is flag,
+// then environment, then config. The flag is not marked as changed, so an
+// environment value behaves like a default (e.g. --batch still accepts it).
+func applyCaptureEnvDefaults(flags *pflag.FlagSet) error {
+	var applyErr error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if applyErr != nil || flag.Changed || flag.Name == "help" {
+			return
+		}
+		envVar := captureEnvVar(flag.Name)
+		value, ok := os.LookupEnv(envVar)
+		if !ok || strings.TrimSpace(value) == "" {
+			return
+		}
+		if err := flag.Value.Set(strings.TrimSpace(value)); err != nil {
+			applyErr = fmt.Errorf("invalid %s value %q for --%s: %w", envVar, value, flag.Name, err)
+		}
+	})
+	return applyErr
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+)
+
+func TestCaptureReadsUnsetFlagsFromEnvironment(t *testing.T) {
+	stubCaptureEnvironment(t)
+	var requests []bridge.DesktopCaptureRequest
+	captureDesktopFunc = func(_ context.Context, request bridge.DesktopCaptureRequest) ([]byte, error) {
+		requests = append(requests, request)
+		return []byte("# Captured\n"), nil
+	}
+	t.Setenv("CGRAB_CAPTURE_APP", "Finder")
+	t.Setenv("CGRAB_CAPTURE_METHOD", "ax")
+
+	if _, _, err := runRootCommandToFile(t, "capture"); err != nil {
+		t.Fatalf("capture from environment returned error: %v", err)
+	}
+	if _, _, err := runRootCommandToFile(t, "capture", "--app", "Notes", "--method", "ocr"); err != nil {
+		t.Fatalf("capture with flags returned error: %v", err)
+	}
+	if len(requests) != 2 ||
+		requests[0].AppName != "Finder" || requests[0].Method != bridge.DesktopCaptureMethodAX ||
+		requests[1].AppName != "Notes" || requests[1].Method != bridge.DesktopCaptureMethodOCR {
+		t.Fatalf("expected env values only where flags were unset, got %+v", requests)
+	}
+
+	t.Setenv("CGRAB_CAPTURE_INCLUDE_BOUNDS", "maybe")
+	if _, _, err := runRootCommand("capture"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for an invalid env value, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// addCaptureHash annotates a rendered capture with the SHA-256 of its
+// bytes for --hash: a trailing "<!-- sha256: <hex> -->" line in markdown,
+// or a last "sha256" field in JSON and YAML. The digest covers the capture
+// exactly as written, without the annotation, so JSON is first laid out the
+// way output.Write will leave it: compact when compact mirrors
+// --pretty=false, indented otherwise.
+func addCaptureHash(rendered []byte, format string, compact bool) ([]byte, error) {
+	if format == formatJSON {
+		normalized, err := layoutJSONDocument(rendered, compact)
+		if err != nil {
+			return nil, fmt.Errorf("add capture hash: %w", err)
+		}
+		rendered = normalized
+	} else if !strings.HasSuffix(string(rendered), "\n") {
+		rendered = append(rendered, '\n')
+	}
+	sum := sha256.Sum256(rendered)
+	digest := hex.EncodeToString(sum[:])
+	switch format {
+	case formatMarkdown:
+		return append(rendered, []byte("<!-- sha256: "+digest+" -->\n")...), nil
+	case formatJSON:
+		annotated, err := appendJSONObjectField(rendered, "sha256", digest)
+		if err != nil {
+			return nil, fmt.Errorf("add capture hash: %w", err)
+		}
+		if annotated, err = layoutJSONDocument(annotated, compact); err != nil {
+			return nil, fmt.Errorf("add capture hash: %w", err)
+		}
+		return annotated, nil
+	case formatYAML:
+		return append(rendered, []byte("sha256: "+digest+"\n")...), nil
+	default:
+		return nil, usageError(fmt.Errorf("unsupported format: %s", format))
+	}
+}
+
+// layoutJSONDocument compacts or two-space indents a JSON document and ends
+// it with a newline.
+func layoutJSONDocument(document []byte, compact bool) ([]byte, error) {
+	var buffer bytes.Buffer
+	var err error
+	if compact {
+		err = json.Compact(&buffer, bytes.TrimSpace(document))
+	} else {
+		err = json.Indent(&buffer, bytes.TrimSpace(document), "", "  ")
+	}
+	if err != nil {
+		return nil, err
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
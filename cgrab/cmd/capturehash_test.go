@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+)
+
+func sha256Hex(payload string) string {
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAddCaptureHashAnnotatesEachFormat(t *testing.T) {
+	markdown, err := addCaptureHash([]byte("# Doc"), formatMarkdown, false)
+	if err != nil {
+		t.Fatalf("markdown: unexpected error: %v", err)
+	}
+	if want := "# Doc\n<!-- sha256: " + sha256Hex("# Doc\n") + " -->\n"; string(markdown) != want {
+		t.Fatalf("unexpected markdown:\nwant: %q\ngot:  %q", want, markdown)
+	}
+
+	document := "{\n  \"markdown\": \"# Doc\"\n}\n"
+	annotated, err := addCaptureHash([]byte(document), formatJSON, false)
+	if err != nil {
+		t.Fatalf("json: unexpected error: %v", err)
+	}
+	if want := "{\n  \"markdown\": \"# Doc\",\n  \"sha256\": \"" + sha256Hex(document) + "\"\n}\n"; string(annotated) != want {
+		t.Fatalf("unexpected json:\nwant: %q\ngot:  %q", want, annotated)
+	}
+
+	yaml, err := addCaptureHash([]byte("markdown: '# Doc'\n"), formatYAML, false)
+	if err != nil {
+		t.Fatalf("yaml: unexpected error: %v", err)
+	}
+	if want := "markdown: '# Doc'\nsha256: " + sha256Hex("markdown: '# Doc'\n") + "\n"; string(yaml) != want {
+		t.Fatalf("unexpected yaml:\nwant: %q\ngot:  %q", want, yaml)
+	}
+}
+
+func TestCaptureHashAndSidecarCoverWrittenBytes(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte("# Finder\n"), nil
+	}
+	path := filepath.Join(t.TempDir(), "out.md")
+
+	if _, _, err := runRootCommand("capture", "--app", "Finder", "--hash", "--sidecar", "--file", path); err != nil {
+		t.Fatalf("capture --hash --sidecar returned error: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read capture: %v", err)
+	}
+	if want := "# Finder\n<!-- sha256: " + sha256Hex("# Finder\n") + " -->\n"; string(written) != want {
+		t.Fatalf("unexpected capture:\nwant: %q\ngot:  %q", want, written)
+	}
+	checksum, err := os.ReadFile(path + output.ChecksumExtension)
+	if err != nil {
+		t.Fatalf("read sidecar: %v", err)
+	}
+	if want := sha256Hex(string(written)) + "  out.md\n"; string(checksum) != want {
+		t.Fatalf("unexpected sidecar: want %q, got %q", want, checksum)
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--sidecar", "--no-save", "--clipboard"},
+		{"capture", "--tab", "1:1", "--method", "url-pdf", "--hash"},
+		{"capture", "--batch", "targets.json", "--sidecar"},
+		{"capture", "--app", "Finder", "--format", "json", "--envelope", "--hash"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestCaptureHashMatchesWrittenJSON(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return []byte(`{"markdown":"# Finder","title":"Finder"}`), nil
+	}
+
+	for _, pretty := range []string{"true", "false"} {
+		path := filepath.Join(t.TempDir(), "out.json")
+		if _, _, err := runRootCommand("capture", "--app", "Finder", "--format", "json", "--pretty="+pretty, "--hash", "--file", path); err != nil {
+			t.Fatalf("pretty=%s: capture --hash returned error: %v", pretty, err)
+		}
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("pretty=%s: read capture: %v", pretty, err)
+		}
+		var document struct {
+			SHA256 string `json:"sha256"`
+		}
+		if err := json.Unmarshal(written, &document); err != nil || document.SHA256 == "" {
+			t.Fatalf("pretty=%s: expected a sha256 field, got %q (%v)", pretty, written, err)
+		}
+
+		// Removing the annotation from the written bytes must leave exactly
+		// the bytes that were hashed.
+		annotation := `,"sha256":"` + document.SHA256 + `"`
+		if pretty == "true" {
+			annotation = ",\n  \"sha256\": \"" + document.SHA256 + "\""
+		}
+		if !strings.Contains(string(written), annotation) {
+			t.Fatalf("pretty=%s: annotation %q not found in %q", pretty, annotation, written)
+		}
+		unannotated := strings.Replace(string(written), annotation, "", 1)
+		if got := sha256Hex(unannotated); got != document.SHA256 {
+			t.Fatalf("pretty=%s: digest %s does not match written bytes (rehashed %s)", pretty, document.SHA256, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+// validatePageURL accepts the absolute http(s) addresses --url can open.
+func validatePageURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("--url %q must be an absolute http or https URL", rawURL)
+	}
+	return nil
+}
+
+// openCaptureTab opens --url in a new background tab of the --browser target,
+// else the frontmost supported browser, else Safari. The returned func closes
+// that tab (found by id, not position); callers defer it so the tab is
+// cleaned up even when capture fails.
+func openCaptureTab(
+	ctx context.Context,
+	request captureRequest,
+	targetOverride bridge.BrowserTarget,
+	stderr io.Writer,
+) (*osascript.TabEntry, func(), error) {
+	if !request.filter.allowsURL(request.pageURL) {
+		return nil, nil, noMatchError(fmt.Errorf("--url %s is excluded by domain filters", request.pageURL))
+	}
+	target := focusedTargetOrder(ctx, targetOverride, nil)[0]
+	opened, err := openTabFunc(ctx, string(target), request.pageURL)
+	if err != nil {
+		return nil, nil, classifyPermissionError(fmt.Errorf(
+			"failed to open %s in %s: %w",
+			request.pageURL,
+			browserDisplayName(target),
+			err,
+		))
+	}
+	closeTab := func() {
+		// A --deadline or interrupt may already have canceled ctx; the
+		// temporary tab still has to go.
+		if err := closeTabFunc(context.WithoutCancel(ctx), opened); err != nil {
+			fmt.Fprintf(
+				stderr,
+				"warning: unable to close temporary %s tab w%d:t%d (%v)\n",
+				browserDisplayName(target),
+				opened.WindowIndex,
+				opened.TabIndex,
+				err,
+			)
+		}
+	}
+	return &opened.TabEntry, closeTab, nil
+}
@@ -18,8 +18,29 @@ const (
 	minCardWidth     = 20
 	maxCardWidth     = 80
 	borderedCardMin  = 52
+
+	noCardEnvVar = "CONTEXT_GRABBER_NO_CARD"
 )
 
+// outputIsTerminalFunc reports whether w is an interactive terminal. Tests
+// stub it because help output is captured in buffers.
+var outputIsTerminalFunc = isTerminalWriter
+
+// shouldShowProductCard decides whether root help leads with the product
+// card. The box-drawing card is skipped for --no-card, CONTEXT_GRABBER_NO_CARD,
+// and output that is not a terminal (CI logs, pipes).
+func shouldShowProductCard(out io.Writer, noCard bool) bool {
+	if noCard {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(noCardEnvVar))) {
+	case "", "0", "false", "no":
+	default:
+		return false
+	}
+	return outputIsTerminalFunc(out)
+}
+
 func detectCardWidth(out io.Writer) int {
 	// Prefer the actual output stream (e.g. when help goes to stdout)
 	if f, ok := out.(*os.File); ok {
@@ -1,30 +1,50 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/anthonylu23/context_grabber/cgrab/internal/config"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
 	"github.com/spf13/cobra"
 )
 
-func newConfigCommand() *cobra.Command {
+func newConfigCommand(global *globalOptions) *cobra.Command {
 	configCmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage cgrab settings",
 	}
 
-	configCmd.AddCommand(newConfigShowCommand())
+	configCmd.AddCommand(newConfigShowCommand(global))
+	configCmd.AddCommand(newConfigGetCommand())
+	configCmd.AddCommand(newConfigSetCommand())
 	configCmd.AddCommand(newConfigSetOutputDirCommand())
 	configCmd.AddCommand(newConfigResetOutputDirCommand())
+	configCmd.AddCommand(newConfigExportCommand())
+	configCmd.AddCommand(newConfigImportCommand())
 	return configCmd
 }
 
-func newConfigShowCommand() *cobra.Command {
+// configShowOutput is the --format json/yaml form of config show.
+type configShowOutput struct {
+	BaseDir             string         `json:"baseDir"`
+	ConfigFile          string         `json:"configFile"`
+	CaptureOutputSubdir string         `json:"captureOutputSubdir"`
+	CaptureOutputDir    string         `json:"captureOutputDir"`
+	SkillRoot           string         `json:"skillRoot,omitempty"`
+	FocusedTargetOrder  string         `json:"focusedTargetOrder,omitempty"`
+	MinContentByMethod  map[string]int `json:"minContentByMethod,omitempty"`
+}
+
+func newConfigShowCommand(global *globalOptions) *cobra.Command {
 	return &cobra.Command{
-		Use:   "show",
-		Short: "Show current config",
+		Use:     "show",
+		Short:   "Show current config",
+		Example: "  cgrab config show\n  cgrab config show --format json",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			settings, err := config.LoadSettings()
 			if err != nil {
@@ -36,17 +56,143 @@ func newConfigShowCommand() *cobra.Command {
 			}
 			configPath := config.ResolveConfigFilePath(baseDir)
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Context Grabber CLI Config\n")
-			fmt.Fprintf(cmd.OutOrStdout(), "-------------------------\n")
-			fmt.Fprintf(cmd.OutOrStdout(), "base_dir: %s\n", baseDir)
-			fmt.Fprintf(cmd.OutOrStdout(), "config_file: %s\n", configPath)
-			fmt.Fprintf(cmd.OutOrStdout(), "capture_output_subdir: %s\n", settings.CaptureOutputSubdir)
-			fmt.Fprintf(cmd.OutOrStdout(), "capture_output_dir: %s\n", captureDir)
+			shown := configShowOutput{
+				BaseDir:             baseDir,
+				ConfigFile:          configPath,
+				CaptureOutputSubdir: settings.CaptureOutputSubdir,
+				CaptureOutputDir:    captureDir,
+				SkillRoot:           settings.SkillRoot,
+				FocusedTargetOrder:  settings.FocusedTargetOrder,
+				MinContentByMethod:  settings.MinContentByMethod,
+			}
+			rendered, err := renderConfigShow(global.format, shown)
+			if err != nil {
+				return err
+			}
+			return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr())
+		},
+	}
+}
+
+func renderConfigShow(format string, shown configShowOutput) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		rendered, err := json.MarshalIndent(shown, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(rendered, '\n'), nil
+	case formatYAML:
+		return renderJSONAsYAML(func(string) ([]byte, error) {
+			return json.MarshalIndent(shown, "", "  ")
+		})
+	case formatMarkdown:
+	default:
+		return nil, usageError(fmt.Errorf("config show supports only --format markdown, json, or yaml"))
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Context Grabber CLI Config\n")
+	builder.WriteString("-------------------------\n")
+	fmt.Fprintf(&builder, "base_dir: %s\n", shown.BaseDir)
+	fmt.Fprintf(&builder, "config_file: %s\n", shown.ConfigFile)
+	fmt.Fprintf(&builder, "capture_output_subdir: %s\n", shown.CaptureOutputSubdir)
+	fmt.Fprintf(&builder, "capture_output_dir: %s\n", shown.CaptureOutputDir)
+	// Optional settings are omitted when unset, as in the JSON form.
+	if shown.SkillRoot != "" {
+		fmt.Fprintf(&builder, "skill_root: %s\n", shown.SkillRoot)
+	}
+	if shown.FocusedTargetOrder != "" {
+		fmt.Fprintf(&builder, "focused_target_order: %s\n", shown.FocusedTargetOrder)
+	}
+	if len(shown.MinContentByMethod) > 0 {
+		fmt.Fprintf(&builder, "min_content_by_method: %s\n", config.FormatMinContentByMethod(shown.MinContentByMethod))
+	}
+	return []byte(builder.String()), nil
+}
+
+func newConfigGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "get <key>",
+		Short:   "Print a config value",
+		Long:    "Print a config value. Supported keys: " + strings.Join(config.SettingKeys(), ", ") + ".",
+		Example: "  cgrab config get output-subdir",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := config.LoadSettings()
+			if err != nil {
+				return err
+			}
+			value, err := config.GetSetting(settings, args[0])
+			if err != nil {
+				return usageError(err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", value)
+			return nil
+		},
+	}
+}
+
+func newConfigSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "set <key> <value>",
+		Short:   "Update a config value",
+		Long:    "Update a config value. Supported keys: " + strings.Join(config.SettingKeys(), ", ") + ".",
+		Example: "  cgrab config set output-subdir projects/client-a",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := updateSetting(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			value, err := config.GetSetting(settings, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated %s: %s\n", strings.ToLower(strings.TrimSpace(args[0])), value)
 			return nil
 		},
 	}
 }
 
+// updateSetting loads the stored settings, applies key=value, and saves them.
+// An unknown key or invalid value is a usage error.
+func updateSetting(key string, value string) (config.Settings, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return config.Settings{}, err
+	}
+	if err := config.SetSetting(&settings, key, value); err != nil {
+		return config.Settings{}, usageError(err)
+	}
+	if err := validateTargetOrder(settings.FocusedTargetOrder); err != nil {
+		return config.Settings{}, usageError(err)
+	}
+	if err := config.SaveSettings(settings); err != nil {
+		return config.Settings{}, err
+	}
+	return settings, nil
+}
+
+// validateTargetOrder rejects a normalized target-order list naming a browser
+// that is not in the osascript registry. internal/config only normalizes the
+// list, so every path that accepts one checks it here.
+func validateTargetOrder(order string) error {
+	if order == "" {
+		return nil
+	}
+	for _, target := range strings.Split(order, ",") {
+		if _, ok := osascript.LookupBrowser(target); !ok {
+			return fmt.Errorf(
+				"unsupported browser %q in target order (expected one of: %s)",
+				target,
+				strings.Join(osascript.BrowserTargets(), ", "),
+			)
+		}
+	}
+	return nil
+}
+
 func newConfigSetOutputDirCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:     "set-output-dir <subdir>",
@@ -55,19 +201,10 @@ func newConfigSetOutputDirCommand() *cobra.Command {
 		Example: "  cgrab config set-output-dir captures\n  cgrab config set-output-dir projects/client-a",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			subdir := strings.TrimSpace(args[0])
-			if subdir == "" {
-				return fmt.Errorf("output subdirectory cannot be empty")
-			}
-
-			settings, err := config.LoadSettings()
+			settings, err := updateSetting("output-subdir", args[0])
 			if err != nil {
 				return err
 			}
-			settings.CaptureOutputSubdir = filepath.Clean(subdir)
-			if err := config.SaveSettings(settings); err != nil {
-				return err
-			}
 
 			_, captureDir, err := config.EnsureBaseLayout(settings)
 			if err != nil {
@@ -84,12 +221,12 @@ func newConfigResetOutputDirCommand() *cobra.Command {
 		Use:   "reset-output-dir",
 		Short: "Reset capture output subdirectory to default",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			settings, err := config.LoadSettings()
+			defaultSubdir, err := config.GetSetting(config.DefaultSettings(), "output-subdir")
 			if err != nil {
 				return err
 			}
-			settings.CaptureOutputSubdir = config.DefaultSettings().CaptureOutputSubdir
-			if err := config.SaveSettings(settings); err != nil {
+			settings, err := updateSetting("output-subdir", defaultSubdir)
+			if err != nil {
 				return err
 			}
 			_, captureDir, err := config.EnsureBaseLayout(settings)
@@ -101,3 +238,60 @@ func newConfigResetOutputDirCommand() *cobra.Command {
 		},
 	}
 }
+
+func newConfigExportCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "export",
+		Short:   "Print current settings as JSON for config import",
+		Example: "  cgrab config export > settings.json",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			settings, err := config.LoadSettings()
+			if err != nil {
+				return err
+			}
+			payload, err := config.ExportSettings(settings)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(payload)
+			return err
+		},
+	}
+}
+
+func newConfigImportCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Replace settings with a file written by config export",
+		Long: "Replace settings with a file written by config export (\"-\" reads stdin). " +
+			"Unknown fields are rejected and omitted fields take their defaults.",
+		Example: "  cgrab config import settings.json",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := strings.TrimSpace(args[0])
+			var raw []byte
+			var err error
+			if path == "-" {
+				raw, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				raw, err = os.ReadFile(path)
+			}
+			if err != nil {
+				return fmt.Errorf("read settings: %w", err)
+			}
+			settings, err := config.ParseSettings(raw)
+			if err == nil {
+				err = validateTargetOrder(settings.FocusedTargetOrder)
+			}
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", path, err))
+			}
+			if err := config.SaveSettings(settings); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported settings from %s\n", path)
+			return nil
+		},
+	}
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/config"
 )
 
 func TestConfigSetOutputDirAndShow(t *testing.T) {
@@ -17,14 +21,12 @@ func TestConfigSetOutputDirAndShow(t *testing.T) {
 		t.Fatalf("set-output-dir command failed: %v", err)
 	}
 
-	showCommand := newConfigShowCommand()
-	var stdout bytes.Buffer
-	showCommand.SetOut(&stdout)
-	if err := showCommand.Execute(); err != nil {
+	payload, _, err := runRootCommandToFile(t, "config", "show")
+	if err != nil {
 		t.Fatalf("config show failed: %v", err)
 	}
 
-	output := stdout.String()
+	output := string(payload)
 	if !strings.Contains(output, filepath.Join("projects", "client-a")) {
 		t.Fatalf("expected config show output to include custom subdir, got %q", output)
 	}
@@ -40,3 +42,194 @@ func TestConfigSetOutputDirRejectsPathTraversal(t *testing.T) {
 		t.Fatalf("expected traversal path to fail")
 	}
 }
+
+func TestConfigSetAndGetByKey(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), "contextgrabber")
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", baseDir)
+
+	setCommand := newConfigSetCommand()
+	setCommand.SetOut(&bytes.Buffer{})
+	setCommand.SetArgs([]string{"output-subdir", "projects/client-b"})
+	if err := setCommand.Execute(); err != nil {
+		t.Fatalf("config set failed: %v", err)
+	}
+
+	getCommand := newConfigGetCommand()
+	var stdout bytes.Buffer
+	getCommand.SetOut(&stdout)
+	getCommand.SetArgs([]string{"output-subdir"})
+	if err := getCommand.Execute(); err != nil {
+		t.Fatalf("config get failed: %v", err)
+	}
+	if strings.TrimSpace(stdout.String()) != filepath.Join("projects", "client-b") {
+		t.Fatalf("unexpected config get output: %q", stdout.String())
+	}
+}
+
+func TestConfigSetTargetOrderRejectsUnknownBrowser(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", filepath.Join(t.TempDir(), "contextgrabber"))
+
+	_, err := updateSetting("target-order", "chrome,firefox")
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(err.Error(), "firefox") {
+		t.Fatalf("expected unsupported browser to be a usage error, got %v", err)
+	}
+	settings, err := config.LoadSettings()
+	if err != nil || settings.FocusedTargetOrder != "" {
+		t.Fatalf("expected the rejected target order not to be saved, got %+v err=%v", settings, err)
+	}
+}
+
+func TestConfigSetRejectsUnknownKey(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", filepath.Join(t.TempDir(), "contextgrabber"))
+
+	setCommand := newConfigSetCommand()
+	setCommand.SetArgs([]string{"bogus", "value"})
+	if err := setCommand.Execute(); err == nil {
+		t.Fatalf("expected unknown key to fail")
+	}
+}
+
+func TestConfigExportImportRoundTrip(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), "contextgrabber")
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", baseDir)
+	if _, err := updateSetting("target-order", "chrome,safari"); err != nil {
+		t.Fatalf("seed settings: %v", err)
+	}
+
+	exportCommand := newConfigExportCommand()
+	var exported bytes.Buffer
+	exportCommand.SetOut(&exported)
+	if err := exportCommand.Execute(); err != nil {
+		t.Fatalf("config export failed: %v", err)
+	}
+	if !strings.Contains(exported.String(), `"focusedTargetOrder": "chrome,safari"`) {
+		t.Fatalf("unexpected export: %s", exported.String())
+	}
+
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", filepath.Join(t.TempDir(), "other"))
+	importCommand := newConfigImportCommand()
+	importCommand.SetOut(&bytes.Buffer{})
+	importCommand.SetIn(&exported)
+	importCommand.SetArgs([]string{"-"})
+	if err := importCommand.Execute(); err != nil {
+		t.Fatalf("config import failed: %v", err)
+	}
+	settings, err := config.LoadSettings()
+	if err != nil || settings.FocusedTargetOrder != "chrome,safari" {
+		t.Fatalf("expected imported target order, got %+v err=%v", settings, err)
+	}
+}
+
+func TestConfigImportRejectsUnknownFieldsAndInvalidValues(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", filepath.Join(t.TempDir(), "contextgrabber"))
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"typo.json":      `{"captureOutputSubdr": "x"}`,
+		"traversal.json": `{"captureOutputSubdir": "../outside"}`,
+		"browser.json":   `{"focusedTargetOrder": "chrome,firefox"}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		importCommand := newConfigImportCommand()
+		importCommand.SetArgs([]string{path})
+		if err := importCommand.Execute(); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("%s: expected usage error, got %v", name, err)
+		}
+	}
+}
+
+func TestConfigShowMarkdownIncludesOptionalSettings(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), "contextgrabber")
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", baseDir)
+
+	payload, _, err := runRootCommandToFile(t, "config", "show")
+	if err != nil {
+		t.Fatalf("config show failed: %v", err)
+	}
+	for _, key := range []string{"skill_root:", "focused_target_order:", "min_content_by_method:"} {
+		if strings.Contains(string(payload), key) {
+			t.Fatalf("expected unset %s to be omitted, got:\n%s", key, payload)
+		}
+	}
+
+	skillRoot := filepath.Join(t.TempDir(), "skills")
+	for key, value := range map[string]string{
+		"skill-root":   skillRoot,
+		"target-order": "chrome,safari",
+		"min-content":  "browser_extension=200,applescript_dom=50",
+	} {
+		if _, err := updateSetting(key, value); err != nil {
+			t.Fatalf("seed %s: %v", key, err)
+		}
+	}
+	payload, _, err = runRootCommandToFile(t, "config", "show")
+	if err != nil {
+		t.Fatalf("config show failed: %v", err)
+	}
+	for _, line := range []string{
+		"skill_root: " + skillRoot + "\n",
+		"focused_target_order: chrome,safari\n",
+		"min_content_by_method: applescript_dom=50,browser_extension=200\n",
+	} {
+		if !strings.Contains(string(payload), line) {
+			t.Fatalf("expected %q in config show output:\n%s", line, payload)
+		}
+	}
+}
+
+func TestConfigShowJSONAndYAML(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), "contextgrabber")
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", baseDir)
+
+	payload, _, err := runRootCommandToFile(t, "config", "show", "--format", "json")
+	if err != nil {
+		t.Fatalf("config show --format json failed: %v", err)
+	}
+	var shown configShowOutput
+	if err := json.Unmarshal(payload, &shown); err != nil {
+		t.Fatalf("decode config show output %q: %v", payload, err)
+	}
+	if shown.BaseDir != baseDir || shown.ConfigFile != config.ResolveConfigFilePath(baseDir) {
+		t.Fatalf("unexpected base dir or config file: %+v", shown)
+	}
+	if shown.CaptureOutputSubdir != "captures" || shown.CaptureOutputDir != filepath.Join(baseDir, "captures") {
+		t.Fatalf("unexpected capture output settings: %+v", shown)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "config", "show", "--format", "yaml")
+	if err != nil {
+		t.Fatalf("config show --format yaml failed: %v", err)
+	}
+	if !strings.Contains(string(payload), "captureOutputSubdir: captures") {
+		t.Fatalf("expected YAML config fields, got %q", payload)
+	}
+
+	if _, _, err := runRootCommand("config", "show", "--format", "csv"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected --format csv to be a usage error, got %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "config.json")
+	stdout, _, err := runRootCommand("config", "show", "--format", "json", "--file", outputPath, "--pretty=false", "--envelope")
+	if err != nil {
+		t.Fatalf("config show --file --envelope failed: %v", err)
+	}
+	if stdout != "" {
+		t.Fatalf("expected --file to keep stdout empty, got %q", stdout)
+	}
+	written, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read config show file: %v", err)
+	}
+	var envelope struct {
+		Command string           `json:"command"`
+		Data    configShowOutput `json:"data"`
+	}
+	if err := json.Unmarshal(written, &envelope); err != nil || envelope.Command != "config show" || envelope.Data.BaseDir != baseDir {
+		t.Fatalf("expected an enveloped config show payload, got %q (%v)", written, err)
+	}
+	if strings.Count(string(written), "\n") != 1 {
+		t.Fatalf("expected compact JSON with --pretty=false, got %q", written)
+	}
+}
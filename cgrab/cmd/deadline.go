@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// startDeadline bounds the command context with --deadline. Every
+// cmd.Context() consumer (osascript, bridge, host app) then stops once the
+// deadline passes.
+func (o *globalOptions) startDeadline(cmd *cobra.Command) error {
+	if o.deadline < 0 {
+		return usageError(fmt.Errorf("--deadline must be positive"))
+	}
+	if o.deadline == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), o.deadline)
+	o.cancelDeadline = cancel
+	cmd.SetContext(ctx)
+	return nil
+}
+
+// deadlineResult releases the --deadline timer and, when the context ran
+// out, replaces whatever error the timed-out call produced (often a killed
+// subprocess) with one that names the deadline.
+func (o *globalOptions) deadlineResult(ctx context.Context, err error) error {
+	if o.cancelDeadline != nil {
+		defer o.cancelDeadline()
+	}
+	if err == nil || o.deadline == 0 || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("command timed out after --deadline %s: %w", o.deadline, err)
+}
+
+// applyDeadline wraps every RunE under command with deadlineResult.
+func applyDeadline(command *cobra.Command, opts *globalOptions) {
+	if run := command.RunE; run != nil {
+		command.RunE = func(cmd *cobra.Command, args []string) error {
+			return opts.deadlineResult(cmd.Context(), run(cmd, args))
+		}
+	}
+	for _, child := range command.Commands() {
+		applyDeadline(child, opts)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+const delimiterFlagUsage = `field separator for --format csv (single character; \t for tab)`
+
+var (
+	tabDelimitedHeader = []string{"browser", "window", "tab", "title", "url"}
+	appDelimitedHeader = []string{"appName", "bundleIdentifier", "windowCount"}
+)
+
+func isDelimitedFormat(format string) bool {
+	return format == formatTSV || format == formatCSV
+}
+
+// validateDelimited checks --format tsv/csv and --delimiter. Delimited output
+// has one fixed set of columns, so it needs a single entry kind.
+func validateDelimited(format string, delimiter string, selection listSelection) error {
+	if delimiter != "" {
+		if format != formatCSV {
+			return fmt.Errorf("--delimiter requires --format csv")
+		}
+		if _, err := parseDelimiter(delimiter); err != nil {
+			return err
+		}
+	}
+	if isDelimitedFormat(format) && selection.tabs && selection.apps {
+		return fmt.Errorf("--format %s lists one kind at a time; use list tabs or list apps", format)
+	}
+	return nil
+}
+
+func parseDelimiter(raw string) (rune, error) {
+	if raw == `\t` {
+		return '\t', nil
+	}
+	comma, size := utf8.DecodeRuneInString(raw)
+	if size == 0 || size != len(raw) || comma == utf8.RuneError {
+		return 0, fmt.Errorf("--delimiter must be a single character, got %q", raw)
+	}
+	if comma == '"' || comma == '\r' || comma == '\n' {
+		return 0, fmt.Errorf("--delimiter cannot be %q", raw)
+	}
+	return comma, nil
+}
+
+// delimiterFor returns the field separator for a delimited format; --delimiter
+// overrides the comma of --format csv.
+func delimiterFor(format string, delimiter string) rune {
+	if format == formatTSV {
+		return '\t'
+	}
+	if comma, err := parseDelimiter(delimiter); delimiter != "" && err == nil {
+		return comma
+	}
+	return ','
+}
+
+// renderDelimited writes a header row followed by one row per entry.
+func renderDelimited(comma rune, header []string, rows [][]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Comma = comma
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func tabDelimitedRows(tabs []osascript.TabEntry) [][]string {
+	rows := make([][]string, 0, len(tabs))
+	for _, tab := range tabs {
+		rows = append(rows, []string{
+			tab.Browser,
+			strconv.Itoa(tab.WindowIndex),
+			strconv.Itoa(tab.TabIndex),
+			tab.Title,
+			tab.URL,
+		})
+	}
+	return rows
+}
+
+// appDelimitedColumns returns the app header, with an appPath column for
+// --include-app-path.
+func appDelimitedColumns(includeAppPath bool) []string {
+	if !includeAppPath {
+		return appDelimitedHeader
+	}
+	return append(append([]string{}, appDelimitedHeader...), "appPath")
+}
+
+func appDelimitedRows(apps []osascript.AppEntry, includeAppPath bool) [][]string {
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		row := []string{
+			app.AppName,
+			app.BundleIdentifier,
+			strconv.Itoa(app.WindowCount),
+		}
+		if includeAppPath {
+			row = append(row, app.AppPath)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -3,20 +3,62 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os/exec"
+	"strings"
 
+	"github.com/anthonylu23/context_grabber/cgrab/internal/skills"
 	"github.com/spf13/cobra"
 )
 
-const repoDocsURL = "https://github.com/anthonylu23/context_grabber"
+const (
+	repoDocsURL        = "https://github.com/anthonylu23/context_grabber"
+	embeddedDocsPath   = "references/cli-reference.md"
+	docsTopicSeparator = "/"
+)
 
 var openURLFunc = openURL
 
 func newDocsCommand() *cobra.Command {
-	return &cobra.Command{
+	var topic string
+	var search string
+
+	docsCmd := &cobra.Command{
 		Use:   "docs",
 		Short: "Open docs in browser",
+		Long: "Open the docs in the browser. With --topic or --search, print sections of the " +
+			"embedded CLI reference instead.",
+		Example: "  cgrab docs\n" +
+			"  cgrab docs --topic capture\n" +
+			"  cgrab docs --topic capture/auto-save-behavior\n" +
+			"  cgrab docs --search clipboard",
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			topic = strings.TrimSpace(topic)
+			search = strings.TrimSpace(search)
+			if topic != "" && search != "" {
+				return fmt.Errorf("--topic and --search cannot be combined")
+			}
+			if topic != "" || search != "" {
+				sections, err := loadDocSections()
+				if err != nil {
+					return err
+				}
+				if topic != "" {
+					section, err := findDocSection(sections, topic)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), section.text())
+					return nil
+				}
+				matches := searchDocSections(sections, search)
+				if len(matches) == 0 {
+					return fmt.Errorf("no docs matched --search %q", search)
+				}
+				fmt.Fprint(cmd.OutOrStdout(), strings.Join(matches, "\n\n")+"\n")
+				return nil
+			}
+
 			if err := openURLFunc(cmd.Context(), repoDocsURL); err != nil {
 				fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not open browser (%v)\n", err)
 				fmt.Fprintf(cmd.OutOrStdout(), "%s\n", repoDocsURL)
@@ -27,6 +69,152 @@ func newDocsCommand() *cobra.Command {
 			return nil
 		},
 	}
+
+	docsCmd.Flags().StringVar(&topic, "topic", "", "print one section of the embedded CLI reference (e.g. capture)")
+	docsCmd.Flags().StringVar(&search, "search", "", "print embedded CLI reference headings whose sections mention a term")
+	return docsCmd
+}
+
+// docSection is a heading of the embedded CLI reference together with every
+// line up to the next heading of the same or a higher level.
+type docSection struct {
+	key     string
+	heading string
+	level   int
+	lines   []string
+}
+
+func (s docSection) text() string {
+	lines := s.lines
+	for len(lines) > 0 {
+		last := strings.TrimSpace(lines[len(lines)-1])
+		if last != "" && last != "---" {
+			break
+		}
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func loadDocSections() ([]docSection, error) {
+	raw, err := fs.ReadFile(skills.SkillFiles, embeddedDocsPath)
+	if err != nil {
+		return nil, fmt.Errorf("read embedded docs: %w", err)
+	}
+	return parseDocSections(string(raw)), nil
+}
+
+// parseDocSections splits markdown into sections keyed by heading. Level 2
+// and 3 headings use their own slug (e.g. "capture" for "### `cgrab capture`");
+// deeper headings are keyed under their parent (e.g. "capture/flags").
+// Headings inside fenced code blocks are ignored.
+func parseDocSections(markdown string) []docSection {
+	lines := strings.Split(markdown, "\n")
+	var sections []docSection
+	var open []int
+	inFence := false
+
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		level := headingLevel(line)
+		if !inFence && level >= 2 {
+			for len(open) > 0 && sections[open[len(open)-1]].level >= level {
+				open = open[:len(open)-1]
+			}
+			key := docSectionKey(line)
+			if level > 3 && len(open) > 0 {
+				key = sections[open[len(open)-1]].key + docsTopicSeparator + key
+			}
+			sections = append(sections, docSection{
+				key:     key,
+				heading: strings.TrimSpace(line),
+				level:   level,
+			})
+			open = append(open, len(sections)-1)
+		}
+		for _, index := range open {
+			sections[index].lines = append(sections[index].lines, line)
+		}
+	}
+	return sections
+}
+
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level >= len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+func docSectionKey(heading string) string {
+	title := strings.TrimSpace(strings.TrimLeft(heading, "#"))
+	title = strings.ReplaceAll(title, "`", "")
+	title = strings.TrimPrefix(title, "cgrab ")
+	words := []string{}
+	for _, word := range strings.Fields(strings.ToLower(title)) {
+		if strings.HasPrefix(word, "<") || word == "—" || word == "-" {
+			continue
+		}
+		words = append(words, strings.Trim(word, "()"))
+	}
+	return strings.Join(words, "-")
+}
+
+func findDocSection(sections []docSection, topic string) (docSection, error) {
+	needle := strings.ToLower(strings.TrimSpace(topic))
+	for _, section := range sections {
+		if section.key == needle {
+			return section, nil
+		}
+	}
+
+	topics := []string{}
+	for _, section := range sections {
+		if section.level <= 3 {
+			topics = append(topics, section.key)
+		}
+	}
+	return docSection{}, fmt.Errorf("unknown docs topic %q (available: %s)", topic, strings.Join(topics, ", "))
+}
+
+// searchDocSections returns, for every section whose own lines mention term,
+// the section heading followed by the matching lines.
+func searchDocSections(sections []docSection, term string) []string {
+	needle := strings.ToLower(term)
+	var results []string
+	for i, section := range sections {
+		ownLines := section.lines
+		if i+1 < len(sections) {
+			// Stop at the first nested heading so matches are reported once,
+			// under the most specific section.
+			for j, line := range ownLines {
+				if j > 0 && strings.TrimSpace(line) == sections[i+1].heading {
+					ownLines = ownLines[:j]
+					break
+				}
+			}
+		}
+
+		var matches []string
+		for _, line := range ownLines[1:] {
+			if strings.Contains(strings.ToLower(line), needle) {
+				matches = append(matches, "  "+strings.TrimSpace(line))
+			}
+		}
+		if strings.Contains(strings.ToLower(section.heading), needle) || len(matches) > 0 {
+			results = append(results, fmt.Sprintf("%s [%s]", section.heading, section.key))
+			if len(matches) > 0 {
+				results[len(results)-1] += "\n" + strings.Join(matches, "\n")
+			}
+		}
+	}
+	return results
 }
 
 func openURL(ctx context.Context, url string) error {
@@ -33,3 +33,68 @@ func TestDocsCommandPrintsURLWhenOpenFails(t *testing.T) {
 		t.Fatalf("expected warning in stderr, got %q", stderr.String())
 	}
 }
+
+func TestDocsCommandTopicPrintsSection(t *testing.T) {
+	command := newDocsCommand()
+	var stdout bytes.Buffer
+	command.SetOut(&stdout)
+	command.SetArgs([]string{"--topic", "capture/auto-save-behavior"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatalf("docs --topic returned error: %v", err)
+	}
+	output := stdout.String()
+	if !strings.HasPrefix(output, "#### Auto-Save Behavior") {
+		t.Fatalf("expected section heading first, got %q", output)
+	}
+	if strings.Contains(output, "--focused` Fallback Order") {
+		t.Fatalf("expected section to stop at next heading, got %q", output)
+	}
+}
+
+func TestDocsCommandTopicRejectsUnknownTopic(t *testing.T) {
+	command := newDocsCommand()
+	command.SetOut(&bytes.Buffer{})
+	command.SetErr(&bytes.Buffer{})
+	command.SetArgs([]string{"--topic", "nope"})
+
+	err := command.Execute()
+	if err == nil {
+		t.Fatalf("expected unknown topic error")
+	}
+	if !strings.Contains(err.Error(), "capture") {
+		t.Fatalf("expected available topics in error, got %v", err)
+	}
+}
+
+func TestDocsCommandSearchPrintsMatchingHeadings(t *testing.T) {
+	command := newDocsCommand()
+	var stdout bytes.Buffer
+	command.SetOut(&stdout)
+	command.SetArgs([]string{"--search", "CLIPBOARD"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatalf("docs --search returned error: %v", err)
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "### Output Routing [output-routing]") {
+		t.Fatalf("expected matching heading, got %q", output)
+	}
+	if !strings.Contains(output, "\n  2. If `--clipboard` is set") {
+		t.Fatalf("expected indented matching line, got %q", output)
+	}
+}
+
+func TestParseDocSectionsIgnoresFencedHeadings(t *testing.T) {
+	sections := parseDocSections("## Top\ntext\n```\n## Fenced\n```\n### `cgrab sub <arg>`\n#### Flags\nflag\n## Next\n")
+	keys := []string{}
+	for _, section := range sections {
+		keys = append(keys, section.key)
+	}
+	if strings.Join(keys, ",") != "top,sub,sub/flags,next" {
+		t.Fatalf("unexpected section keys: %v", keys)
+	}
+	if !strings.Contains(sections[0].text(), "#### Flags") {
+		t.Fatalf("expected parent section to include nested headings, got %q", sections[0].text())
+	}
+}
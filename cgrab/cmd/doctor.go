@@ -1,41 +1,92 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/config"
 	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
 	"github.com/spf13/cobra"
 )
 
+const (
+	doctorCacheFileName = "doctor-cache.json"
+	// doctorCacheTTL keeps repeat runs (e.g. from a shell prompt) from
+	// spawning Bun for every bridge ping.
+	doctorCacheTTL = 30 * time.Second
+)
+
+var (
+	runDoctorFunc       = bridge.RunDoctor
+	fixDoctorIssuesFunc = bridge.FixDoctorIssues
+)
+
 func newDoctorCommand(global *globalOptions) *cobra.Command {
+	var fresh bool
+	var fix bool
+	var watch bool
+	var interval time.Duration
+
 	doctorCmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Run system health checks",
 		Example: "  cgrab doctor\n" +
-			"  cgrab doctor --format json",
+			"  cgrab doctor --format json\n" +
+			"  cgrab doctor --fresh\n" +
+			"  cgrab doctor --fix\n" +
+			"  cgrab doctor --watch --interval 5s",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			report, err := bridge.RunDoctor(cmd.Context())
+			if watch {
+				if global.format == formatYAML {
+					return usageError(fmt.Errorf("--watch supports only --format markdown or json"))
+				}
+				if global.outputFile != "" || global.clipboard {
+					return usageError(fmt.Errorf("--watch cannot be combined with --file or --clipboard"))
+				}
+				if fix {
+					return usageError(fmt.Errorf("--watch cannot be combined with --fix"))
+				}
+				if interval <= 0 {
+					return usageError(fmt.Errorf("--interval must be positive"))
+				}
+				return runDoctorWatch(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), global.format, interval)
+			}
+
+			report, err := loadDoctorReport(cmd.Context(), fresh || fix)
 			if err != nil {
 				return err
 			}
+			if fix {
+				if report, err = fixDoctorReport(cmd.Context(), report); err != nil {
+					return err
+				}
+			}
 
 			var rendered []byte
 			switch global.format {
 			case formatJSON:
 				rendered, err = json.MarshalIndent(report, "", "  ")
+			case formatYAML:
+				rendered, err = renderJSONAsYAML(func(string) ([]byte, error) {
+					return json.MarshalIndent(report, "", "  ")
+				})
 			case formatMarkdown:
 				rendered = []byte(formatDoctorMarkdown(report))
 			default:
-				err = fmt.Errorf("unsupported format: %s", global.format)
+				err = usageError(fmt.Errorf("unsupported format: %s", global.format))
 			}
 			if err != nil {
 				return err
 			}
 
-			if err := output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard); err != nil {
+			if err := global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr()); err != nil {
 				return err
 			}
 
@@ -45,18 +96,96 @@ func newDoctorCommand(global *globalOptions) *cobra.Command {
 			return nil
 		},
 	}
+	doctorCmd.Flags().BoolVar(&fresh, "fresh", false, fmt.Sprintf("ignore the cached report (reused for %s) and re-run all checks", doctorCacheTTL))
+	doctorCmd.Flags().BoolVar(&fix, "fix", false, "apply safe fixes (e.g. build the host binary in a repo checkout) and print steps for the rest")
+	doctorCmd.Flags().BoolVar(&watch, "watch", false, "re-run the checks on an interval until interrupted, highlighting status changes (JSON lines when not a terminal)")
+	doctorCmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "refresh interval for --watch")
 	return doctorCmd
 }
 
+// fixDoctorReport applies the safe remediations for report and re-runs the
+// checks when anything changed, so the printed status reflects the fixes.
+func fixDoctorReport(ctx context.Context, report bridge.DoctorReport) (bridge.DoctorReport, error) {
+	fixes := fixDoctorIssuesFunc(ctx, report)
+	for _, applied := range fixes {
+		if applied.Applied {
+			refreshed, err := loadDoctorReport(ctx, true)
+			if err != nil {
+				return bridge.DoctorReport{}, err
+			}
+			report = refreshed
+			break
+		}
+	}
+	report.Fixes = fixes
+	return report, nil
+}
+
+// loadDoctorReport returns a cached report younger than doctorCacheTTL, or
+// runs the checks and caches the result. Cache failures never fail doctor.
+func loadDoctorReport(ctx context.Context, fresh bool) (bridge.DoctorReport, error) {
+	cachePath, cacheErr := resolveDoctorCachePath()
+	if cacheErr == nil && !fresh {
+		if report, ok := readCachedDoctorReport(cachePath); ok {
+			return report, nil
+		}
+	}
+
+	report, err := runDoctorFunc(ctx)
+	if err != nil {
+		return bridge.DoctorReport{}, err
+	}
+	if cacheErr == nil {
+		if payload, marshalErr := json.Marshal(report); marshalErr == nil {
+			_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+			_ = os.WriteFile(cachePath, payload, 0o644)
+		}
+	}
+	return report, nil
+}
+
+func resolveDoctorCachePath() (string, error) {
+	baseDir, err := config.ResolveBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, doctorCacheFileName), nil
+}
+
+func readCachedDoctorReport(path string) (bridge.DoctorReport, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return bridge.DoctorReport{}, false
+	}
+	var report bridge.DoctorReport
+	if err := json.Unmarshal(raw, &report); err != nil || report.GeneratedAt.IsZero() {
+		return bridge.DoctorReport{}, false
+	}
+	age := nowFunc().Sub(report.GeneratedAt)
+	if age < 0 || age >= doctorCacheTTL {
+		return bridge.DoctorReport{}, false
+	}
+	report.Cached = true
+	return report, true
+}
+
 func formatDoctorMarkdown(report bridge.DoctorReport) string {
 	lines := []string{
 		"# Context Grabber Doctor",
 		fmt.Sprintf("- overall_status: %s", report.OverallStatus),
+		fmt.Sprintf("- generated_at: %s", report.GeneratedAt.UTC().Format(time.RFC3339)),
+		fmt.Sprintf("- cached: %t", report.Cached),
 		fmt.Sprintf("- repo_root: %s", report.RepoRoot),
 		fmt.Sprintf("- osascript_available: %t", report.OsaScriptAvailable),
+		fmt.Sprintf("- accessibility_granted: %t", report.AccessibilityGranted),
+	}
+	for _, target := range sortedKeys(report.AutomationStatus) {
+		lines = append(lines, fmt.Sprintf("- %s_automation: %s", target, report.AutomationStatus[target]))
+	}
+	lines = append(lines,
 		fmt.Sprintf("- bun_available: %t", report.BunAvailable),
 		fmt.Sprintf("- host_binary_available: %t", report.HostBinaryAvailable),
-	}
+	)
 	if report.HostBinaryPath != "" {
 		lines = append(lines, fmt.Sprintf("- host_binary_path: %s", report.HostBinaryPath))
 	}
@@ -74,5 +203,27 @@ func formatDoctorMarkdown(report bridge.DoctorReport) string {
 			lines = append(lines, "- "+warning)
 		}
 	}
+	if len(report.Fixes) > 0 {
+		lines = append(lines, "", "## Fixes")
+		for _, fix := range report.Fixes {
+			switch {
+			case fix.Applied:
+				lines = append(lines, fmt.Sprintf("- fixed: %s (%s)", fix.Issue, fix.Action))
+			case fix.Error != "":
+				lines = append(lines, fmt.Sprintf("- failed: %s (%s): %s", fix.Issue, fix.Action, fix.Error))
+			default:
+				lines = append(lines, fmt.Sprintf("- manual: %s: %s", fix.Issue, fix.Action))
+			}
+		}
+	}
 	return strings.Join(lines, "\n") + "\n"
 }
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
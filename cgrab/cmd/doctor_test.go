@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+)
+
+func stubRunDoctor(t *testing.T, generatedAt time.Time) *int {
+	t.Helper()
+	calls := 0
+	previous := runDoctorFunc
+	runDoctorFunc = func(context.Context) (bridge.DoctorReport, error) {
+		calls++
+		return bridge.DoctorReport{OverallStatus: "ready", GeneratedAt: generatedAt}, nil
+	}
+	t.Cleanup(func() {
+		runDoctorFunc = previous
+	})
+	return &calls
+}
+
+func TestDoctorReusesCachedReportWithinTTL(t *testing.T) {
+	setupCaptureHistory(t)
+	calls := stubRunDoctor(t, nowFunc().Add(-5*time.Second))
+
+	payloads := []bridge.DoctorReport{}
+	for _, args := range [][]string{
+		{"doctor", "--format", "json"},
+		{"doctor", "--format", "json"},
+		{"doctor", "--format", "json", "--fresh"},
+	} {
+		payload, _, err := runRootCommandToFile(t, args...)
+		if err != nil {
+			t.Fatalf("args %v: doctor returned error: %v", args, err)
+		}
+		var report bridge.DoctorReport
+		if err := json.Unmarshal(payload, &report); err != nil {
+			t.Fatalf("decode doctor report: %v", err)
+		}
+		payloads = append(payloads, report)
+	}
+
+	if *calls != 2 {
+		t.Fatalf("expected checks to run for the first and --fresh invocations only, got %d runs", *calls)
+	}
+	if payloads[0].Cached || !payloads[1].Cached || payloads[2].Cached {
+		t.Fatalf("unexpected cached flags: %v %v %v", payloads[0].Cached, payloads[1].Cached, payloads[2].Cached)
+	}
+	if payloads[1].GeneratedAt.IsZero() {
+		t.Fatalf("expected cached report to keep generatedAt")
+	}
+}
+
+func TestDoctorIgnoresExpiredCache(t *testing.T) {
+	setupCaptureHistory(t)
+	calls := stubRunDoctor(t, nowFunc().Add(-doctorCacheTTL-time.Second))
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := runRootCommandToFile(t, "doctor", "--format", "json"); err != nil {
+			t.Fatalf("doctor returned error: %v", err)
+		}
+	}
+	if *calls != 2 {
+		t.Fatalf("expected expired cache to be ignored, got %d runs", *calls)
+	}
+}
+
+func TestDoctorFixRerunsChecksAfterAppliedFix(t *testing.T) {
+	setupCaptureHistory(t)
+	calls := stubRunDoctor(t, nowFunc())
+	previous := fixDoctorIssuesFunc
+	t.Cleanup(func() {
+		fixDoctorIssuesFunc = previous
+	})
+	fixDoctorIssuesFunc = func(context.Context, bridge.DoctorReport) []bridge.DoctorFix {
+		return []bridge.DoctorFix{
+			{Issue: "ContextGrabberHost binary not found", Action: "ran swift build in /repo/apps/macos-host", Applied: true},
+			{Issue: "bun not found", Action: "install Bun: curl -fsSL https://bun.sh/install | bash"},
+		}
+	}
+
+	payload, _, err := runRootCommandToFile(t, "doctor", "--fix")
+	if err != nil {
+		t.Fatalf("doctor --fix returned error: %v", err)
+	}
+	if *calls != 2 {
+		t.Fatalf("expected checks before and after the fix, got %d runs", *calls)
+	}
+	for _, want := range []string{
+		"## Fixes",
+		"- fixed: ContextGrabberHost binary not found (ran swift build in /repo/apps/macos-host)",
+		"- manual: bun not found: install Bun",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Fatalf("expected %q in doctor output, got:\n%s", want, payload)
+		}
+	}
+}
+
+func TestDoctorYAMLOutput(t *testing.T) {
+	setupCaptureHistory(t)
+	stubRunDoctor(t, nowFunc())
+
+	payload, _, err := runRootCommandToFile(t, "doctor", "--format", "yaml", "--fresh")
+	if err != nil {
+		t.Fatalf("doctor --format yaml returned error: %v", err)
+	}
+	if !strings.Contains(string(payload), "overallStatus: ready\n") {
+		t.Fatalf("expected YAML doctor report, got %s", payload)
+	}
+}
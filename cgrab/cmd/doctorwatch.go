@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+)
+
+// doctorWatchChangeLimit caps how many status changes the redrawn doctor
+// report keeps, so a flapping bridge does not push the report off screen.
+const doctorWatchChangeLimit = 10
+
+// doctorStatusChange records a bridge (or the overall status, as target
+// "overall") moving between statuses across two doctor --watch refreshes.
+type doctorStatusChange struct {
+	Target string    `json:"target"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	At     time.Time `json:"at"`
+}
+
+// doctorWatchSnapshot is one line of doctor --watch JSON output.
+type doctorWatchSnapshot struct {
+	Timestamp time.Time            `json:"timestamp"`
+	Changes   []doctorStatusChange `json:"changes,omitempty"`
+	Report    bridge.DoctorReport  `json:"report"`
+}
+
+// runDoctorWatch re-runs the doctor checks on every interval until
+// interrupted. Markdown on a terminal is redrawn with the recent status
+// changes appended; otherwise each refresh appends one JSON snapshot line.
+func runDoctorWatch(ctx context.Context, stdout io.Writer, stderr io.Writer, format string, interval time.Duration) error {
+	redraw := format == formatMarkdown && isTerminalWriter(stdout)
+	var previous *bridge.DoctorReport
+	var recent []doctorStatusChange
+	return runWatchLoop(ctx, interval, func(ctx context.Context) {
+		report, err := loadDoctorReport(ctx, true)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			writeWarnings(stderr, []string{err.Error()})
+			return
+		}
+		timestamp := nowFunc().UTC()
+		var changes []doctorStatusChange
+		if previous != nil {
+			changes = diffDoctorStatuses(*previous, report, timestamp)
+		}
+		previous = &report
+
+		if !redraw {
+			line, err := json.Marshal(doctorWatchSnapshot{Timestamp: timestamp, Changes: changes, Report: report})
+			if err != nil {
+				writeWarnings(stderr, []string{err.Error()})
+				return
+			}
+			fmt.Fprintf(stdout, "%s\n", line)
+			return
+		}
+
+		recent = append(recent, changes...)
+		if len(recent) > doctorWatchChangeLimit {
+			recent = recent[len(recent)-doctorWatchChangeLimit:]
+		}
+		fmt.Fprint(stdout, clearScreenSequence)
+		fmt.Fprint(stdout, formatDoctorMarkdown(report))
+		fmt.Fprint(stdout, formatDoctorChanges(recent, len(changes)))
+	})
+}
+
+// diffDoctorStatuses lists the overall and per-bridge statuses that differ
+// between two reports, in report order. Bridges missing from either report
+// are reported as "missing".
+func diffDoctorStatuses(previous bridge.DoctorReport, current bridge.DoctorReport, at time.Time) []doctorStatusChange {
+	var changes []doctorStatusChange
+	if previous.OverallStatus != current.OverallStatus {
+		changes = append(changes, doctorStatusChange{Target: "overall", From: previous.OverallStatus, To: current.OverallStatus, At: at})
+	}
+	before := map[string]string{}
+	for _, status := range previous.Bridges {
+		before[status.Target] = status.Status
+	}
+	for _, status := range current.Bridges {
+		from, ok := before[status.Target]
+		delete(before, status.Target)
+		if !ok {
+			from = "missing"
+		}
+		if from != status.Status {
+			changes = append(changes, doctorStatusChange{Target: status.Target, From: from, To: status.Status, At: at})
+		}
+	}
+	for _, status := range previous.Bridges {
+		if _, gone := before[status.Target]; gone {
+			changes = append(changes, doctorStatusChange{Target: status.Target, From: status.Status, To: "missing", At: at})
+		}
+	}
+	return changes
+}
+
+// formatDoctorChanges renders the recent changes section of a redrawn
+// report, marking the ones detected by the latest refresh.
+func formatDoctorChanges(changes []doctorStatusChange, latest int) string {
+	lines := []string{"", "## Recent Changes"}
+	if len(changes) == 0 {
+		lines = append(lines, "- none")
+	}
+	for i, change := range changes {
+		entry := fmt.Sprintf("%s %s: %s -> %s", change.At.Format(time.RFC3339), change.Target, change.From, change.To)
+		if i >= len(changes)-latest {
+			entry = "**" + entry + "**"
+		}
+		lines = append(lines, "- "+entry)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
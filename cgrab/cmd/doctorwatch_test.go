@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+)
+
+func TestDoctorWatchAppendsJSONSnapshotsWithChanges(t *testing.T) {
+	setupCaptureHistory(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	previous := runDoctorFunc
+	runDoctorFunc = func(context.Context) (bridge.DoctorReport, error) {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		chrome := "ready"
+		if runs == 2 {
+			chrome = "unreachable"
+		}
+		return bridge.DoctorReport{
+			OverallStatus: "ready",
+			GeneratedAt:   nowFunc(),
+			Bridges: []bridge.BridgeStatus{
+				{Target: "safari", Status: "ready"},
+				{Target: "chrome", Status: chrome},
+			},
+		}, nil
+	}
+	t.Cleanup(func() {
+		runDoctorFunc = previous
+	})
+
+	var stdout bytes.Buffer
+	if err := runDoctorWatch(ctx, &stdout, io.Discard, formatMarkdown, time.Millisecond); err != nil {
+		t.Fatalf("runDoctorWatch returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 snapshots before cancellation, got %d:\n%s", len(lines), stdout.String())
+	}
+	var first, second doctorWatchSnapshot
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decode first snapshot: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("decode second snapshot: %v", err)
+	}
+	if first.Timestamp.IsZero() || len(first.Changes) != 0 {
+		t.Fatalf("expected a timestamped first snapshot without changes, got %+v", first)
+	}
+	if len(second.Changes) != 1 || second.Changes[0].Target != "chrome" || second.Changes[0].From != "ready" || second.Changes[0].To != "unreachable" {
+		t.Fatalf("expected chrome ready -> unreachable, got %+v", second.Changes)
+	}
+}
+
+func TestFormatDoctorChangesHighlightsLatest(t *testing.T) {
+	at := time.Date(2026, time.February, 15, 12, 0, 0, 0, time.UTC)
+	rendered := formatDoctorChanges([]doctorStatusChange{
+		{Target: "chrome", From: "ready", To: "unreachable", At: at},
+		{Target: "chrome", From: "unreachable", To: "ready", At: at.Add(5 * time.Second)},
+	}, 1)
+	expected := "\n## Recent Changes\n" +
+		"- 2026-02-15T12:00:00Z chrome: ready -> unreachable\n" +
+		"- **2026-02-15T12:00:05Z chrome: unreachable -> ready**\n"
+	if rendered != expected {
+		t.Fatalf("unexpected changes section:\n%s", rendered)
+	}
+}
+
+func TestDoctorWatchRejectsIncompatibleFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"doctor", "--watch", "--format", "yaml"},
+		{"doctor", "--watch", "--fix"},
+		{"doctor", "--watch", "--interval", "0s"},
+	} {
+		if _, _, err := runRootCommand(args...); err == nil {
+			t.Fatalf("args %v: expected a usage error", args)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+)
+
+var pingBrowserBridgeFunc = bridge.PingBrowserBridge
+
+// captureDryRunExclusiveFlags save, open, or diff capture output, which
+// --dry-run never produces. --file still receives the report.
+var captureDryRunExclusiveFlags = []string{"no-save", "save-as", "overwrite", "open", "reveal", "diff", "diff-only", "hash", "sidecar"}
+
+// captureDryRunReport is what capture --dry-run prints: the browser a capture
+// would use and the extraction method its bridges make likely.
+type captureDryRunReport struct {
+	Target           string `json:"target"`
+	ExtractionMethod string `json:"extractionMethod"`
+	// Tab is the tab a --tab, --url-match, or --title-match capture
+	// would activate.
+	Tab *osascript.TabEntry `json:"tab,omitempty"`
+	// Bridges lists the pinged bridges in fallback order, up to the first
+	// ready one.
+	Bridges []bridge.BridgeStatus `json:"bridges"`
+	Note    string                `json:"note,omitempty"`
+}
+
+// planBrowserCapture resolves the target of a browser capture and pings its
+// extension bridge without launching the host app, activating a tab, or
+// capturing. Targets are pinged in the order captureBrowserWithFallback
+// would try them.
+func planBrowserCapture(ctx context.Context, request captureRequest, stderr io.Writer) (captureDryRunReport, error) {
+	targetOverride, err := resolveBrowserTargetOverride(request)
+	if err != nil {
+		return captureDryRunReport{}, err
+	}
+	source, err := toBrowserCaptureSource(request.method)
+	if err != nil {
+		return captureDryRunReport{}, usageError(err)
+	}
+
+	var report captureDryRunReport
+	var targets []bridge.BrowserTarget
+	switch {
+	case request.focused:
+		var order []bridge.BrowserTarget
+		if osascript.IsAllBrowsers(request.browser) {
+			if order, err = resolveFocusedTargetOrder(request); err != nil {
+				return captureDryRunReport{}, err
+			}
+		}
+		targets = focusedTargetOrder(ctx, targetOverride, order)
+	case request.pageURL != "":
+		targets = focusedTargetOrder(ctx, targetOverride, nil)[:1]
+	default:
+		selectedTab, err := resolveTargetTab(ctx, request, targetOverride, stderr)
+		if err != nil {
+			return captureDryRunReport{}, err
+		}
+		target, err := parseOptionalBrowserTarget(selectedTab.Browser)
+		if err != nil {
+			return captureDryRunReport{}, err
+		}
+		report.Tab = selectedTab
+		targets = []bridge.BrowserTarget{target}
+	}
+
+	var safariFallback bridge.BrowserTarget
+	for _, target := range targets {
+		status := pingBrowserBridgeFunc(ctx, target)
+		report.Bridges = append(report.Bridges, status)
+		if status.Status == "ready" {
+			report.Target = string(target)
+			report.ExtractionMethod = "browser_extension"
+			return report, nil
+		}
+		if app, ok := osascript.LookupBrowser(string(target)); ok && app.Family == osascript.BrowserFamilySafari && safariFallback == "" {
+			safariFallback = target
+		}
+	}
+
+	if safariFallback != "" && source != bridge.BrowserCaptureSourceRuntime {
+		report.Target = string(safariFallback)
+		report.ExtractionMethod = "applescript_dom"
+		report.Note = "no bridge is ready; page text is read via AppleScript, which needs \"Allow JavaScript from Apple Events\""
+		return report, nil
+	}
+	report.Target = string(targets[0])
+	report.ExtractionMethod = "metadata_only"
+	report.Note = "no bridge is ready; the capture fails unless --ignore-unreachable is set, which outputs title and URL only"
+	return report, nil
+}
+
+func renderCaptureDryRun(report captureDryRunReport, format string) ([]byte, error) {
+	switch format {
+	case formatJSON, formatYAML:
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil || format == formatJSON {
+			return encoded, err
+		}
+		return output.JSONToYAML(encoded)
+	}
+
+	lines := []string{
+		"Target: " + browserDisplayName(bridge.BrowserTarget(report.Target)),
+		"Expected extraction method: " + report.ExtractionMethod,
+	}
+	if report.Tab != nil {
+		lines = append(lines, fmt.Sprintf("Tab: w%d:t%d %s (%s)", report.Tab.WindowIndex, report.Tab.TabIndex, report.Tab.Title, report.Tab.URL))
+	}
+	lines = append(lines, "Bridges:")
+	for _, status := range report.Bridges {
+		line := fmt.Sprintf("- %s: %s", status.Target, status.Status)
+		if status.Detail != "" {
+			line += " (" + status.Detail + ")"
+		}
+		lines = append(lines, line)
+	}
+	if report.Note != "" {
+		lines = append(lines, "Note: "+report.Note)
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
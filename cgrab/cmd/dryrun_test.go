@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func stubBridgePing(t *testing.T, ready map[bridge.BrowserTarget]bool) {
+	t.Helper()
+	previous := pingBrowserBridgeFunc
+	t.Cleanup(func() { pingBrowserBridgeFunc = previous })
+	pingBrowserBridgeFunc = func(_ context.Context, target bridge.BrowserTarget) bridge.BridgeStatus {
+		if ready[target] {
+			return bridge.BridgeStatus{Target: string(target), Status: "ready", Detail: "protocol=1"}
+		}
+		return bridge.BridgeStatus{Target: string(target), Status: "unreachable", Detail: "bridge reported not ready"}
+	}
+}
+
+func TestCaptureDryRunReportsFirstReadyBridgeWithoutCapturing(t *testing.T) {
+	stubCaptureEnvironment(t)
+	stubBridgePing(t, map[bridge.BrowserTarget]bool{bridge.BrowserTargetChrome: true})
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		t.Fatalf("expected --dry-run not to capture")
+		return bridge.BrowserCaptureAttempt{}, nil
+	}
+	ensureHostAppRunningFunc = func(context.Context, ...bridge.HostAppLaunchOption) (bool, error) {
+		t.Fatalf("expected --dry-run not to launch the host app")
+		return false, nil
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--focused", "--dry-run", "--format", "json")
+	if err != nil {
+		t.Fatalf("capture --dry-run returned error: %v", err)
+	}
+	var report captureDryRunReport
+	if err := json.Unmarshal(payload, &report); err != nil {
+		t.Fatalf("decode dry-run report: %v\n%s", err, payload)
+	}
+	if report.Target != "chrome" || report.ExtractionMethod != "browser_extension" || len(report.Bridges) != 2 || report.Bridges[0].Target != "safari" {
+		t.Fatalf("unexpected dry-run report: %+v", report)
+	}
+
+	stubBridgePing(t, nil)
+	payload, _, err = runRootCommandToFile(t, "capture", "--focused", "--dry-run")
+	if err != nil {
+		t.Fatalf("capture --dry-run returned error: %v", err)
+	}
+	if stdout := string(payload); !strings.Contains(stdout, "Target: Safari\nExpected extraction method: applescript_dom\n") {
+		t.Fatalf("expected the Safari AppleScript fallback, got %q", stdout)
+	}
+}
+
+func TestCaptureDryRunResolvesTabWithoutActivating(t *testing.T) {
+	stubCaptureEnvironment(t)
+	stubBridgePing(t, nil)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "chrome", WindowIndex: 1, TabIndex: 2, Title: "Docs", URL: "https://example.com/docs"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	activateTabFunc = func(context.Context, string, int, int) error {
+		t.Fatalf("expected --dry-run not to activate a tab")
+		return nil
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--url-match", "docs", "--dry-run")
+	if err != nil {
+		t.Fatalf("capture --dry-run returned error: %v", err)
+	}
+	stdout := string(payload)
+	for _, want := range []string{"Expected extraction method: metadata_only", "Tab: w1:t2 Docs (https://example.com/docs)", "- chrome: unreachable", "--ignore-unreachable"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in dry-run output, got %q", want, stdout)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--app", "Finder", "--dry-run"},
+		{"capture", "--focused", "--dry-run", "--clipboard"},
+		{"capture", "--focused", "--dry-run", "--diff"},
+		{"capture", "--tab", "1:2", "--method", "url-pdf", "--dry-run"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by cgrab. Scripts can rely on these values; any error
+// not covered below exits with ExitCodeFailure.
+const (
+	ExitCodeOK          = 0
+	ExitCodeFailure     = 1
+	ExitCodeNoMatch     = 2
+	ExitCodeUnavailable = 3
+	ExitCodePermission  = 4
+	ExitCodeUsage       = 5
+	// ExitCodeInterrupted follows the shell convention of 128 + SIGINT.
+	ExitCodeInterrupted = 130
+)
+
+// cliError attaches a process exit code to an error without changing its
+// message.
+type cliError struct {
+	code int
+	err  error
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var existing *cliError
+	if errors.As(err, &existing) {
+		return err
+	}
+	return &cliError{code: code, err: err}
+}
+
+func usageError(err error) error {
+	return withExitCode(ExitCodeUsage, err)
+}
+
+func noMatchError(err error) error {
+	return withExitCode(ExitCodeNoMatch, err)
+}
+
+func unavailableError(err error) error {
+	return withExitCode(ExitCodeUnavailable, err)
+}
+
+// applyUsageArgs tags argument-count errors from each command's Args
+// validator as usage errors. The root command, which takes no arguments,
+// reports an unknown subcommand the way cobra does, with suggestions; it is
+// made runnable (printing help, as before) so cobra validates its arguments
+// instead of failing in command lookup with an untagged error.
+func applyUsageArgs(command *cobra.Command) {
+	if !command.HasParent() {
+		if command.RunE == nil && command.Run == nil {
+			command.RunE = func(cmd *cobra.Command, _ []string) error {
+				return cmd.Help()
+			}
+		}
+		command.Args = func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return nil
+			}
+			message := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+			if cmd.SuggestionsMinimumDistance <= 0 {
+				cmd.SuggestionsMinimumDistance = 2
+			}
+			if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+				message += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+			}
+			return usageError(errors.New(message))
+		}
+	} else if validate := command.Args; validate != nil {
+		command.Args = func(cmd *cobra.Command, args []string) error {
+			return usageError(validate(cmd, args))
+		}
+	}
+	for _, child := range command.Commands() {
+		applyUsageArgs(child)
+	}
+}
+
+// interruptedResult tags an error returned after ctx was canceled by
+// SIGINT/SIGTERM with ExitCodeInterrupted, replacing any code the failed
+// call was already given. Commands that stop cleanly on interrupt (--watch)
+// return nil and keep exit code 0.
+func interruptedResult(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	return &cliError{code: ExitCodeInterrupted, err: fmt.Errorf("interrupted: %w", err)}
+}
+
+// permissionMarkers are substrings osascript and the host app emit when
+// Automation or Accessibility access has not been granted.
+var permissionMarkers = []string{
+	"not authorized to send apple events",
+	"(-1743)",
+	"not allowed assistive access",
+	"(-25211)",
+	"accessibility permission",
+}
+
+// classifyPermissionError tags err with ExitCodePermission when its message
+// indicates a denied macOS privacy permission, and returns it unchanged
+// otherwise.
+func classifyPermissionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range permissionMarkers {
+		if strings.Contains(message, marker) {
+			return withExitCode(ExitCodePermission, err)
+		}
+	}
+	return err
+}
+
+// ExitCode maps an error returned by Execute to the process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	var coded *cliError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return ExitCodeFailure
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func stubCaptureEnvironment(t *testing.T) {
+	t.Helper()
+	previousEnsure := ensureHostAppRunningFunc
+	previousCaptureBrowser := captureBrowserFunc
+	previousCaptureDesktop := captureDesktopFunc
+	previousActivateTab := activateTabFunc
+	previousActivateByName := activateAppByNameFunc
+	previousWindowCount := browserWindowCountFunc
+	t.Cleanup(func() {
+		ensureHostAppRunningFunc = previousEnsure
+		captureBrowserFunc = previousCaptureBrowser
+		captureDesktopFunc = previousCaptureDesktop
+		activateTabFunc = previousActivateTab
+		activateAppByNameFunc = previousActivateByName
+		browserWindowCountFunc = previousWindowCount
+	})
+	ensureHostAppRunningFunc = func(context.Context, ...bridge.HostAppLaunchOption) (bool, error) {
+		return true, nil
+	}
+	activateTabFunc = func(context.Context, string, int, int) error {
+		return nil
+	}
+	activateAppByNameFunc = func(context.Context, string) error {
+		return nil
+	}
+	browserWindowCountFunc = func(context.Context, string) (int, error) {
+		return 1, nil
+	}
+	t.Cleanup(stubFrontmostApp(osascript.AppEntry{}, errors.New("no frontmost app")))
+	t.Cleanup(stubSafariPageText(osascript.PageText{}, errors.New("javascript disabled")))
+}
+
+func TestExitCodeMapsPlainAndNilErrors(t *testing.T) {
+	if code := ExitCode(nil); code != ExitCodeOK {
+		t.Fatalf("expected %d for nil error, got %d", ExitCodeOK, code)
+	}
+	if code := ExitCode(errors.New("boom")); code != ExitCodeFailure {
+		t.Fatalf("expected %d for untyped error, got %d", ExitCodeFailure, code)
+	}
+	wrapped := fmt.Errorf("outer: %w", noMatchError(errors.New("inner")))
+	if code := ExitCode(wrapped); code != ExitCodeNoMatch {
+		t.Fatalf("expected wrapped code %d, got %d", ExitCodeNoMatch, code)
+	}
+}
+
+func TestExitCodeInvalidArguments(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", filepath.Join(t.TempDir(), "contextgrabber"))
+	cases := [][]string{
+		{"capture"},
+		{"capture", "--focused", "--app", "Finder"},
+		{"list", "--no-such-flag"},
+		{"list", "--format", "xml"},
+		{"config", "get"},
+		{"config", "get", "nope"},
+		{"config", "set", "nope", "value"},
+		{"config", "set", "target-order", "firefox"},
+		{"bogus"},
+		{"history", "list", "--format", "markdown-table"},
+	}
+	for _, args := range cases {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeUsage {
+			t.Fatalf("args %v: expected exit code %d, got %d (err=%v)", args, ExitCodeUsage, code, err)
+		}
+	}
+}
+
+func TestExitCodeNoTargetMatched(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, URL: "https://example.com"}}, nil, nil
+		},
+		func(context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder"}}, nil, nil
+		},
+	)
+	defer restore()
+
+	for _, args := range [][]string{
+		{"capture", "--url-match", "missing.example"},
+		{"capture", "--name-match", "missing"},
+	} {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeNoMatch {
+			t.Fatalf("args %v: expected exit code %d, got %d (err=%v)", args, ExitCodeNoMatch, code, err)
+		}
+	}
+}
+
+func TestExitCodeBridgeUnavailable(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureBrowserFunc = func(
+		context.Context,
+		bridge.BrowserTarget,
+		bridge.BrowserCaptureSource,
+		int,
+		bridge.BrowserCaptureMetadata,
+	) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{}, errors.New("bun not found; browser capture is unavailable")
+	}
+	captureDesktopFunc = func(context.Context, bridge.DesktopCaptureRequest) ([]byte, error) {
+		return nil, bridge.ErrHostBinaryNotFound
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--focused"},
+		{"capture", "--app", "Finder"},
+	} {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeUnavailable {
+			t.Fatalf("args %v: expected exit code %d, got %d (err=%v)", args, ExitCodeUnavailable, code, err)
+		}
+	}
+}
+
+func TestExitCodePermissionDenied(t *testing.T) {
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return nil, nil, errors.New("execution error: Not authorized to send Apple events to Safari. (-1743)")
+		},
+		nil,
+	)
+	defer restore()
+
+	_, _, err := runRootCommand("list", "tabs")
+	if code := ExitCode(err); code != ExitCodePermission {
+		t.Fatalf("expected exit code %d, got %d (err=%v)", ExitCodePermission, code, err)
+	}
+
+	// -1719 is AppleScript's generic invalid-index error, not a denial.
+	invalidIndex := errors.New("execution error: Can't get window 3. Invalid index. (-1719)")
+	if code := ExitCode(classifyPermissionError(invalidIndex)); code != ExitCodeFailure {
+		t.Fatalf("expected an invalid index to exit %d, got %d", ExitCodeFailure, code)
+	}
+}
+
+func TestExecuteContextReportsInterruption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	restore := stubListSources(
+		func(ctx context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			// Simulate SIGINT arriving while osascript runs; the killed
+			// subprocess error would otherwise look like a permission denial.
+			cancel()
+			return nil, nil, fmt.Errorf("Not authorized to send Apple events to Safari. (-1743): %w", ctx.Err())
+		},
+		nil,
+	)
+	defer restore()
+	previousArgs := os.Args
+	os.Args = []string{"cgrab", "list", "tabs"}
+	defer func() { os.Args = previousArgs }()
+
+	err := ExecuteContext(ctx)
+	if code := ExitCode(err); code != ExitCodeInterrupted {
+		t.Fatalf("expected exit code %d, got %d (err=%v)", ExitCodeInterrupted, code, err)
+	}
+	if !errors.Is(err, context.Canceled) || !strings.HasPrefix(err.Error(), "interrupted: ") {
+		t.Fatalf("expected a wrapped interruption error, got %v", err)
+	}
+	if interruptedResult(ctx, nil) != nil {
+		t.Fatalf("a command that stops cleanly on interrupt should keep succeeding")
+	}
+}
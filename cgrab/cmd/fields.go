@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+var (
+	tabFieldNames = jsonFieldNames(reflect.TypeOf(osascript.TabEntry{}))
+	appFieldNames = jsonFieldNames(reflect.TypeOf(osascript.AppEntry{}))
+)
+
+// jsonFieldNames returns the JSON keys of a struct type in declaration order.
+func jsonFieldNames(entryType reflect.Type) []string {
+	names := make([]string, 0, entryType.NumField())
+	for i := 0; i < entryType.NumField(); i++ {
+		tag := entryType.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func parseFieldList(raw string) []string {
+	var fields []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		field := strings.TrimSpace(part)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// validateListFields rejects --fields values that the selected entry kinds
+// do not expose, and --fields combined with a non-JSON format.
+func validateListFields(fields []string, format string, selection listSelection) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if format != formatJSON && format != formatJSONL && format != formatYAML {
+		return fmt.Errorf("--fields requires --format json, jsonl, or yaml")
+	}
+
+	var valid []string
+	if selection.tabs {
+		valid = append(valid, tabFieldNames...)
+	}
+	if selection.apps {
+		valid = append(valid, appFieldNames...)
+	}
+	for _, field := range fields {
+		if !containsString(valid, field) {
+			return fmt.Errorf("unknown --fields value %q (valid fields: %s)", field, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// entriesView returns entries as-is, or re-encoded as maps holding only the
+// requested fields that exist on the entry type.
+func entriesView[T any](entries []T, fields []string, available []string) ([]any, error) {
+	view := make([]any, 0, len(entries))
+	if len(fields) == 0 {
+		for _, entry := range entries {
+			view = append(view, entry)
+		}
+		return view, nil
+	}
+
+	for _, entry := range entries {
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		var full map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &full); err != nil {
+			return nil, err
+		}
+		projected := map[string]json.RawMessage{}
+		for _, field := range fields {
+			if !containsString(available, field) {
+				continue
+			}
+			if value, ok := full[field]; ok {
+				projected[field] = value
+			}
+		}
+		view = append(view, projected)
+	}
+	return view, nil
+}
+
+// tagRecord adds the combined JSON Lines type discriminator to an entry view.
+func tagRecord(kind string, entry any) any {
+	switch value := entry.(type) {
+	case map[string]json.RawMessage:
+		value["type"] = json.RawMessage(strconv.Quote(kind))
+		return value
+	case osascript.TabEntry:
+		return jsonlTabRecord{Type: kind, TabEntry: value}
+	case osascript.AppEntry:
+		return jsonlAppRecord{Type: kind, AppEntry: value}
+	default:
+		return entry
+	}
+}
+
+func containsString(values []string, needle string) bool {
+	for _, value := range values {
+		if value == needle {
+			return true
+		}
+	}
+	return false
+}
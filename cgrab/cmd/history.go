@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type historyEntry struct {
+	Path       string    `json:"path"`
+	CapturedAt time.Time `json:"capturedAt"`
+	Format     string    `json:"format"`
+	SizeBytes  int64     `json:"sizeBytes"`
+}
+
+func newHistoryCommand(global *globalOptions) *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Inspect auto-saved captures",
+	}
+	historyCmd.AddCommand(newHistoryListCommand(global))
+	historyCmd.AddCommand(newHistoryCleanCommand())
+	return historyCmd
+}
+
+func newHistoryListCommand(global *globalOptions) *cobra.Command {
+	var since string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List auto-saved captures",
+		Example: "  cgrab history list\n" +
+			"  cgrab history list --since 24h --format json",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var cutoff time.Time
+			if strings.TrimSpace(since) != "" {
+				age, err := parseHistoryAge(since)
+				if err != nil {
+					return usageError(fmt.Errorf("invalid --since value: %w", err))
+				}
+				cutoff = nowFunc().Add(-age)
+			}
+
+			captureDir, err := resolveCaptureDir()
+			if err != nil {
+				return err
+			}
+			entries, err := scanCaptureHistory(captureDir)
+			if err != nil {
+				return err
+			}
+
+			recent := make([]historyEntry, 0, len(entries))
+			for _, entry := range entries {
+				if entry.CapturedAt.After(cutoff) {
+					recent = append(recent, entry)
+				}
+			}
+
+			rendered, err := renderHistory(global.format, recent)
+			if err != nil {
+				return err
+			}
+			return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr())
+		},
+	}
+	listCmd.Flags().StringVar(&since, "since", "", "only list captures newer than a duration (e.g. 24h, 7d)")
+	return listCmd
+}
+
+func newHistoryCleanCommand() *cobra.Command {
+	var olderThan string
+	cleanCmd := &cobra.Command{
+		Use:     "clean",
+		Short:   "Delete old auto-saved captures",
+		Example: "  cgrab history clean --older-than 30d",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(olderThan) == "" {
+				return usageError(fmt.Errorf("history clean requires --older-than"))
+			}
+			age, err := parseHistoryAge(olderThan)
+			if err != nil {
+				return usageError(fmt.Errorf("invalid --older-than value: %w", err))
+			}
+			cutoff := nowFunc().Add(-age)
+
+			captureDir, err := resolveCaptureDir()
+			if err != nil {
+				return err
+			}
+			entries, err := scanCaptureHistory(captureDir)
+			if err != nil {
+				return err
+			}
+
+			removed := 0
+			for _, entry := range entries {
+				if !entry.CapturedAt.Before(cutoff) {
+					continue
+				}
+				if err := os.Remove(entry.Path); err != nil {
+					return fmt.Errorf("remove capture: %w", err)
+				}
+				removed++
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d capture(s) from %s\n", removed, captureDir)
+			return nil
+		},
+	}
+	cleanCmd.Flags().StringVar(&olderThan, "older-than", "", "delete captures older than a duration (e.g. 30d, 12h)")
+	return cleanCmd
+}
+
+// parseHistoryAge accepts Go durations plus a day suffix (e.g. 30d, 1.5d).
+func parseHistoryAge(raw string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(raw)
+	var age time.Duration
+	if days, ok := strings.CutSuffix(trimmed, "d"); ok {
+		value, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration (e.g. 24h, 30d)", raw)
+		}
+		age = time.Duration(value * float64(24*time.Hour))
+	} else {
+		parsed, err := time.ParseDuration(trimmed)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration (e.g. 24h, 30d)", raw)
+		}
+		age = parsed
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("%q must be positive", raw)
+	}
+	return age, nil
+}
+
+// scanCaptureHistory returns auto-saved captures in dir, newest first. Files
+// that do not follow the capture naming scheme are ignored.
+func scanCaptureHistory(dir string) ([]historyEntry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read capture dir: %w", err)
+	}
+
+	entries := []historyEntry{}
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		capturedAt, format, ok := parseCaptureFileName(dirEntry.Name())
+		if !ok {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{
+			Path:       filepath.Join(dir, dirEntry.Name()),
+			CapturedAt: capturedAt,
+			Format:     format,
+			SizeBytes:  info.Size(),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CapturedAt.After(entries[j].CapturedAt)
+	})
+	return entries, nil
+}
+
+// historyFormatPDF is the history format of a --method url-pdf capture.
+const historyFormatPDF = "pdf"
+
+// captureFileFormats maps each extension auto-saves are written with (see
+// captureOutputExtension) to the format history reports for it.
+var captureFileFormats = map[string]string{
+	captureOutputExtension(formatMarkdown, ""):                  formatMarkdown,
+	captureOutputExtension(formatJSON, ""):                      formatJSON,
+	captureOutputExtension(formatYAML, ""):                      formatYAML,
+	captureOutputExtension(formatMarkdown, browserMethodURLPDF): historyFormatPDF,
+}
+
+func parseCaptureFileName(name string) (time.Time, string, bool) {
+	stem, ok := strings.CutPrefix(name, captureFilePrefix)
+	if !ok {
+		return time.Time{}, "", false
+	}
+	stem = strings.TrimSuffix(stem, gzipExtension)
+	format, ok := captureFileFormats[filepath.Ext(stem)]
+	if !ok {
+		return time.Time{}, "", false
+	}
+	capturedAt, err := time.Parse(captureFileTimestampLayout, strings.TrimSuffix(stem, filepath.Ext(stem)))
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return capturedAt.UTC(), format, true
+}
+
+func renderHistory(format string, entries []historyEntry) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		return json.MarshalIndent(entries, "", "  ")
+	case formatJSONL:
+		return marshalJSONLines(entries)
+	case formatMarkdown:
+		if len(entries) == 0 {
+			return []byte("No captures found.\n"), nil
+		}
+		lines := []string{"# Capture History"}
+		for _, entry := range entries {
+			lines = append(lines, fmt.Sprintf("- %s - %s", entry.CapturedAt.Format(time.RFC3339), entry.Path))
+		}
+		return []byte(strings.Join(lines, "\n") + "\n"), nil
+	default:
+		return nil, usageError(fmt.Errorf("unsupported format: %s", format))
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupCaptureHistory(t *testing.T, names ...string) string {
+	t.Helper()
+	previousNowFunc := nowFunc
+	t.Cleanup(func() {
+		nowFunc = previousNowFunc
+	})
+	nowFunc = func() time.Time {
+		return time.Date(2026, time.February, 15, 12, 0, 0, 0, time.UTC)
+	}
+
+	baseDir := filepath.Join(t.TempDir(), "contextgrabber")
+	t.Setenv("CONTEXT_GRABBER_CLI_HOME", baseDir)
+	captureDir := filepath.Join(baseDir, "captures")
+	if err := os.MkdirAll(captureDir, 0o755); err != nil {
+		t.Fatalf("mkdir capture dir: %v", err)
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(captureDir, name), []byte("# capture\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return captureDir
+}
+
+func TestHistoryListSinceFiltersByFileTimestamp(t *testing.T) {
+	setupCaptureHistory(t,
+		"capture-20260215-100000.000.md",
+		"capture-20260210-100000.000.json",
+		"notes.md",
+	)
+
+	payloadBytes, _, err := runRootCommandToFile(t, "history", "list", "--since", "24h", "--format", "json")
+	if err != nil {
+		t.Fatalf("history list returned error: %v", err)
+	}
+	var entries []historyEntry
+	if err := json.Unmarshal(payloadBytes, &entries); err != nil {
+		t.Fatalf("invalid JSON %q: %v", string(payloadBytes), err)
+	}
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Path, "capture-20260215-100000.000.md") {
+		t.Fatalf("unexpected history entries: %#v", entries)
+	}
+	if entries[0].Format != formatMarkdown || entries[0].SizeBytes == 0 {
+		t.Fatalf("unexpected entry metadata: %#v", entries[0])
+	}
+}
+
+func TestHistoryCleanRemovesOnlyOldCaptures(t *testing.T) {
+	captureDir := setupCaptureHistory(t,
+		"capture-20260215-100000.000.md",
+		"capture-20260101-100000.000.md",
+		"keep-me.txt",
+	)
+
+	stdout, _, err := runRootCommand("history", "clean", "--older-than", "30d")
+	if err != nil {
+		t.Fatalf("history clean returned error: %v", err)
+	}
+	if !strings.Contains(stdout, "Removed 1 capture(s)") {
+		t.Fatalf("unexpected clean output: %q", stdout)
+	}
+	remaining, err := os.ReadDir(captureDir)
+	if err != nil {
+		t.Fatalf("read capture dir: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 files to remain, got %d", len(remaining))
+	}
+}
+
+func TestParseHistoryAge(t *testing.T) {
+	cases := map[string]time.Duration{
+		"24h": 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for raw, want := range cases {
+		got, err := parseHistoryAge(raw)
+		if err != nil || got != want {
+			t.Fatalf("parseHistoryAge(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "soon", "-1d", "0h"} {
+		if _, err := parseHistoryAge(raw); err == nil {
+			t.Fatalf("expected parseHistoryAge(%q) to fail", raw)
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/anthonylu23/context_grabber/cgrab/internal/eventlog"
 	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
 	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
 	"github.com/spf13/cobra"
@@ -16,6 +21,15 @@ func newListCommand(global *globalOptions) *cobra.Command {
 	var includeTabs bool
 	var includeApps bool
 	var browser string
+	var fields string
+	var count bool
+	var delimiter string
+	var includeAppPath bool
+	var noHeaders bool
+	var watch bool
+	var interval time.Duration
+	var filter tabFilter
+	var urls urlCleaner
 
 	listCmd := &cobra.Command{
 		Use:   "list",
@@ -23,59 +37,119 @@ func newListCommand(global *globalOptions) *cobra.Command {
 		Example: "  cgrab list\n" +
 			"  cgrab list --tabs --browser chrome --format json\n" +
 			"  cgrab list --apps\n" +
-			"  cgrab list tabs",
+			"  cgrab list --watch --interval 2s\n" +
+			"  cgrab list tabs\n" +
+			"  cgrab list open w1:t2 --browser safari",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			selection := resolveListSelection(includeTabs, includeApps)
-			result := combinedListResult{
-				Tabs: []osascript.TabEntry{},
-				Apps: []osascript.AppEntry{},
+			options := listRenderOptions{fields: parseFieldList(fields), count: count, delimiter: delimiter, includeAppPath: includeAppPath, noHeaders: noHeaders}
+			if err := options.validate(global.format, selection); err != nil {
+				return usageError(err)
 			}
-
-			successCount := 0
-			var failures []string
-			if selection.tabs {
-				tabs, warnings, err := listTabsFunc(cmd.Context(), browser)
-				writeWarnings(cmd.ErrOrStderr(), warnings)
-				if err != nil {
-					failures = append(failures, fmt.Sprintf("tabs failed: %v", err))
-				} else {
-					result.Tabs = tabs
-					successCount++
+			renderOnce := func(ctx context.Context) ([]byte, error) {
+				result, err := collectCombinedList(ctx, selection, browser, filter, urls)
+				if err != nil || !embedsListWarnings(global.format, selection, options) {
+					writeWarnings(global.warnings(cmd.ErrOrStderr()), result.Warnings)
 				}
-			}
-			if selection.apps {
-				apps, err := listAppsFunc(cmd.Context())
 				if err != nil {
-					failures = append(failures, fmt.Sprintf("apps failed: %v", err))
-				} else {
-					result.Apps = apps
-					successCount++
+					return nil, err
 				}
+				return renderCombinedList(global.format, selection, result, options)
 			}
 
-			if len(failures) > 0 && successCount == 0 {
-				return fmt.Errorf("%s", strings.Join(failures, "; "))
-			}
-			if len(failures) > 0 {
-				writeWarnings(cmd.ErrOrStderr(), failures)
+			if watch {
+				if global.format != formatMarkdown {
+					return usageError(fmt.Errorf("--watch supports only --format markdown"))
+				}
+				if global.outputFile != "" || global.clipboard {
+					return usageError(fmt.Errorf("--watch cannot be combined with --file or --clipboard"))
+				}
+				if interval <= 0 {
+					return usageError(fmt.Errorf("--interval must be positive"))
+				}
+				return runListWatch(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), interval, renderOnce)
 			}
 
-			rendered, err := renderCombinedList(global.format, selection, result)
+			rendered, err := renderOnce(cmd.Context())
 			if err != nil {
 				return err
 			}
-			return output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard)
+			return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr())
 		},
 	}
 
 	listCmd.AddCommand(newListTabsCommand(global))
 	listCmd.AddCommand(newListAppsCommand(global))
+	listCmd.AddCommand(newListOpenCommand(global))
 	listCmd.Flags().BoolVar(&includeTabs, "tabs", false, "include browser tabs")
 	listCmd.Flags().BoolVar(&includeApps, "apps", false, "include running desktop apps")
-	listCmd.Flags().StringVar(&browser, "browser", "", "browser filter for tabs: safari or chrome")
+	listCmd.Flags().StringVar(&browser, "browser", "", "browser filter for tabs: all (default, every running browser), safari, safari-tp, chrome, chrome-beta, chrome-dev, or chrome-canary")
+	listCmd.Flags().StringVar(&fields, "fields", "", "comma-separated JSON fields to keep (e.g. title,url)")
+	listCmd.Flags().BoolVar(&count, "count", false, "print only the number of entries (tabs=<n> apps=<n> when listing both)")
+	listCmd.Flags().StringVar(&delimiter, "delimiter", "", delimiterFlagUsage)
+	listCmd.Flags().BoolVar(&includeAppPath, "include-app-path", false, includeAppPathFlagUsage)
+	listCmd.Flags().BoolVar(&noHeaders, "no-headers", false, noHeadersFlagUsage)
+	listCmd.Flags().BoolVar(&watch, "watch", false, "re-run the listing on an interval until interrupted")
+	listCmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "refresh interval for --watch")
+	filter.register(listCmd)
+	urls.register(listCmd)
 	return listCmd
 }
 
+// collectCombinedList queries the selected sources. A failing source is
+// reported as a warning unless every selected source failed. Warnings are
+// returned in the result, even with an error, for the caller to print or
+// embed.
+func collectCombinedList(
+	ctx context.Context,
+	selection listSelection,
+	browser string,
+	filter tabFilter,
+	urls urlCleaner,
+) (combinedListResult, error) {
+	result := combinedListResult{
+		Tabs:     []osascript.TabEntry{},
+		Apps:     []osascript.AppEntry{},
+		Warnings: []string{},
+	}
+
+	eventlog.Emit(ctx, "list_start", map[string]any{"tabs": selection.tabs, "apps": selection.apps, "browser": browser})
+	successCount := 0
+	var failures []string
+	if selection.tabs {
+		tabs, warnings, err := listTabsFunc(ctx, browser)
+		result.Warnings = append(result.Warnings, warnings...)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("tabs failed: %v", err))
+		} else {
+			result.Tabs = urls.applyTabs(filter.apply(tabs))
+			successCount++
+		}
+	}
+	if selection.apps {
+		apps, warnings, err := listAppsFunc(ctx)
+		result.Warnings = append(result.Warnings, warnings...)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("apps failed: %v", err))
+		} else {
+			result.Apps = apps
+			successCount++
+		}
+	}
+
+	eventlog.Emit(ctx, "list_end", map[string]any{
+		"ok":       successCount > 0 || len(failures) == 0,
+		"tabCount": len(result.Tabs),
+		"appCount": len(result.Apps),
+		"failures": failures,
+	})
+	if len(failures) > 0 && successCount == 0 {
+		return combinedListResult{Warnings: result.Warnings}, classifyPermissionError(fmt.Errorf("%s", strings.Join(failures, "; ")))
+	}
+	result.Warnings = append(result.Warnings, failures...)
+	return result, nil
+}
+
 type listSelection struct {
 	tabs bool
 	apps bool
@@ -88,61 +162,273 @@ func resolveListSelection(includeTabs bool, includeApps bool) listSelection {
 	return listSelection{tabs: includeTabs, apps: includeApps}
 }
 
+type listRenderOptions struct {
+	fields []string
+	// count replaces the entries with their number, ignoring --format for
+	// single-kind listings.
+	count bool
+	// delimiter overrides the comma of --format csv.
+	delimiter string
+	// byHost replaces the tab entries with per-host title groups.
+	byHost bool
+	// includeAppPath keeps AppEntry.AppPath in the output.
+	includeAppPath bool
+	// frontmostFirst moves the frontmost app to the top of app listings.
+	frontmostFirst bool
+	// appSort is the app listing order: appSortName or appSortWindowsDesc.
+	appSort string
+	// tabSort is the tab listing order: tabSortIndex or tabSortRecent. With
+	// tabSortRecent the tabs arrive sorted and markdown keeps their order.
+	tabSort string
+	// noHeaders drops markdown headings (and the empty-listing notes), so
+	// only the bullet lines remain.
+	noHeaders bool
+}
+
+const (
+	appSortName        = "name"
+	appSortWindowsDesc = "windows-desc"
+)
+
+// appOrderLess orders app listings: the frontmost app first with
+// --frontmost-first, then by window count (high to low) with --sort
+// windows-desc, then by name and bundle identifier.
+func appOrderLess(a osascript.AppEntry, b osascript.AppEntry, options listRenderOptions) bool {
+	if options.frontmostFirst && a.IsFrontmost != b.IsFrontmost {
+		return a.IsFrontmost
+	}
+	if options.appSort == appSortWindowsDesc && a.WindowCount != b.WindowCount {
+		return a.WindowCount > b.WindowCount
+	}
+	if a.AppName != b.AppName {
+		return a.AppName < b.AppName
+	}
+	return a.BundleIdentifier < b.BundleIdentifier
+}
+
 type combinedListResult struct {
-	Tabs []osascript.TabEntry `json:"tabs"`
-	Apps []osascript.AppEntry `json:"apps"`
+	Tabs     []osascript.TabEntry `json:"tabs"`
+	Apps     []osascript.AppEntry `json:"apps"`
+	Warnings []string             `json:"warnings"`
 }
 
-func renderCombinedList(format string, selection listSelection, result combinedListResult) ([]byte, error) {
+// embedsListWarnings reports whether the combined JSON/YAML document carries
+// the listing warnings in its warnings array, keeping them off stderr. Other
+// output (markdown, single-kind arrays, counts) leaves them on stderr.
+func embedsListWarnings(format string, selection listSelection, options listRenderOptions) bool {
+	return (format == formatJSON || format == formatYAML) && selection.tabs && selection.apps && !options.count
+}
+
+// jsonlTabRecord and jsonlAppRecord tag each line of combined JSON Lines
+// output so consumers can tell tabs and apps apart.
+type jsonlTabRecord struct {
+	Type string `json:"type"`
+	osascript.TabEntry
+}
+
+type jsonlAppRecord struct {
+	Type string `json:"type"`
+	osascript.AppEntry
+}
+
+// marshalJSONLines encodes each record as compact JSON on its own line.
+func marshalJSONLines[T any](records []T) ([]byte, error) {
+	var buffer bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(line)
+		buffer.WriteByte('\n')
+	}
+	return buffer.Bytes(), nil
+}
+
+func renderCombinedList(
+	format string,
+	selection listSelection,
+	result combinedListResult,
+	options listRenderOptions,
+) ([]byte, error) {
+	if format == formatYAML {
+		return renderJSONAsYAML(func(format string) ([]byte, error) {
+			return renderCombinedList(format, selection, result, options)
+		})
+	}
 	if selection.tabs && !selection.apps {
-		return renderTabs(format, result.Tabs)
+		return renderTabs(format, result.Tabs, options)
 	}
+	result.Apps = appsForOutput(result.Apps, options)
 	if selection.apps && !selection.tabs {
-		return renderApps(format, result.Apps)
+		return renderApps(format, result.Apps, options)
+	}
+	if options.count {
+		return renderCombinedCount(format, len(result.Tabs), len(result.Apps))
 	}
 
 	switch format {
-	case formatJSON:
-		return json.MarshalIndent(result, "", "  ")
+	case formatJSON, formatJSONL:
+		tabsView, err := entriesView(result.Tabs, options.fields, tabFieldNames)
+		if err != nil {
+			return nil, err
+		}
+		appsView, err := entriesView(result.Apps, options.fields, appFieldNames)
+		if err != nil {
+			return nil, err
+		}
+		if format == formatJSON {
+			return json.MarshalIndent(struct {
+				Tabs     []any    `json:"tabs"`
+				Apps     []any    `json:"apps"`
+				Warnings []string `json:"warnings"`
+			}{Tabs: tabsView, Apps: appsView, Warnings: result.Warnings}, "", "  ")
+		}
+		records := make([]any, 0, len(tabsView)+len(appsView))
+		for _, tab := range tabsView {
+			records = append(records, tagRecord("tab", tab))
+		}
+		for _, app := range appsView {
+			records = append(records, tagRecord("app", app))
+		}
+		return marshalJSONLines(records)
 	case formatMarkdown:
-		tabsMarkdown, err := renderTabs(formatMarkdown, result.Tabs)
+		tabsMarkdown, err := renderTabs(formatMarkdown, result.Tabs, options)
 		if err != nil {
 			return nil, err
 		}
-		appsMarkdown, err := renderApps(formatMarkdown, result.Apps)
+		appsMarkdown, err := renderApps(formatMarkdown, result.Apps, options)
 		if err != nil {
 			return nil, err
 		}
+		if options.noHeaders {
+			return append(tabsMarkdown, appsMarkdown...), nil
+		}
 		combined := strings.TrimSpace(string(tabsMarkdown)) + "\n\n" + strings.TrimSpace(string(appsMarkdown)) + "\n"
 		return []byte(combined), nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
+		return nil, usageError(fmt.Errorf("unsupported format: %s", format))
 	}
 }
 
 func newListTabsCommand(global *globalOptions) *cobra.Command {
 	var browser string
+	var fields string
+	var count bool
+	var delimiter string
+	var byHost bool
+	var noHeaders bool
+	var current bool
+	var sortOrder string
+	var filter tabFilter
+	var urls urlCleaner
+	var changes tabChangeOptions
 	tabsCmd := &cobra.Command{
 		Use:   "tabs",
 		Short: "Show open browser tabs",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			tabs, warnings, err := listTabsFunc(cmd.Context(), browser)
-			writeWarnings(cmd.ErrOrStderr(), warnings)
+			options := listRenderOptions{
+				fields:    parseFieldList(fields),
+				count:     count,
+				delimiter: delimiter,
+				byHost:    byHost,
+				tabSort:   strings.ToLower(strings.TrimSpace(sortOrder)),
+				noHeaders: noHeaders,
+			}
+			if err := options.validate(global.format, listSelection{tabs: true}); err != nil {
+				return usageError(err)
+			}
+			if err := changes.validate(global.format, options); err != nil {
+				return usageError(err)
+			}
+			if current && strings.TrimSpace(browser) != "" {
+				return usageError(fmt.Errorf("--current cannot be combined with --browser"))
+			}
+			var snapshot []osascript.TabEntry
+			if changes.changedSince != "" {
+				loaded, err := loadTabSnapshot(changes.changedSince)
+				if err != nil {
+					return usageError(err)
+				}
+				snapshot = urls.applyTabs(filter.apply(loaded))
+			}
+
+			var currentWarnings []string
+			if current {
+				if target, ok := frontmostBrowserTarget(cmd.Context()); ok {
+					browser = string(target)
+				} else {
+					currentWarnings = append(currentWarnings, "--current: the frontmost app is not a supported browser; listing tabs from all browsers")
+				}
+			}
+			listTabs := listTabsFunc
+			if options.tabSort == tabSortRecent {
+				listTabs = listTabsByRecent
+			}
+			tabs, warnings, err := listTabs(cmd.Context(), browser)
+			writeWarnings(global.warnings(cmd.ErrOrStderr()), append(currentWarnings, warnings...))
 			if err != nil {
-				return err
+				return classifyPermissionError(err)
 			}
 
-			rendered, err := renderTabs(global.format, tabs)
+			tabs = urls.applyTabs(filter.apply(tabs))
+			var rendered []byte
+			switch {
+			case changes.showClosed:
+				opened, closed := diffTabsByURL(snapshot, tabs)
+				rendered, err = renderTabChanges(global.format, opened, closed, options)
+			case changes.changedSince != "":
+				opened, _ := diffTabsByURL(snapshot, tabs)
+				rendered, err = renderTabs(global.format, opened, options)
+			default:
+				rendered, err = renderTabs(global.format, tabs, options)
+			}
 			if err != nil {
 				return err
 			}
-			return output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard)
+			return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr())
 		},
 	}
-	tabsCmd.Flags().StringVar(&browser, "browser", "", "browser: safari or chrome")
+	tabsCmd.Flags().StringVar(&browser, "browser", "", "browser: all (default, every running browser), safari, safari-tp, chrome, chrome-beta, chrome-dev, or chrome-canary")
+	tabsCmd.Flags().BoolVar(&current, "current", false, "list only the frontmost browser's tabs (all browsers, with a warning, when the frontmost app is not a browser)")
+	tabsCmd.Flags().StringVar(&fields, "fields", "", "comma-separated JSON fields to keep (e.g. title,url)")
+	tabsCmd.Flags().BoolVar(&count, "count", false, "print only the number of tabs")
+	tabsCmd.Flags().StringVar(&delimiter, "delimiter", "", delimiterFlagUsage)
+	tabsCmd.Flags().StringVar(&sortOrder, "sort", tabSortIndex, "tab order: index (browser, window, tab) or recent (by extension-reported open times; fails when none are available)")
+	tabsCmd.Flags().BoolVar(&byHost, "by-host", false, "group tab titles by URL host with per-host counts (markdown or json)")
+	tabsCmd.Flags().BoolVar(&noHeaders, "no-headers", false, noHeadersFlagUsage)
+	filter.register(tabsCmd)
+	urls.register(tabsCmd)
+	changes.register(tabsCmd)
 	return tabsCmd
 }
 
+const includeAppPathFlagUsage = "include each app's on-disk bundle path (appPath)"
+
+const noHeadersFlagUsage = "markdown only: omit headings and print just the bullet lines"
+
+// appsForOutput drops AppPath unless --include-app-path is set, so the
+// default output keeps its existing shape.
+func appsForOutput(apps []osascript.AppEntry, options listRenderOptions) []osascript.AppEntry {
+	reorder := options.frontmostFirst || options.appSort == appSortWindowsDesc
+	if options.includeAppPath && !reorder {
+		return apps
+	}
+	prepared := make([]osascript.AppEntry, len(apps))
+	for i, app := range apps {
+		if !options.includeAppPath {
+			app.AppPath = ""
+		}
+		prepared[i] = app
+	}
+	if reorder {
+		sort.SliceStable(prepared, func(i, j int) bool {
+			return appOrderLess(prepared[i], prepared[j], options)
+		})
+	}
+	return prepared
+}
+
 func writeWarnings(stderr io.Writer, warnings []string) {
 	for _, warning := range warnings {
 		fmt.Fprintf(stderr, "warning: %s\n", warning)
@@ -150,48 +436,152 @@ func writeWarnings(stderr io.Writer, warnings []string) {
 }
 
 func newListAppsCommand(global *globalOptions) *cobra.Command {
+	var fields string
+	var count bool
+	var delimiter string
+	var includeAppPath bool
+	var frontmostFirst bool
+	var noHeaders bool
+	var sortOrder string
 	appsCmd := &cobra.Command{
 		Use:   "apps",
 		Short: "Show running desktop apps",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			apps, err := listAppsFunc(cmd.Context())
+			options := listRenderOptions{
+				fields:         parseFieldList(fields),
+				count:          count,
+				delimiter:      delimiter,
+				includeAppPath: includeAppPath,
+				frontmostFirst: frontmostFirst,
+				appSort:        strings.ToLower(strings.TrimSpace(sortOrder)),
+				noHeaders:      noHeaders,
+			}
+			if err := options.validate(global.format, listSelection{apps: true}); err != nil {
+				return usageError(err)
+			}
+
+			apps, warnings, err := listAppsFunc(cmd.Context())
+			writeWarnings(global.warnings(cmd.ErrOrStderr()), warnings)
 			if err != nil {
-				return err
+				return classifyPermissionError(err)
 			}
-			rendered, err := renderApps(global.format, apps)
+			rendered, err := renderApps(global.format, apps, options)
 			if err != nil {
 				return err
 			}
-			return output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard)
+			return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr())
 		},
 	}
+	appsCmd.Flags().StringVar(&fields, "fields", "", "comma-separated JSON fields to keep (e.g. appName,bundleIdentifier)")
+	appsCmd.Flags().BoolVar(&count, "count", false, "print only the number of apps")
+	appsCmd.Flags().StringVar(&delimiter, "delimiter", "", delimiterFlagUsage)
+	appsCmd.Flags().BoolVar(&includeAppPath, "include-app-path", false, includeAppPathFlagUsage)
+	appsCmd.Flags().BoolVar(&frontmostFirst, "frontmost-first", false, "list the frontmost app first")
+	appsCmd.Flags().BoolVar(&noHeaders, "no-headers", false, noHeadersFlagUsage)
+	appsCmd.Flags().StringVar(&sortOrder, "sort", appSortName, "app order: name or windows-desc (most windows first, then name)")
 	return appsCmd
 }
 
-func renderTabs(format string, tabs []osascript.TabEntry) ([]byte, error) {
+func (o listRenderOptions) validate(format string, selection listSelection) error {
+	if o.count && len(o.fields) > 0 {
+		return fmt.Errorf("--count cannot be combined with --fields")
+	}
+	switch o.appSort {
+	case "", appSortName, appSortWindowsDesc:
+	default:
+		return fmt.Errorf("unsupported --sort value %q (expected %s or %s)", o.appSort, appSortName, appSortWindowsDesc)
+	}
+	switch o.tabSort {
+	case "", tabSortIndex, tabSortRecent:
+	default:
+		return fmt.Errorf("unsupported --sort value %q (expected %s or %s)", o.tabSort, tabSortIndex, tabSortRecent)
+	}
+	if o.byHost {
+		if o.count || len(o.fields) > 0 {
+			return fmt.Errorf("--by-host cannot be combined with --count or --fields")
+		}
+		if format != formatJSON && format != formatYAML && format != formatMarkdown {
+			return fmt.Errorf("--by-host supports only --format markdown, json, or yaml")
+		}
+	}
+	if o.noHeaders {
+		if format != formatMarkdown {
+			return fmt.Errorf("--no-headers applies only to --format markdown")
+		}
+		if o.byHost {
+			return fmt.Errorf("--no-headers cannot be combined with --by-host")
+		}
+	}
+	if format == formatMarkdownTable && selection.tabs && selection.apps {
+		return fmt.Errorf("--format %s lists one kind at a time; use list tabs or list apps", format)
+	}
+	if err := validateDelimited(format, o.delimiter, selection); err != nil {
+		return err
+	}
+	return validateListFields(o.fields, format, selection)
+}
+
+// renderJSONAsYAML renders the --format json form with render and converts
+// it to YAML, so both formats always carry the same fields.
+func renderJSONAsYAML(render func(format string) ([]byte, error)) ([]byte, error) {
+	rendered, err := render(formatJSON)
+	if err != nil {
+		return nil, err
+	}
+	return output.JSONToYAML(rendered)
+}
+
+// renderCombinedCount prints tab and app counts as JSON for --format json and
+// as a shell-friendly "tabs=<n> apps=<n>" line otherwise.
+func renderCombinedCount(format string, tabCount int, appCount int) ([]byte, error) {
+	if format == formatJSON {
+		return json.Marshal(struct {
+			Tabs int `json:"tabs"`
+			Apps int `json:"apps"`
+		}{Tabs: tabCount, Apps: appCount})
+	}
+	return []byte(fmt.Sprintf("tabs=%d apps=%d\n", tabCount, appCount)), nil
+}
+
+func renderTabs(format string, tabs []osascript.TabEntry, options listRenderOptions) ([]byte, error) {
+	if format == formatYAML {
+		return renderJSONAsYAML(func(format string) ([]byte, error) {
+			return renderTabs(format, tabs, options)
+		})
+	}
+	if options.count {
+		return []byte(strconv.Itoa(len(tabs)) + "\n"), nil
+	}
+	if options.byHost {
+		return renderTabsByHost(format, tabs)
+	}
 	switch format {
-	case formatJSON:
-		return json.MarshalIndent(tabs, "", "  ")
+	case formatJSON, formatJSONL:
+		view, err := entriesView(tabs, options.fields, tabFieldNames)
+		if err != nil {
+			return nil, err
+		}
+		if format == formatJSONL {
+			return marshalJSONLines(view)
+		}
+		return json.MarshalIndent(view, "", "  ")
+	case formatTSV, formatCSV:
+		return renderDelimited(delimiterFor(format, options.delimiter), tabDelimitedHeader, tabDelimitedRows(tabs))
 	case formatMarkdown:
 		if len(tabs) == 0 {
+			if options.noHeaders {
+				return nil, nil
+			}
 			return []byte("No tabs found.\n"), nil
 		}
 		var lines []string
-		lines = append(lines, "# Open Tabs")
-		sort.SliceStable(tabs, func(i, j int) bool {
-			if tabs[i].Browser != tabs[j].Browser {
-				return tabs[i].Browser < tabs[j].Browser
-			}
-			if tabs[i].WindowIndex != tabs[j].WindowIndex {
-				return tabs[i].WindowIndex < tabs[j].WindowIndex
-			}
-			return tabs[i].TabIndex < tabs[j].TabIndex
-		})
+		if !options.noHeaders {
+			lines = append(lines, "# Open Tabs")
+		}
+		if options.tabSort != tabSortRecent {
+			sortTabsByIndex(tabs)
+		}
 		for _, tab := range tabs {
-			activeLabel := ""
-			if tab.IsActive {
-				activeLabel = " (active)"
-			}
 			lines = append(
 				lines,
 				fmt.Sprintf(
@@ -199,42 +589,121 @@ func renderTabs(format string, tabs []osascript.TabEntry) ([]byte, error) {
 					tab.Browser,
 					tab.WindowIndex,
 					tab.TabIndex,
-					activeLabel,
+					tabStatusLabels(tab),
 					tab.Title,
 					tab.URL,
 				),
 			)
 		}
 		return []byte(strings.Join(lines, "\n") + "\n"), nil
+	case formatMarkdownTable:
+		if len(tabs) == 0 {
+			return []byte("No tabs found.\n"), nil
+		}
+		if options.tabSort != tabSortRecent {
+			sortTabsByIndex(tabs)
+		}
+		return renderMarkdownTable(tabTableHeader, tabTableRows(tabs)), nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
+		return nil, usageError(fmt.Errorf("unsupported format: %s", format))
 	}
 }
 
-func renderApps(format string, apps []osascript.AppEntry) ([]byte, error) {
+// tabStatusLabels returns the " (active)"-style labels markdown output
+// appends to a tab's window and tab index.
+func tabStatusLabels(tab osascript.TabEntry) string {
+	labels := ""
+	if tab.IsActive {
+		labels = " (active)"
+	}
+	if tab.IsPinned {
+		labels += " (pinned)"
+	}
+	if tab.IsLoading {
+		labels += " (loading)"
+	}
+	if tab.IsAudible {
+		labels += " (audible)"
+	}
+	if tab.WebAppName != "" {
+		labels += " (app: " + tab.WebAppName + ")"
+	}
+	return labels
+}
+
+// sortTabsByIndex orders tabs by browser, window, and tab index.
+func sortTabsByIndex(tabs []osascript.TabEntry) {
+	sort.SliceStable(tabs, func(i, j int) bool {
+		if tabs[i].Browser != tabs[j].Browser {
+			return tabs[i].Browser < tabs[j].Browser
+		}
+		if tabs[i].WindowIndex != tabs[j].WindowIndex {
+			return tabs[i].WindowIndex < tabs[j].WindowIndex
+		}
+		return tabs[i].TabIndex < tabs[j].TabIndex
+	})
+}
+
+func renderApps(format string, apps []osascript.AppEntry, options listRenderOptions) ([]byte, error) {
+	if format == formatYAML {
+		return renderJSONAsYAML(func(format string) ([]byte, error) {
+			return renderApps(format, apps, options)
+		})
+	}
+	apps = appsForOutput(apps, options)
+	if options.count {
+		return []byte(strconv.Itoa(len(apps)) + "\n"), nil
+	}
 	switch format {
-	case formatJSON:
-		return json.MarshalIndent(apps, "", "  ")
+	case formatJSON, formatJSONL:
+		view, err := entriesView(apps, options.fields, appFieldNames)
+		if err != nil {
+			return nil, err
+		}
+		if format == formatJSONL {
+			return marshalJSONLines(view)
+		}
+		return json.MarshalIndent(view, "", "  ")
+	case formatTSV, formatCSV:
+		return renderDelimited(
+			delimiterFor(format, options.delimiter),
+			appDelimitedColumns(options.includeAppPath),
+			appDelimitedRows(apps, options.includeAppPath),
+		)
 	case formatMarkdown:
 		if len(apps) == 0 {
+			if options.noHeaders {
+				return nil, nil
+			}
 			return []byte("No desktop apps with windows found.\n"), nil
 		}
 		var lines []string
-		lines = append(lines, "# Running Apps")
+		if !options.noHeaders {
+			lines = append(lines, "# Running Apps")
+		}
 		sort.SliceStable(apps, func(i, j int) bool {
-			if apps[i].AppName != apps[j].AppName {
-				return apps[i].AppName < apps[j].AppName
-			}
-			return apps[i].BundleIdentifier < apps[j].BundleIdentifier
+			return appOrderLess(apps[i], apps[j], options)
 		})
 		for _, app := range apps {
-			lines = append(
-				lines,
-				fmt.Sprintf("- %s (%s) - windows: %d", app.AppName, app.BundleIdentifier, app.WindowCount),
-			)
+			line := fmt.Sprintf("- %s (%s) - windows: %d", app.AppName, app.BundleIdentifier, app.WindowCount)
+			if app.IsFrontmost {
+				line += " (frontmost)"
+			}
+			if app.AppPath != "" {
+				line += " - " + app.AppPath
+			}
+			lines = append(lines, line)
 		}
 		return []byte(strings.Join(lines, "\n") + "\n"), nil
+	case formatMarkdownTable:
+		if len(apps) == 0 {
+			return []byte("No desktop apps with windows found.\n"), nil
+		}
+		sort.SliceStable(apps, func(i, j int) bool {
+			return appOrderLess(apps[i], apps[j], options)
+		})
+		return renderMarkdownTable(appTableHeaderFor(options.includeAppPath), appTableRows(apps, options.includeAppPath)), nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
+		return nil, usageError(fmt.Errorf("unsupported format: %s", format))
 	}
 }
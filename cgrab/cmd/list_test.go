@@ -5,10 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
 )
@@ -44,10 +46,10 @@ func TestListDefaultsToTabsAndAppsJSON(t *testing.T) {
 				{Browser: "safari", WindowIndex: 1, TabIndex: 1, IsActive: true, Title: "Doc", URL: "https://example.com"},
 			}, nil, nil
 		},
-		func(_ context.Context) ([]osascript.AppEntry, error) {
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
 			return []osascript.AppEntry{
 				{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1},
-			}, nil
+			}, nil, nil
 		},
 	)
 	defer restore()
@@ -83,9 +85,9 @@ func TestListTabsOnlySkipsAppsSource(t *testing.T) {
 				{Browser: "chrome", WindowIndex: 1, TabIndex: 1, IsActive: true, Title: "Issue", URL: "https://example.com/issue"},
 			}, nil, nil
 		},
-		func(_ context.Context) ([]osascript.AppEntry, error) {
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
 			appCalls++
-			return nil, nil
+			return nil, nil, nil
 		},
 	)
 	defer restore()
@@ -113,10 +115,10 @@ func TestListAppsOnlySkipsTabsSource(t *testing.T) {
 			tabCalls++
 			return nil, nil, nil
 		},
-		func(_ context.Context) ([]osascript.AppEntry, error) {
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
 			return []osascript.AppEntry{
 				{AppName: "Xcode", BundleIdentifier: "com.apple.dt.Xcode", WindowCount: 2},
-			}, nil
+			}, nil, nil
 		},
 	)
 	defer restore()
@@ -137,15 +139,41 @@ func TestListAppsOnlySkipsTabsSource(t *testing.T) {
 	}
 }
 
+func TestListAppsPrintsSkippedRecordWarnings(t *testing.T) {
+	restore := stubListSources(
+		nil,
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{
+				{AppName: "Xcode", BundleIdentifier: "com.apple.dt.Xcode", WindowCount: 2},
+			}, []string{`skipped Broken app record with invalid window count "many"`}, nil
+		},
+	)
+	defer restore()
+
+	payloadBytes, stderr, err := runRootCommandToFile(t, "list", "apps")
+	if err != nil {
+		t.Fatalf("list apps returned error: %v", err)
+	}
+	if !strings.Contains(string(payloadBytes), "Xcode") {
+		t.Fatalf("expected the parsed app to be listed, got:\n%s", payloadBytes)
+	}
+	if !strings.Contains(stderr, `warning: skipped Broken app record with invalid window count "many"`) {
+		t.Fatalf("expected skipped-record warning on stderr, got %q", stderr)
+	}
+	if _, stderr, err := runRootCommandToFile(t, "--quiet", "list", "apps"); err != nil || stderr != "" {
+		t.Fatalf("expected --quiet to suppress the warning, got %q err=%v", stderr, err)
+	}
+}
+
 func TestListReturnsPartialOutputWithWarningsWhenOneSourceFails(t *testing.T) {
 	restore := stubListSources(
 		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
 			return nil, []string{"safari tabs unavailable: timed out"}, errors.New("unable to enumerate tabs from requested browsers")
 		},
-		func(_ context.Context) ([]osascript.AppEntry, error) {
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
 			return []osascript.AppEntry{
 				{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1},
-			}, nil
+			}, nil, nil
 		},
 	)
 	defer restore()
@@ -166,9 +194,283 @@ func TestListReturnsPartialOutputWithWarningsWhenOneSourceFails(t *testing.T) {
 	}
 }
 
+func TestListJSONEmbedsWarnings(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return nil, []string{"safari tabs unavailable: timed out"}, errors.New("unable to enumerate tabs from requested browsers")
+		},
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1}}, nil, nil
+		},
+	)
+	defer restore()
+
+	payloadBytes, stderr, err := runRootCommandToFile(t, "--format", "json", "list")
+	if err != nil {
+		t.Fatalf("expected partial success, got error: %v", err)
+	}
+	var payload struct {
+		Apps     []osascript.AppEntry `json:"apps"`
+		Warnings []string             `json:"warnings"`
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, payloadBytes)
+	}
+	if len(payload.Apps) != 1 || len(payload.Warnings) != 2 ||
+		payload.Warnings[0] != "safari tabs unavailable: timed out" ||
+		!strings.HasPrefix(payload.Warnings[1], "tabs failed:") {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if stderr != "" {
+		t.Fatalf("expected embedded warnings to stay off stderr, got %q", stderr)
+	}
+
+	payloadBytes, _, err = runRootCommandToFile(t, "--format", "json", "list", "--apps", "--tabs")
+	if err != nil || !strings.Contains(string(payloadBytes), `"warnings": [`) {
+		t.Fatalf("expected a warnings array for an explicit combined listing, err=%v\n%s", err, payloadBytes)
+	}
+}
+
+func TestListNoHeadersPrintsOnlyBullets(t *testing.T) {
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Doc", URL: "https://example.com"}}, nil, nil
+		},
+		func(context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1}}, nil, nil
+		},
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "--no-headers")
+	if err != nil {
+		t.Fatalf("list --no-headers returned error: %v", err)
+	}
+	expected := "- safari w1:t1 - Doc - https://example.com\n- Finder (com.apple.finder) - windows: 1\n"
+	if string(payload) != expected {
+		t.Fatalf("unexpected listing:\n%q", payload)
+	}
+	payload, _, err = runRootCommandToFile(t, "list", "apps", "--no-headers")
+	if err != nil || string(payload) != "- Finder (com.apple.finder) - windows: 1\n" {
+		t.Fatalf("unexpected list apps --no-headers output %q (err=%v)", payload, err)
+	}
+
+	for _, args := range [][]string{
+		{"--format", "json", "list", "tabs", "--no-headers"},
+		{"list", "tabs", "--no-headers", "--by-host"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestListQuietSuppressesWarnings(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return nil, []string{"safari tabs unavailable: timed out"}, errors.New("unable to enumerate tabs from requested browsers")
+		},
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1}}, nil, nil
+		},
+	)
+	defer restore()
+
+	_, stderr, err := runRootCommandToFile(t, "list", "--quiet")
+	if err != nil {
+		t.Fatalf("expected partial success, got error: %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected no stderr with --quiet, got %q", stderr)
+	}
+
+	_, _, err = runRootCommand("list", "tabs", "--quiet")
+	if err == nil {
+		t.Fatalf("expected --quiet to keep returning errors")
+	}
+}
+
+func TestListCombinedJSONLinesTagsRecordTypes(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, IsActive: true, Title: "Doc", URL: "https://example.com"},
+			}, nil, nil
+		},
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{
+				{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1},
+			}, nil, nil
+		},
+	)
+	defer restore()
+
+	payloadBytes, _, err := runRootCommandToFile(t, "list", "--format", "jsonl")
+	if err != nil {
+		t.Fatalf("list returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(payloadBytes)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %d:\n%s", len(lines), string(payloadBytes))
+	}
+	var tabRecord map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &tabRecord); err != nil {
+		t.Fatalf("invalid tab JSON line: %v", err)
+	}
+	if tabRecord["type"] != "tab" || tabRecord["url"] != "https://example.com" {
+		t.Fatalf("unexpected tab record: %v", tabRecord)
+	}
+	var appRecord map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &appRecord); err != nil {
+		t.Fatalf("invalid app JSON line: %v", err)
+	}
+	if appRecord["type"] != "app" || appRecord["appName"] != "Finder" {
+		t.Fatalf("unexpected app record: %v", appRecord)
+	}
+}
+
+func TestRenderTabsJSONLinesEmitsOneObjectPerLine(t *testing.T) {
+	rendered, err := renderTabs(formatJSONL, []osascript.TabEntry{
+		{Browser: "chrome", WindowIndex: 1, TabIndex: 1, Title: "A", URL: "https://a.example"},
+		{Browser: "chrome", WindowIndex: 1, TabIndex: 2, Title: "B", URL: "https://b.example"},
+	}, listRenderOptions{})
+	if err != nil {
+		t.Fatalf("renderTabs returned error: %v", err)
+	}
+	want := "" +
+		`{"browser":"chrome","windowIndex":1,"tabIndex":1,"isActive":false,"title":"A","url":"https://a.example"}` + "\n" +
+		`{"browser":"chrome","windowIndex":1,"tabIndex":2,"isActive":false,"title":"B","url":"https://b.example"}` + "\n"
+	if string(rendered) != want {
+		t.Fatalf("unexpected JSON lines:\nwant: %q\ngot:  %q", want, string(rendered))
+	}
+}
+
+func TestRenderTabsYAMLMatchesJSONFields(t *testing.T) {
+	rendered, err := renderTabs(formatYAML, []osascript.TabEntry{
+		{Browser: "chrome", WindowIndex: 1, TabIndex: 2, Title: "Plan: Q3", URL: "https://a.example"},
+	}, listRenderOptions{fields: []string{"title", "url"}})
+	if err != nil {
+		t.Fatalf("renderTabs returned error: %v", err)
+	}
+	want := "- title: 'Plan: Q3'\n  url: https://a.example\n"
+	if string(rendered) != want {
+		t.Fatalf("unexpected YAML:\nwant: %q\ngot:  %q", want, string(rendered))
+	}
+}
+
+func TestListPrettyFalsePrintsCompactJSON(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Doc", URL: "https://example.com"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "tabs", "--format", "json", "--fields", "title", "--pretty=false")
+	if err != nil {
+		t.Fatalf("list tabs returned error: %v", err)
+	}
+	if string(payload) != `[{"title":"Doc"}]`+"\n" {
+		t.Fatalf("expected compact JSON, got %q", payload)
+	}
+}
+
+func TestRenderTabsMarkdownAnnotatesLoadingTabs(t *testing.T) {
+	rendered, err := renderTabs(formatMarkdown, []osascript.TabEntry{
+		{Browser: "chrome", WindowIndex: 1, TabIndex: 1, IsActive: true, IsLoading: true, Title: "A", URL: "https://a.example"},
+	}, listRenderOptions{})
+	if err != nil {
+		t.Fatalf("renderTabs returned error: %v", err)
+	}
+	if !strings.Contains(string(rendered), "- chrome w1:t1 (active) (loading) - A - https://a.example") {
+		t.Fatalf("expected loading annotation, got %q", string(rendered))
+	}
+}
+
+func TestListTabsFieldsProjectsJSON(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, IsActive: true, Title: "Doc", URL: "https://example.com"},
+			}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	payloadBytes, _, err := runRootCommandToFile(t, "list", "tabs", "--format", "json", "--fields", "title,url")
+	if err != nil {
+		t.Fatalf("list tabs returned error: %v", err)
+	}
+	var payload []map[string]any
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		t.Fatalf("invalid JSON payload: %v\noutput:\n%s", err, string(payloadBytes))
+	}
+	if len(payload) != 1 || len(payload[0]) != 2 {
+		t.Fatalf("expected one entry with two fields, got %v", payload)
+	}
+	if payload[0]["title"] != "Doc" || payload[0]["url"] != "https://example.com" {
+		t.Fatalf("unexpected projected entry: %v", payload[0])
+	}
+}
+
+func TestListFieldsRejectsUnknownField(t *testing.T) {
+	_, _, err := runRootCommand("list", "apps", "--format", "json", "--fields", "appName,url")
+	if err == nil {
+		t.Fatalf("expected unknown field to fail")
+	}
+	if !strings.Contains(err.Error(), "bundleIdentifier") {
+		t.Fatalf("expected error to list valid fields, got %v", err)
+	}
+}
+
+func TestListFieldsRequiresJSONFormat(t *testing.T) {
+	_, _, err := runRootCommand("list", "tabs", "--fields", "title")
+	if err == nil {
+		t.Fatalf("expected --fields with markdown to fail")
+	}
+}
+
+func TestRunListWatchAppendsTimestampedSnapshotsUntilCanceled(t *testing.T) {
+	previousNowFunc := nowFunc
+	t.Cleanup(func() {
+		nowFunc = previousNowFunc
+	})
+	nowFunc = func() time.Time {
+		return time.Date(2026, time.February, 15, 13, 30, 45, 0, time.UTC)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	renders := 0
+	var stdout bytes.Buffer
+	err := runListWatch(ctx, &stdout, io.Discard, time.Millisecond, func(context.Context) ([]byte, error) {
+		renders++
+		if renders == 3 {
+			cancel()
+		}
+		return []byte("# Open Tabs\n"), nil
+	})
+	if err != nil {
+		t.Fatalf("runListWatch returned error: %v", err)
+	}
+	if got := strings.Count(stdout.String(), "<!-- snapshot 2026-02-15T13:30:45Z -->"); got != 2 {
+		t.Fatalf("expected 2 snapshots before cancellation, got %d:\n%s", got, stdout.String())
+	}
+}
+
+func TestListWatchRejectsJSONFormat(t *testing.T) {
+	_, _, err := runRootCommand("list", "--watch", "--format", "json")
+	if err == nil {
+		t.Fatalf("expected --watch with json to fail")
+	}
+}
+
 func stubListSources(
 	tabs func(context.Context, string) ([]osascript.TabEntry, []string, error),
-	apps func(context.Context) ([]osascript.AppEntry, error),
+	apps func(context.Context) ([]osascript.AppEntry, []string, error),
 ) func() {
 	previousTabs := listTabsFunc
 	previousApps := listAppsFunc
@@ -205,3 +507,420 @@ func runRootCommandToFile(t *testing.T, args ...string) ([]byte, string, error)
 	}
 	return payload, stderr, nil
 }
+
+func TestListLogFileRecordsStartAndEndEvents(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Doc", URL: "https://example.com"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	logPath := filepath.Join(t.TempDir(), "cgrab.jsonl")
+	if _, _, err := runRootCommandToFile(t, "list", "--tabs", "--log-file", logPath); err != nil {
+		t.Fatalf("list returned error: %v", err)
+	}
+
+	raw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 events, got %q", string(raw))
+	}
+	var end map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &end); err != nil {
+		t.Fatalf("invalid event %q: %v", lines[1], err)
+	}
+	if end["event"] != "list_end" || end["tabCount"] != float64(1) || end["ok"] != true {
+		t.Fatalf("unexpected list_end event: %v", end)
+	}
+	if !strings.Contains(lines[0], `"event":"list_start"`) {
+		t.Fatalf("expected list_start event first, got %q", lines[0])
+	}
+}
+
+func TestListTabsOnlyHTTPDropsNonWebTabs(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Start Page", URL: ""},
+				{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "Local", URL: "file:///tmp/a.html"},
+				{Browser: "safari", WindowIndex: 1, TabIndex: 3, Title: "Doc", URL: "https://example.com"},
+			}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	for _, args := range [][]string{
+		{"list", "tabs", "--only-http", "--format", "json"},
+		{"list", "--tabs", "--only-http", "--format", "json"},
+	} {
+		payloadBytes, _, err := runRootCommandToFile(t, args...)
+		if err != nil {
+			t.Fatalf("%v returned error: %v", args, err)
+		}
+		payload := string(payloadBytes)
+		if strings.Contains(payload, "Start Page") || strings.Contains(payload, "file:///") {
+			t.Fatalf("%v: expected non-http tabs filtered, got %s", args, payload)
+		}
+		if !strings.Contains(payload, "https://example.com") {
+			t.Fatalf("%v: expected http tab kept, got %s", args, payload)
+		}
+	}
+}
+
+func TestListTabsCurrentFiltersToFrontmostBrowser(t *testing.T) {
+	var requested []string
+	restore := stubListSources(
+		func(_ context.Context, browser string) ([]osascript.TabEntry, []string, error) {
+			requested = append(requested, browser)
+			return nil, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	restoreFrontmost := stubFrontmostApp(osascript.AppEntry{AppName: "Google Chrome", BundleIdentifier: "com.google.Chrome"}, nil)
+	_, stderr, err := runRootCommandToFile(t, "list", "tabs", "--current")
+	restoreFrontmost()
+	if err != nil {
+		t.Fatalf("list tabs --current returned error: %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected no warnings, got %q", stderr)
+	}
+
+	restoreFrontmost = stubFrontmostApp(osascript.AppEntry{AppName: "Finder", BundleIdentifier: "com.apple.finder"}, nil)
+	_, stderr, err = runRootCommandToFile(t, "list", "tabs", "--current")
+	restoreFrontmost()
+	if err != nil {
+		t.Fatalf("list tabs --current returned error: %v", err)
+	}
+	if !strings.Contains(stderr, "--current: the frontmost app is not a supported browser; listing tabs from all browsers") {
+		t.Fatalf("expected a fallback warning, got %q", stderr)
+	}
+	if len(requested) != 2 || requested[0] != "chrome" || requested[1] != "" {
+		t.Fatalf("unexpected browser filters: %q", requested)
+	}
+
+	if _, _, err := runRootCommand("list", "tabs", "--current", "--browser", "safari"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected --current with --browser to be a usage error, got %v", err)
+	}
+}
+
+func TestListCountPrintsBareNumbers(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, URL: "https://example.com"},
+				{Browser: "safari", WindowIndex: 1, TabIndex: 2, URL: "https://other.test"},
+			}, nil, nil
+		},
+		func(_ context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1}}, nil, nil
+		},
+	)
+	defer restore()
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"list", "tabs", "--count", "--format", "json"}, want: "2\n"},
+		{args: []string{"list", "tabs", "--count", "--include-domain", "example.com"}, want: "1\n"},
+		{args: []string{"list", "apps", "--count"}, want: "1\n"},
+		{args: []string{"list", "--count"}, want: "tabs=2 apps=1\n"},
+		{args: []string{"list", "--count", "--format", "json"}, want: `{"tabs":2,"apps":1}`},
+	}
+	for _, tc := range cases {
+		payload, _, err := runRootCommandToFile(t, tc.args...)
+		if err != nil {
+			t.Fatalf("%v returned error: %v", tc.args, err)
+		}
+		if string(payload) != tc.want {
+			t.Fatalf("%v: want %q, got %q", tc.args, tc.want, string(payload))
+		}
+	}
+
+	if _, _, err := runRootCommand("list", "tabs", "--count", "--fields", "url", "--format", "json"); err == nil {
+		t.Fatalf("expected --count with --fields to be rejected")
+	}
+}
+
+func TestListTabsRendersTSVWithHeader(t *testing.T) {
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "Docs, v2", URL: "https://example.com/docs"},
+			}, nil, nil
+		},
+		func(context.Context) ([]osascript.AppEntry, []string, error) {
+			return nil, nil, nil
+		},
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "tabs", "--format", "tsv")
+	if err != nil {
+		t.Fatalf("list tabs --format tsv returned error: %v", err)
+	}
+	want := "browser\twindow\ttab\ttitle\turl\n" +
+		"safari\t1\t2\tDocs, v2\thttps://example.com/docs\n"
+	if string(payload) != want {
+		t.Fatalf("unexpected tsv output:\nwant: %q\ngot:  %q", want, string(payload))
+	}
+}
+
+func TestListAppsRendersCSVWithCustomDelimiter(t *testing.T) {
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return nil, nil, nil
+		},
+		func(context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder; Files", BundleIdentifier: "com.apple.finder", WindowCount: 2}}, nil, nil
+		},
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "apps", "--format", "csv", "--delimiter", ";")
+	if err != nil {
+		t.Fatalf("list apps --format csv returned error: %v", err)
+	}
+	want := "appName;bundleIdentifier;windowCount\n" +
+		"\"Finder; Files\";com.apple.finder;2\n"
+	if string(payload) != want {
+		t.Fatalf("unexpected csv output:\nwant: %q\ngot:  %q", want, string(payload))
+	}
+}
+
+func TestListMarkdownTableEscapesCells(t *testing.T) {
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "A | B", URL: "https://example.com/?q=a|b"},
+				{Browser: "chrome", WindowIndex: 1, TabIndex: 1, IsActive: true, Title: "Docs", URL: "https://docs.example"},
+			}, nil, nil
+		},
+		func(context.Context) ([]osascript.AppEntry, []string, error) {
+			return []osascript.AppEntry{{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 2, IsFrontmost: true}}, nil, nil
+		},
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "tabs", "--format", "markdown-table")
+	if err != nil {
+		t.Fatalf("list tabs --format markdown-table returned error: %v", err)
+	}
+	want := "| Browser | W:T            | Title  | URL                         |\n" +
+		"| ------- | -------------- | ------ | --------------------------- |\n" +
+		"| chrome  | w1:t1 (active) | Docs   | https://docs.example        |\n" +
+		"| safari  | w1:t2          | A \\| B | https://example.com/?q=a\\|b |\n"
+	if string(payload) != want {
+		t.Fatalf("unexpected table:\nwant: %q\ngot:  %q", want, string(payload))
+	}
+
+	payload, _, err = runRootCommandToFile(t, "list", "apps", "--format", "markdown-table")
+	if err != nil {
+		t.Fatalf("list apps --format markdown-table returned error: %v", err)
+	}
+	want = "| App                | Bundle ID        | Windows |\n" +
+		"| ------------------ | ---------------- | ------- |\n" +
+		"| Finder (frontmost) | com.apple.finder | 2       |\n"
+	if string(payload) != want {
+		t.Fatalf("unexpected table:\nwant: %q\ngot:  %q", want, string(payload))
+	}
+
+	if _, _, err := runRootCommand("list", "--format", "markdown-table"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected a combined markdown-table listing to be a usage error, got %v", err)
+	}
+}
+
+func TestListDelimitedFormatValidation(t *testing.T) {
+	cases := [][]string{
+		{"list", "--format", "csv"},
+		{"list", "tabs", "--format", "tsv", "--delimiter", ";"},
+		{"list", "tabs", "--format", "csv", "--delimiter", "ab"},
+	}
+	for _, args := range cases {
+		_, _, err := runRootCommand(args...)
+		if code := ExitCode(err); code != ExitCodeUsage {
+			t.Fatalf("args=%v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestListTabsEnvelopeWrapsJSON(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Doc", URL: "https://example.com"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "tabs", "--format", "json", "--envelope")
+	if err != nil {
+		t.Fatalf("list tabs --envelope returned error: %v", err)
+	}
+	var envelope struct {
+		Command     string            `json:"command"`
+		Format      string            `json:"format"`
+		GeneratedAt string            `json:"generatedAt"`
+		Data        []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		t.Fatalf("expected envelope JSON, got %q: %v", payload, err)
+	}
+	if envelope.Command != "list tabs" || envelope.Format != formatJSON || envelope.GeneratedAt == "" {
+		t.Fatalf("unexpected envelope metadata: %+v", envelope)
+	}
+	if len(envelope.Data) != 1 {
+		t.Fatalf("expected one tab under data, got %s", payload)
+	}
+}
+
+func TestEnvelopeRequiresJSONFormat(t *testing.T) {
+	_, _, err := runRootCommand("list", "tabs", "--envelope")
+	if err == nil || !strings.Contains(err.Error(), "--envelope requires --format json") {
+		t.Fatalf("expected --envelope format error, got %v", err)
+	}
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage exit code, got %d", ExitCode(err))
+	}
+}
+
+func TestRenderTabsByHostGroupsTitles(t *testing.T) {
+	tabs := []osascript.TabEntry{
+		{Browser: "safari", Title: "Docs", URL: "https://go.dev/doc"},
+		{Browser: "chrome", Title: "Issue", URL: "https://github.com/a/b/issues/1"},
+		{Browser: "chrome", Title: "Blank", URL: "about:blank"},
+		{Browser: "safari", Title: "PR", URL: "https://GitHub.com/a/b/pull/2"},
+	}
+
+	markdown, err := renderTabs(formatMarkdown, tabs, listRenderOptions{byHost: true})
+	if err != nil {
+		t.Fatalf("renderTabs returned error: %v", err)
+	}
+	want := "# Tabs by Host\n\n## github.com (2)\n- Issue\n- PR\n\n## (no host) (1)\n- Blank\n\n## go.dev (1)\n- Docs\n"
+	if string(markdown) != want {
+		t.Fatalf("unexpected markdown:\n%s", markdown)
+	}
+
+	payload, err := renderTabs(formatJSON, tabs, listRenderOptions{byHost: true})
+	if err != nil {
+		t.Fatalf("renderTabs returned error: %v", err)
+	}
+	var byHost map[string][]string
+	if err := json.Unmarshal(payload, &byHost); err != nil {
+		t.Fatalf("expected JSON object, got %q: %v", payload, err)
+	}
+	if len(byHost["github.com"]) != 2 || byHost["go.dev"][0] != "Docs" {
+		t.Fatalf("unexpected grouping: %v", byHost)
+	}
+}
+
+func TestListTabsByHostRejectsDelimitedFormats(t *testing.T) {
+	_, _, err := runRootCommand("list", "tabs", "--by-host", "--format", "csv")
+	if err == nil || !strings.Contains(err.Error(), "--by-host") {
+		t.Fatalf("expected --by-host format error, got %v", err)
+	}
+}
+
+func TestListAppsIncludeAppPath(t *testing.T) {
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{
+			{AppName: "Notes", BundleIdentifier: "com.apple.Notes", WindowCount: 1, AppPath: "/System/Applications/Notes.app"},
+		}, nil, nil
+	})
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "apps", "--format", "json")
+	if err != nil {
+		t.Fatalf("list apps returned error: %v", err)
+	}
+	if strings.Contains(string(payload), "appPath") {
+		t.Fatalf("expected appPath to be omitted by default, got %s", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "list", "apps", "--include-app-path")
+	if err != nil {
+		t.Fatalf("list apps --include-app-path returned error: %v", err)
+	}
+	if !strings.Contains(string(payload), "- Notes (com.apple.Notes) - windows: 1 - /System/Applications/Notes.app") {
+		t.Fatalf("expected app path in markdown, got %s", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "list", "apps", "--include-app-path", "--format", "tsv")
+	if err != nil {
+		t.Fatalf("list apps --include-app-path --format tsv returned error: %v", err)
+	}
+	if !strings.HasPrefix(string(payload), "appName\tbundleIdentifier\twindowCount\tappPath\n") {
+		t.Fatalf("expected appPath column, got %q", payload)
+	}
+}
+
+func TestListAppsFrontmostFirstMarksAndSortsFrontmostApp(t *testing.T) {
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{
+			{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1},
+			{AppName: "Terminal", BundleIdentifier: "com.apple.Terminal", WindowCount: 2, IsFrontmost: true},
+		}, nil, nil
+	})
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "apps", "--frontmost-first")
+	if err != nil {
+		t.Fatalf("list apps returned error: %v", err)
+	}
+	want := "# Running Apps\n- Terminal (com.apple.Terminal) - windows: 2 (frontmost)\n- Finder (com.apple.finder) - windows: 1\n"
+	if string(payload) != want {
+		t.Fatalf("unexpected markdown:\n%s", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "list", "apps", "--format", "json", "--fields", "appName,isFrontmost", "--frontmost-first")
+	if err != nil {
+		t.Fatalf("list apps --format json returned error: %v", err)
+	}
+	if !strings.HasPrefix(strings.Join(strings.Fields(string(payload)), ""), `[{"appName":"Terminal","isFrontmost":true}`) {
+		t.Fatalf("expected frontmost app first in JSON, got %s", payload)
+	}
+}
+
+func TestListAppsSortWindowsDescOrdersByWindowCount(t *testing.T) {
+	restore := stubListSources(nil, func(context.Context) ([]osascript.AppEntry, []string, error) {
+		return []osascript.AppEntry{
+			{AppName: "Finder", BundleIdentifier: "com.apple.finder", WindowCount: 1},
+			{AppName: "Notes", BundleIdentifier: "com.apple.Notes", WindowCount: 3},
+			{AppName: "Safari", BundleIdentifier: "com.apple.Safari", WindowCount: 3},
+		}, nil, nil
+	})
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "apps", "--sort", "windows-desc")
+	if err != nil {
+		t.Fatalf("list apps --sort windows-desc returned error: %v", err)
+	}
+	want := "# Running Apps\n" +
+		"- Notes (com.apple.Notes) - windows: 3\n" +
+		"- Safari (com.apple.Safari) - windows: 3\n" +
+		"- Finder (com.apple.finder) - windows: 1\n"
+	if string(payload) != want {
+		t.Fatalf("unexpected markdown:\n%s", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "list", "apps", "--format", "json", "--fields", "appName", "--sort", "windows-desc")
+	if err != nil {
+		t.Fatalf("list apps --format json returned error: %v", err)
+	}
+	if got := strings.Join(strings.Fields(string(payload)), ""); got != `[{"appName":"Notes"},{"appName":"Safari"},{"appName":"Finder"}]` {
+		t.Fatalf("expected JSON in window order, got %s", got)
+	}
+
+	if _, _, err := runRootCommand("list", "apps", "--sort", "windows"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for unknown --sort, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newListOpenCommand(global *globalOptions) *cobra.Command {
+	var browser string
+	openCmd := &cobra.Command{
+		Use:   "open <w:t>",
+		Short: "Focus a listed tab without capturing it",
+		Long: "Focus the tab a list tabs reference (e.g. w1:t2) points at, bringing its browser to the front. " +
+			"Without --browser, a reference open in several browsers resolves to the frontmost one.",
+		Example: "  cgrab list open w1:t2 --browser safari\n" +
+			"  cgrab list open 2:5",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if global.format != formatMarkdown && global.format != formatJSON {
+				return usageError(fmt.Errorf("list open supports only --format markdown or json"))
+			}
+			target, err := parseOptionalBrowserTarget(strings.TrimSpace(browser))
+			if err != nil {
+				return usageError(err)
+			}
+			request := captureRequest{tabReference: strings.TrimSpace(args[0])}
+			tab, err := resolveTargetTab(cmd.Context(), request, target, global.warnings(cmd.ErrOrStderr()))
+			if err != nil {
+				return err
+			}
+			if err := activateTabFunc(cmd.Context(), tab.Browser, tab.WindowIndex, tab.TabIndex); err != nil {
+				return classifyPermissionError(fmt.Errorf(
+					"failed to activate %s tab w%d:t%d: %w",
+					tab.Browser,
+					tab.WindowIndex,
+					tab.TabIndex,
+					err,
+				))
+			}
+
+			if global.format == formatJSON {
+				rendered, err := json.MarshalIndent(tab, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", rendered)
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Opened %s w%d:t%d - %s - %s\n", tab.Browser, tab.WindowIndex, tab.TabIndex, tab.Title, tab.URL)
+			return err
+		},
+	}
+	openCmd.Flags().StringVar(&browser, "browser", "", "browser the reference belongs to: safari, safari-tp, chrome, chrome-beta, chrome-dev, or chrome-canary")
+	return openCmd
+}
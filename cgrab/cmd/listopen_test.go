@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestListOpenActivatesReferencedTab(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+		return []osascript.TabEntry{
+			{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "Docs", URL: "https://docs.example"},
+			{Browser: "chrome", WindowIndex: 1, TabIndex: 2, Title: "Mail", URL: "https://mail.example"},
+		}, nil, nil
+	}, nil)
+	defer restore()
+	var activated []string
+	activateTabFunc = func(_ context.Context, browser string, windowIndex int, tabIndex int) error {
+		activated = append(activated, fmt.Sprintf("%s w%d:t%d", browser, windowIndex, tabIndex))
+		return nil
+	}
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		t.Fatalf("list open should not capture")
+		return bridge.BrowserCaptureAttempt{}, nil
+	}
+
+	stdout, _, err := runRootCommand("list", "open", "w1:t2", "--browser", "safari")
+	if err != nil {
+		t.Fatalf("list open returned error: %v", err)
+	}
+	if stdout != "Opened safari w1:t2 - Docs - https://docs.example\n" {
+		t.Fatalf("unexpected output %q", stdout)
+	}
+	if len(activated) != 1 || activated[0] != "safari w1:t2" {
+		t.Fatalf("unexpected activations: %v", activated)
+	}
+
+	cases := []struct {
+		args []string
+		code int
+	}{
+		{args: []string{"list", "open", "w1:t2"}, code: ExitCodeUsage},
+		{args: []string{"list", "open", "w3:t1", "--browser", "safari"}, code: ExitCodeNoMatch},
+		{args: []string{"list", "open", "first"}, code: ExitCodeUsage},
+		{args: []string{"list", "open", "w1:t2", "--browser", "opera"}, code: ExitCodeUsage},
+	}
+	for _, tc := range cases {
+		if _, _, err := runRootCommand(tc.args...); ExitCode(err) != tc.code {
+			t.Fatalf("args %v: expected exit code %d, got %v", tc.args, tc.code, err)
+		}
+	}
+	if len(activated) != 1 {
+		t.Fatalf("expected failed lookups not to activate a tab, got %v", activated)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+var (
+	tabTableHeader = []string{"Browser", "W:T", "Title", "URL"}
+	appTableHeader = []string{"App", "Bundle ID", "Windows"}
+)
+
+// markdownTableCell escapes a value for a GitHub-flavored table cell: pipes
+// would end the cell and line breaks the row.
+func markdownTableCell(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", " ", "\n", " ", "\r", " ").Replace(value)
+}
+
+// renderMarkdownTable renders a GitHub-flavored table with every column
+// padded to its widest cell, so the source reads as aligned as the preview.
+func renderMarkdownTable(header []string, rows [][]string) []byte {
+	escaped := make([][]string, 0, len(rows)+1)
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = markdownTableCell(cell)
+		}
+		escaped = append(escaped, cells)
+	}
+	widths := make([]int, len(header))
+	for i := range widths {
+		widths[i] = 3
+	}
+	for _, row := range escaped {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var builder strings.Builder
+	writeRow := func(cells []string) {
+		builder.WriteString("|")
+		for i, cell := range cells {
+			builder.WriteString(" " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " |")
+		}
+		builder.WriteString("\n")
+	}
+	writeRow(escaped[0])
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("-", width)
+	}
+	writeRow(separator)
+	for _, row := range escaped[1:] {
+		writeRow(row)
+	}
+	return []byte(builder.String())
+}
+
+// tabTableRows returns one markdown-table row per tab, with the markdown
+// listing's status labels after the window and tab index.
+func tabTableRows(tabs []osascript.TabEntry) [][]string {
+	rows := make([][]string, 0, len(tabs))
+	for _, tab := range tabs {
+		position := fmt.Sprintf("w%d:t%d%s", tab.WindowIndex, tab.TabIndex, tabStatusLabels(tab))
+		rows = append(rows, []string{tab.Browser, position, tab.Title, tab.URL})
+	}
+	return rows
+}
+
+// appTableHeaderFor returns the app table header, with a Path column for
+// --include-app-path.
+func appTableHeaderFor(includeAppPath bool) []string {
+	if !includeAppPath {
+		return appTableHeader
+	}
+	return append(append([]string{}, appTableHeader...), "Path")
+}
+
+func appTableRows(apps []osascript.AppEntry, includeAppPath bool) [][]string {
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		name := app.AppName
+		if app.IsFrontmost {
+			name += " (frontmost)"
+		}
+		row := []string{name, app.BundleIdentifier, strconv.Itoa(app.WindowCount)}
+		if includeAppPath {
+			row = append(row, app.AppPath)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// savedCaptureOutput is the JSON shape used when a markdown capture is
+// re-emitted with --format json.
+type savedCaptureOutput struct {
+	Source   string `json:"source"`
+	Markdown string `json:"markdown"`
+}
+
+func newCaptureLastCommand(global *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:     "last",
+		Short:   "Reprint the most recent auto-saved text capture",
+		Example: "  cgrab capture last\n  cgrab capture last --clipboard",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			captureDir, err := resolveCaptureDir()
+			if err != nil {
+				return err
+			}
+			entries, err := scanCaptureHistory(captureDir)
+			if err != nil {
+				return err
+			}
+			// PDF captures cannot be reprinted, so the newest text capture
+			// counts as the last one.
+			for _, entry := range entries {
+				if entry.Format != historyFormatPDF {
+					return replaySavedCapture(cmd, global, entry.Path)
+				}
+			}
+			return noMatchError(fmt.Errorf("no saved captures to reprint in %s", captureDir))
+		},
+	}
+}
+
+func newCaptureOpenCommand(global *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "open <file>",
+		Short: "Reprint a saved capture",
+		Long: "Reprint a saved capture. Bare file names are resolved in the capture " +
+			"directory; paths are used as given.",
+		Example: "  cgrab capture open capture-20260215-133045.123.md\n" +
+			"  cgrab capture open ./notes/capture.json --format markdown",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := strings.TrimSpace(args[0])
+			if !filepath.IsAbs(path) && !strings.ContainsRune(path, filepath.Separator) {
+				captureDir, err := resolveCaptureDir()
+				if err != nil {
+					return err
+				}
+				path = filepath.Join(captureDir, path)
+			}
+			return replaySavedCapture(cmd, global, path)
+		},
+	}
+}
+
+func replaySavedCapture(cmd *cobra.Command, global *globalOptions, path string) error {
+	if savedCaptureExtension(path) == captureOutputExtension(formatMarkdown, browserMethodURLPDF) {
+		return usageError(fmt.Errorf("saved capture %s is a PDF and cannot be reprinted; open it with a PDF viewer", path))
+	}
+	raw, err := readSavedCapture(path)
+	if err != nil {
+		return err
+	}
+
+	rendered, format := raw, savedCaptureFormat(path)
+	if formatFlag := cmd.Flag("format"); formatFlag != nil && formatFlag.Changed {
+		rendered, err = convertSavedCapture(path, raw, global.format)
+		if err != nil {
+			return err
+		}
+		format = global.format
+	}
+	return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(format)...), cmd.ErrOrStderr())
+}
+
+// readSavedCapture reads path, decompressing files saved with --gzip.
+func readSavedCapture(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, noMatchError(fmt.Errorf("saved capture not found: %s", path))
+		}
+		return nil, fmt.Errorf("read saved capture: %w", err)
+	}
+	if !strings.EqualFold(filepath.Ext(path), gzipExtension) {
+		return raw, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decompress saved capture: %w", err)
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("decompress saved capture: %w", err)
+	}
+	return decompressed, nil
+}
+
+// savedCaptureExtension returns the lower-case extension of a saved capture,
+// ignoring a trailing .gz.
+func savedCaptureExtension(path string) string {
+	return strings.ToLower(filepath.Ext(strings.TrimSuffix(path, gzipExtension)))
+}
+
+// savedCaptureFormat returns the format a saved capture was written in,
+// judged by its extension.
+func savedCaptureFormat(path string) string {
+	switch savedCaptureExtension(path) {
+	case ".json":
+		return formatJSON
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+	return formatMarkdown
+}
+
+// convertSavedCapture re-renders a saved capture in format. JSON and YAML
+// captures can be rendered as markdown when they carry a "markdown" field,
+// and as each other; markdown captures are wrapped in a small JSON (or YAML)
+// object.
+func convertSavedCapture(path string, raw []byte, format string) ([]byte, error) {
+	stored := savedCaptureFormat(path)
+	if stored == formatYAML && format != formatYAML {
+		converted, err := output.YAMLToJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parse saved capture %s: %w", path, err)
+		}
+		raw, stored = converted, formatJSON
+	}
+
+	switch format {
+	case formatMarkdown:
+		if stored == formatMarkdown {
+			return raw, nil
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, fmt.Errorf("parse saved capture %s: %w", path, err)
+		}
+		markdown, ok := decoded["markdown"].(string)
+		if !ok {
+			return nil, fmt.Errorf("saved capture %s has no markdown content to render", path)
+		}
+		if !strings.HasSuffix(markdown, "\n") {
+			markdown += "\n"
+		}
+		return []byte(markdown), nil
+	case formatJSON:
+		if stored == formatJSON {
+			return raw, nil
+		}
+		return json.MarshalIndent(savedCaptureOutput{Source: path, Markdown: string(raw)}, "", "  ")
+	case formatYAML:
+		if stored == formatYAML {
+			return raw, nil
+		}
+		converted, err := convertSavedCapture(path, raw, formatJSON)
+		if err != nil {
+			return nil, err
+		}
+		return output.JSONToYAML(converted)
+	default:
+		return nil, usageError(fmt.Errorf("saved captures support --format json, yaml, or markdown, got %q", format))
+	}
+}
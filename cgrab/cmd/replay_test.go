@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+)
+
+func TestCaptureLastReprintsNewestCapture(t *testing.T) {
+	captureDir := setupCaptureHistory(t,
+		"capture-20260215-100000.000.md",
+		"capture-20260214-100000.000.md",
+	)
+	if err := os.WriteFile(filepath.Join(captureDir, "capture-20260215-100000.000.md"), []byte("# Newest\n"), 0o644); err != nil {
+		t.Fatalf("write capture: %v", err)
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "last")
+	if err != nil {
+		t.Fatalf("capture last returned error: %v", err)
+	}
+	if string(payload) != "# Newest\n" {
+		t.Fatalf("unexpected replayed capture: %q", string(payload))
+	}
+}
+
+func TestCaptureLastWithoutCapturesIsNoMatch(t *testing.T) {
+	setupCaptureHistory(t)
+
+	_, _, err := runRootCommand("capture", "last")
+	if code := ExitCode(err); code != ExitCodeNoMatch {
+		t.Fatalf("expected exit code %d, got %d (err=%v)", ExitCodeNoMatch, code, err)
+	}
+}
+
+func TestCaptureOpenConvertsBetweenFormats(t *testing.T) {
+	captureDir := setupCaptureHistory(t)
+	jsonCapture := filepath.Join(captureDir, "capture-20260215-100000.000.json")
+	if err := os.WriteFile(jsonCapture, []byte(`{"target":"safari","markdown":"# From JSON"}`), 0o644); err != nil {
+		t.Fatalf("write capture: %v", err)
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "open", filepath.Base(jsonCapture), "--format", "markdown")
+	if err != nil {
+		t.Fatalf("capture open returned error: %v", err)
+	}
+	if string(payload) != "# From JSON\n" {
+		t.Fatalf("unexpected markdown rendering: %q", string(payload))
+	}
+
+	markdownCapture := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(markdownCapture, []byte("# Notes\n"), 0o644); err != nil {
+		t.Fatalf("write capture: %v", err)
+	}
+	payload, _, err = runRootCommandToFile(t, "capture", "open", markdownCapture, "--format", "json")
+	if err != nil {
+		t.Fatalf("capture open returned error: %v", err)
+	}
+	var decoded savedCaptureOutput
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", string(payload), err)
+	}
+	if decoded.Markdown != "# Notes\n" || !strings.HasSuffix(decoded.Source, "notes.md") {
+		t.Fatalf("unexpected JSON rendering: %#v", decoded)
+	}
+}
+
+func TestCaptureLastReadsGzipCaptures(t *testing.T) {
+	captureDir := setupCaptureHistory(t)
+	path := filepath.Join(captureDir, "capture-20260215-100000.000.md.gz")
+	if err := output.Write(context.Background(), []byte("# Compressed\n"), path, false); err != nil {
+		t.Fatalf("write gzip capture: %v", err)
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "last")
+	if err != nil {
+		t.Fatalf("capture last returned error: %v", err)
+	}
+	if string(payload) != "# Compressed\n" {
+		t.Fatalf("unexpected replayed capture: %q", string(payload))
+	}
+}
+
+func TestSavedYAMLAndPDFCapturesAppearInHistory(t *testing.T) {
+	captureDir := setupCaptureHistory(t,
+		"capture-20260215-090000.000.yaml",
+		"capture-20260215-100000.000.pdf",
+	)
+	yamlCapture := filepath.Join(captureDir, "capture-20260215-090000.000.yaml")
+	if err := os.WriteFile(yamlCapture, []byte("target: safari\nmarkdown: |\n  # From YAML\n"), 0o644); err != nil {
+		t.Fatalf("write capture: %v", err)
+	}
+
+	payload, _, err := runRootCommandToFile(t, "history", "list", "--format", "json")
+	if err != nil {
+		t.Fatalf("history list returned error: %v", err)
+	}
+	var entries []historyEntry
+	if err := json.Unmarshal(payload, &entries); err != nil {
+		t.Fatalf("invalid JSON %q: %v", payload, err)
+	}
+	if len(entries) != 2 || entries[0].Format != historyFormatPDF || entries[1].Format != formatYAML {
+		t.Fatalf("unexpected history entries: %#v", entries)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "capture", "last", "--format", "markdown")
+	if err != nil {
+		t.Fatalf("capture last returned error: %v", err)
+	}
+	if string(payload) != "# From YAML\n" {
+		t.Fatalf("expected capture last to skip the newer PDF, got %q", payload)
+	}
+	_, _, err = runRootCommand("capture", "open", "capture-20260215-100000.000.pdf")
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(err.Error(), "is a PDF") {
+		t.Fatalf("expected capture open on a PDF to be a usage error, got %v", err)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "capture", "open", filepath.Base(yamlCapture), "--format", "markdown")
+	if err != nil {
+		t.Fatalf("capture open returned error: %v", err)
+	}
+	if string(payload) != "# From YAML\n" {
+		t.Fatalf("unexpected markdown rendering: %q", payload)
+	}
+	payload, _, err = runRootCommandToFile(t, "capture", "open", filepath.Base(yamlCapture), "--format", "json")
+	if err != nil {
+		t.Fatalf("capture open returned error: %v", err)
+	}
+	if string(payload) != "{\n  \"target\": \"safari\",\n  \"markdown\": \"# From YAML\\n\"\n}\n" {
+		t.Fatalf("unexpected JSON rendering: %q", payload)
+	}
+
+	if err := os.Remove(yamlCapture); err != nil {
+		t.Fatalf("remove capture: %v", err)
+	}
+	if _, _, err := runRootCommand("capture", "last"); ExitCode(err) != ExitCodeNoMatch {
+		t.Fatalf("expected only PDF captures to be no match, got %v", err)
+	}
+}
@@ -1,15 +1,29 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/eventlog"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
 	"github.com/spf13/cobra"
 )
 
-func initRootHelp(rootCmd *cobra.Command) {
+func initRootHelp(rootCmd *cobra.Command, opts *globalOptions) {
 	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
-		if cmd == rootCmd {
+		if applyColorMode(opts.color, cmd.OutOrStderr()) != nil {
+			// An invalid --color value is reported when a command runs;
+			// help still renders with automatic detection.
+			_ = applyColorMode(colorAuto, cmd.OutOrStderr())
+		}
+		if cmd == rootCmd && shouldShowProductCard(cmd.OutOrStderr(), opts.noCard) {
 			fmt.Fprintln(cmd.OutOrStderr(), buildProductCard(detectCardWidth(cmd.OutOrStderr())))
 			fmt.Fprintln(cmd.OutOrStderr())
 		}
@@ -18,8 +32,13 @@ func initRootHelp(rootCmd *cobra.Command) {
 }
 
 const (
-	formatJSON     = "json"
-	formatMarkdown = "markdown"
+	formatJSON          = "json"
+	formatJSONL         = "jsonl"
+	formatMarkdown      = "markdown"
+	formatMarkdownTable = "markdown-table"
+	formatTSV           = "tsv"
+	formatCSV           = "csv"
+	formatYAML          = "yaml"
 )
 
 // Version is injected at build-time via -ldflags.
@@ -28,12 +47,63 @@ var Version = "dev"
 type globalOptions struct {
 	outputFile string
 	clipboard  bool
-	format     string
+	// requireClipboard keeps a failed clipboard copy fatal instead of a
+	// warning.
+	requireClipboard bool
+	format           string
+	quiet            bool
+	verbose          bool
+	logFile          string
+	gzip             bool
+	noCard           bool
+	color            string
+	envelope         bool
+	pretty           bool
+	// deadline bounds the whole command; cancelDeadline releases its timer.
+	deadline       time.Duration
+	cancelDeadline func()
+	// command is the invoked command path without the root name (e.g.
+	// "list tabs"), recorded for --envelope.
+	command string
+}
+
+// writeOptions returns the output.Write options for a payload rendered in
+// format, which may differ from --format (e.g. capture --raw is always JSON).
+// Only JSON output is compacted, and only without --pretty.
+func (o *globalOptions) writeOptions(format string) []output.Option {
+	options := []output.Option{output.WithGzip(o.gzip), output.WithCompactJSON(!o.pretty && format == formatJSON)}
+	if o.envelope {
+		options = append(options, output.WithEnvelope(o.command, format, nowFunc().UTC()))
+	}
+	return options
+}
+
+// clipboardResult downgrades a failed clipboard copy from output.Write to a
+// warning, since file and stdout output were already written. With
+// --require-clipboard the failure is returned as is.
+func (o *globalOptions) clipboardResult(err error, stderr io.Writer) error {
+	var warning *output.ClipboardWarning
+	if o.requireClipboard || !errors.As(err, &warning) {
+		return err
+	}
+	fmt.Fprintf(o.warnings(stderr), "warning: %v\n", warning)
+	return nil
+}
+
+// warnings returns w, or io.Discard when --quiet is set. Use it for warnings
+// and status lines; errors are always reported.
+func (o *globalOptions) warnings(w io.Writer) io.Writer {
+	if o.quiet {
+		return io.Discard
+	}
+	return w
 }
 
 func defaultGlobalOptions() *globalOptions {
 	return &globalOptions{
 		format: formatMarkdown,
+		color:  colorAuto,
+		pretty: true,
 	}
 }
 
@@ -47,16 +117,44 @@ func newRootCommand() *cobra.Command {
 		Example:       "  cgrab list tabs --browser safari\n  cgrab capture --focused\n  cgrab config show\n  cgrab docs",
 		SilenceUsage:  true,
 		SilenceErrors: true,
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			var verboseLog io.Writer
+			if opts.verbose {
+				verboseLog = cmd.ErrOrStderr()
+			}
+			osascript.SetVerboseLog(verboseLog)
+			bridge.SetVerboseLog(verboseLog)
+			if err := applyColorMode(strings.ToLower(strings.TrimSpace(opts.color)), cmd.OutOrStdout()); err != nil {
+				return usageError(err)
+			}
+			if logFile := strings.TrimSpace(opts.logFile); logFile != "" {
+				cmd.SetContext(eventlog.WithLogger(cmd.Context(), eventlog.New(logFile)))
+			}
+
+			if err := opts.startDeadline(cmd); err != nil {
+				return err
+			}
+
+			opts.command = strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+
 			switch opts.format {
-			case formatJSON, formatMarkdown:
-				return nil
+			case formatJSON, formatJSONL, formatMarkdown, formatMarkdownTable, formatTSV, formatCSV, formatYAML:
 			default:
-				return fmt.Errorf("unsupported --format value %q (expected json or markdown)", opts.format)
+				return usageError(fmt.Errorf("unsupported --format value %q (expected json, jsonl, yaml, markdown, markdown-table, tsv, or csv)", opts.format))
+			}
+			if opts.envelope && opts.format != formatJSON {
+				return usageError(fmt.Errorf("--envelope requires --format json"))
 			}
+			if opts.requireClipboard && !opts.clipboard {
+				return usageError(fmt.Errorf("--require-clipboard requires --clipboard"))
+			}
+			return nil
 		},
 	}
 
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return usageError(err)
+	})
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.SetErr(os.Stderr)
 	rootCmd.Version = Version
@@ -73,26 +171,101 @@ func newRootCommand() *cobra.Command {
 		false,
 		"copy output to clipboard",
 	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.requireClipboard,
+		"require-clipboard",
+		false,
+		"with --clipboard, fail when the clipboard copy fails instead of warning",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.gzip,
+		"gzip",
+		false,
+		"gzip-compress --file output (implied when the file name ends in .gz)",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.quiet,
+		"quiet",
+		false,
+		"suppress warnings and status messages",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.verbose,
+		"verbose",
+		false,
+		"log osascript and bridge commands to stderr",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&opts.logFile,
+		"log-file",
+		"",
+		"append structured JSON Lines events (capture/list start, end, errors) to a file",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&opts.color,
+		"color",
+		colorAuto,
+		"styled output: auto (off for NO_COLOR or non-terminal output), always, or never",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.envelope,
+		"envelope",
+		false,
+		`with --format json, wrap output as {"command","format","generatedAt","data"}`,
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.pretty,
+		"pretty",
+		true,
+		"indent JSON output; --pretty=false prints compact single-line JSON",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&opts.deadline,
+		"deadline",
+		0,
+		"fail the command if it has not finished within this duration (e.g. 30s); 0 disables",
+	)
+	rootCmd.Flags().BoolVar(
+		&opts.noCard,
+		"no-card",
+		false,
+		"print plain help without the product card (also CONTEXT_GRABBER_NO_CARD=1)",
+	)
 	rootCmd.PersistentFlags().StringVar(
 		&opts.format,
 		"format",
 		formatMarkdown,
-		"output format: json or markdown",
+		"output format: json, jsonl, tsv, csv, markdown-table (list only), yaml (list, doctor, capture, config show, update), or markdown",
 	)
 
 	rootCmd.AddCommand(newListCommand(opts))
 	rootCmd.AddCommand(newCaptureCommand(opts))
+	rootCmd.AddCommand(newAppsCommand(opts))
 	rootCmd.AddCommand(newDoctorCommand(opts))
-	rootCmd.AddCommand(newConfigCommand())
+	rootCmd.AddCommand(newHistoryCommand(opts))
+	rootCmd.AddCommand(newConfigCommand(opts))
+	rootCmd.AddCommand(newUpdateCommand(opts))
 	rootCmd.AddCommand(newDocsCommand())
 	rootCmd.AddCommand(newSkillsCommand())
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	applyCommandStyle(rootCmd)
-	initRootHelp(rootCmd)
+	applyDeadline(rootCmd, opts)
+	applyUsageArgs(rootCmd)
+	initRootHelp(rootCmd, opts)
 
 	return rootCmd
 }
 
+// Execute runs the root command without signal handling; it is kept for
+// callers that predate ExecuteContext.
 func Execute() error {
-	return newRootCommand().Execute()
+	return ExecuteContext(context.Background())
+}
+
+// ExecuteContext runs the root command with ctx as every command's
+// context. main cancels ctx on SIGINT/SIGTERM so in-flight osascript, bridge,
+// and host app calls stop while deferred cleanups (closing --url tabs) still
+// run; the resulting error exits with ExitCodeInterrupted.
+func ExecuteContext(ctx context.Context) error {
+	return interruptedResult(ctx, newRootCommand().ExecuteContext(ctx))
 }
@@ -2,8 +2,17 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestDefaultGlobalOptionsReturnsIndependentInstances(t *testing.T) {
@@ -64,7 +73,20 @@ func TestRootCommandShortName(t *testing.T) {
 	}
 }
 
+func stubOutputIsTerminal(t *testing.T, terminal bool) {
+	t.Helper()
+	previous := outputIsTerminalFunc
+	outputIsTerminalFunc = func(io.Writer) bool {
+		return terminal
+	}
+	t.Cleanup(func() {
+		outputIsTerminalFunc = previous
+	})
+}
+
 func TestRootHelpIncludesProductCard(t *testing.T) {
+	stubOutputIsTerminal(t, true)
+	t.Setenv(noCardEnvVar, "")
 	command := newRootCommand()
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -85,6 +107,40 @@ func TestRootHelpIncludesProductCard(t *testing.T) {
 	}
 }
 
+func TestRootHelpOmitsProductCardWhenSuppressed(t *testing.T) {
+	cases := []struct {
+		name     string
+		terminal bool
+		env      string
+		args     []string
+	}{
+		{name: "flag", terminal: true, args: []string{"--help", "--no-card"}},
+		{name: "env", terminal: true, env: "1", args: []string{"--help"}},
+		{name: "non-tty", terminal: false, args: []string{"--help"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stubOutputIsTerminal(t, tc.terminal)
+			t.Setenv(noCardEnvVar, tc.env)
+			command := newRootCommand()
+			var stdout bytes.Buffer
+			command.SetOut(&stdout)
+			command.SetErr(&stdout)
+			command.SetArgs(tc.args)
+
+			if err := command.Execute(); err != nil {
+				t.Fatalf("root help returned error: %v", err)
+			}
+			if strings.Contains(stdout.String(), "base_dir") {
+				t.Fatalf("expected plain usage without product card:\n%s", stdout.String())
+			}
+			if !strings.Contains(stdout.String(), "Usage:") {
+				t.Fatalf("expected usage in help output:\n%s", stdout.String())
+			}
+		})
+	}
+}
+
 func TestSubcommandHelpOmitsProductCard(t *testing.T) {
 	command := newRootCommand()
 	var stdout bytes.Buffer
@@ -122,3 +178,127 @@ func TestBuildProductCardUsesBorderOnWideWidths(t *testing.T) {
 		t.Fatalf("expected bordered card for wide width:\n%s", rendered)
 	}
 }
+
+func TestApplyColorModeControlsANSIOutput(t *testing.T) {
+	previous := lipgloss.ColorProfile()
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(previous)
+	})
+	stubOutputIsTerminal(t, true)
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+
+	cases := []struct {
+		mode      string
+		noColor   string
+		wantColor bool
+	}{
+		{mode: colorAlways, wantColor: true},
+		{mode: colorNever, wantColor: false},
+		{mode: colorAuto, noColor: "1", wantColor: false},
+		{mode: colorAlways, noColor: "1", wantColor: true},
+	}
+	for _, tc := range cases {
+		t.Setenv("NO_COLOR", tc.noColor)
+		if err := applyColorMode(tc.mode, io.Discard); err != nil {
+			t.Fatalf("applyColorMode(%q) returned error: %v", tc.mode, err)
+		}
+		rendered := style.Render("x")
+		if hasColor := strings.Contains(rendered, "\x1b["); hasColor != tc.wantColor {
+			t.Fatalf("mode=%q NO_COLOR=%q: expected color=%v, got %q", tc.mode, tc.noColor, tc.wantColor, rendered)
+		}
+	}
+}
+
+func TestApplyColorModeDisablesColorForNonTerminalOutput(t *testing.T) {
+	previous := lipgloss.ColorProfile()
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(previous)
+	})
+	stubOutputIsTerminal(t, false)
+	t.Setenv("NO_COLOR", "")
+
+	if err := applyColorMode(colorAuto, io.Discard); err != nil {
+		t.Fatalf("applyColorMode returned error: %v", err)
+	}
+	rendered := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")).Render("x")
+	if rendered != "x" {
+		t.Fatalf("expected plain output for non-terminal writer, got %q", rendered)
+	}
+}
+
+func TestRootCommandRejectsUnknownColorMode(t *testing.T) {
+	_, _, err := runRootCommand("config", "show", "--color", "sometimes")
+	if err == nil || !strings.Contains(err.Error(), "--color") {
+		t.Fatalf("expected --color error, got %v", err)
+	}
+	if code := ExitCode(err); code != ExitCodeUsage {
+		t.Fatalf("expected usage exit code, got %d", code)
+	}
+}
+
+func TestClipboardResultDowngradesClipboardFailures(t *testing.T) {
+	warning := &output.ClipboardWarning{Err: errors.New("pbcopy not found")}
+	options := defaultGlobalOptions()
+	var stderr bytes.Buffer
+	if err := options.clipboardResult(warning, &stderr); err != nil {
+		t.Fatalf("expected clipboard failure downgraded to a warning, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "warning: clipboard copy failed: pbcopy not found") {
+		t.Fatalf("unexpected warning output %q", stderr.String())
+	}
+
+	options.requireClipboard = true
+	if err := options.clipboardResult(warning, &stderr); err == nil {
+		t.Fatalf("expected --require-clipboard to keep the failure")
+	}
+	if err := defaultGlobalOptions().clipboardResult(errors.New("write output file: denied"), &stderr); err == nil {
+		t.Fatalf("expected non-clipboard errors to pass through")
+	}
+
+	_, _, err := runRootCommand("list", "--require-clipboard")
+	if code := ExitCode(err); code != ExitCodeUsage {
+		t.Fatalf("expected usage error for --require-clipboard without --clipboard, got %v", err)
+	}
+}
+
+func TestWriteOptionsCompactOnlyJSONOutput(t *testing.T) {
+	payload := []byte("{\n  \"a\": 1\n}\n")
+	dir := t.TempDir()
+	cases := map[string]string{
+		formatJSON: "{\"a\":1}\n",
+		formatYAML: string(payload),
+	}
+	options := defaultGlobalOptions()
+	options.pretty = false
+	for format, expected := range cases {
+		path := filepath.Join(dir, "out."+format)
+		if err := output.Write(context.Background(), payload, path, false, options.writeOptions(format)...); err != nil {
+			t.Fatalf("Write(%s) returned error: %v", format, err)
+		}
+		written, _ := os.ReadFile(path)
+		if string(written) != expected {
+			t.Fatalf("%s output: expected %q, got %q", format, expected, written)
+		}
+	}
+}
+
+func TestDeadlineTimesOutHungCommand(t *testing.T) {
+	restore := stubListSources(
+		func(ctx context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			<-ctx.Done()
+			return nil, nil, errors.New("osascript: signal: killed")
+		},
+		nil,
+	)
+	defer restore()
+
+	_, _, err := runRootCommand("list", "tabs", "--browser", "safari", "--deadline", "20ms")
+	if err == nil || !strings.Contains(err.Error(), "timed out after --deadline 20ms") {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+
+	_, _, err = runRootCommand("list", "tabs", "--deadline", "-1s")
+	if err == nil || ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for negative deadline, got %v", err)
+	}
+}
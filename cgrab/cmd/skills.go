@@ -35,13 +35,13 @@ When Bun is available, launches the interactive installer with support for
 Claude Code, OpenCode, and Cursor. When Bun is unavailable, falls back to
 the embedded installer (Claude Code and OpenCode only; Cursor requires Bun
 for .mdc format conversion).`,
-		Example: "  cgrab skills install\n  cgrab skills install --agent claude --scope project\n  cgrab skills install --agent claude --agent opencode --scope global",
+		Example: "  cgrab skills install\n  cgrab skills install --agent claude --scope project\n  cgrab skills install --agent claude --agent opencode --scope global\n  cgrab skills install --agent claude:global --agent opencode:project",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runSkillsAction(cmd, agentFlag, scopeFlag, false)
 		},
 	}
 
-	cmd.Flags().StringSliceVar(&agentFlag, "agent", nil, "agent targets: claude, opencode, cursor")
+	cmd.Flags().StringSliceVar(&agentFlag, "agent", nil, "agent targets: claude, opencode, cursor (optionally agent:scope, e.g. claude:global)")
 	cmd.Flags().StringVar(&scopeFlag, "scope", "global", "install scope: global or project")
 	return cmd
 }
@@ -60,7 +60,7 @@ func newSkillsUninstallCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringSliceVar(&agentFlag, "agent", nil, "agent targets: claude, opencode, cursor")
+	cmd.Flags().StringSliceVar(&agentFlag, "agent", nil, "agent targets: claude, opencode, cursor (optionally agent:scope, e.g. claude:global)")
 	cmd.Flags().StringVar(&scopeFlag, "scope", "global", "install scope: global or project")
 	return cmd
 }
@@ -109,31 +109,78 @@ func runBunInstaller(
 	if uninstall {
 		args = append(args, "--uninstall")
 	}
-	if agentFlagChanged {
-		agents := normalizeAgentValues(agentFlag)
-		for _, agent := range agents {
-			args = append(args, "--agent", agent)
-		}
-	}
+	defaultScope := ""
 	if scopeFlagChanged {
-		args = append(args, "--scope", strings.TrimSpace(scopeFlag))
+		defaultScope = strings.TrimSpace(scopeFlag)
 	}
-	if agentFlagChanged || scopeFlagChanged {
-		// Explicit flags indicate non-interactive intent.
-		args = append(args, "--yes")
+	// The TS installer takes a single --scope, so agents with per-agent
+	// scopes run as one invocation per distinct scope.
+	groups := []bunAgentGroup{{scope: defaultScope}}
+	if agentFlagChanged {
+		groups = groupAgentsByScope(normalizeAgentValues(agentFlag), defaultScope)
 	}
 
-	proc := exec.Command(bunPath, args...)
-	proc.Stdin = os.Stdin
-	proc.Stdout = cmd.OutOrStdout()
-	proc.Stderr = cmd.ErrOrStderr()
+	for _, group := range groups {
+		groupArgs := append([]string{}, args...)
+		for _, agent := range group.agents {
+			groupArgs = append(groupArgs, "--agent", agent)
+		}
+		if group.scope != "" {
+			groupArgs = append(groupArgs, "--scope", group.scope)
+		}
+		if agentFlagChanged || scopeFlagChanged {
+			// Explicit flags indicate non-interactive intent.
+			groupArgs = append(groupArgs, "--yes")
+		}
+
+		proc := exec.Command(bunPath, groupArgs...)
+		proc.Stdin = os.Stdin
+		proc.Stdout = cmd.OutOrStdout()
+		proc.Stderr = cmd.ErrOrStderr()
 
-	if err := proc.Run(); err != nil {
-		return fmt.Errorf("interactive installer failed: %w", err)
+		if err := proc.Run(); err != nil {
+			return fmt.Errorf("interactive installer failed: %w", err)
+		}
 	}
 	return nil
 }
 
+type bunAgentGroup struct {
+	scope  string
+	agents []string
+}
+
+// groupAgentsByScope splits normalized --agent values (agent or agent:scope)
+// into per-scope groups, keeping first-seen order.
+func groupAgentsByScope(agents []string, defaultScope string) []bunAgentGroup {
+	var groups []bunAgentGroup
+	for _, value := range agents {
+		agent, scope := splitAgentScope(value)
+		if scope == "" {
+			scope = defaultScope
+		}
+		index := -1
+		for i := range groups {
+			if groups[i].scope == scope {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			groups = append(groups, bunAgentGroup{scope: scope})
+			index = len(groups) - 1
+		}
+		groups[index].agents = append(groups[index].agents, agent)
+	}
+	return groups
+}
+
+// splitAgentScope splits an --agent value of the form agent[:scope].
+func splitAgentScope(value string) (string, string) {
+	agent, scope, _ := strings.Cut(value, ":")
+	return strings.TrimSpace(agent), strings.TrimSpace(scope)
+}
+
 func normalizeAgentValues(agentFlag []string) []string {
 	seen := make(map[string]bool)
 	var agents []string
@@ -157,7 +204,7 @@ func runEmbeddedInstaller(cmd *cobra.Command, agentFlag []string, scopeFlag stri
 		return err
 	}
 
-	agents, err := resolveAgents(agentFlag)
+	targets, err := resolveAgents(agentFlag, scope)
 	if err != nil {
 		return err
 	}
@@ -176,13 +223,18 @@ func runEmbeddedInstaller(cmd *cobra.Command, agentFlag []string, scopeFlag stri
 	}
 
 	var results []skills.InstallResult
-	if uninstall {
-		results, err = skills.Uninstall(agents, scope, cwd)
-	} else {
-		results, err = skills.Install(agents, scope, cwd)
-	}
-	if err != nil {
-		return err
+	for _, target := range targets {
+		agents := []skills.AgentTarget{target.agent}
+		var targetResults []skills.InstallResult
+		if uninstall {
+			targetResults, err = skills.Uninstall(agents, target.scope, cwd)
+		} else {
+			targetResults, err = skills.Install(agents, target.scope, cwd)
+		}
+		if err != nil {
+			return err
+		}
+		results = append(results, targetResults...)
 	}
 
 	fmt.Fprintln(w)
@@ -213,15 +265,18 @@ func runEmbeddedInstaller(cmd *cobra.Command, agentFlag []string, scopeFlag stri
 	return nil
 }
 
-// resolveAgents parses the --agent flag values into validated AgentTargets.
-// If no agents specified, defaults to all embedded agents.
-func resolveAgents(agentFlag []string) ([]skills.AgentTarget, error) {
-	if len(agentFlag) == 0 {
-		return skills.EmbeddedAgents, nil
-	}
+// agentInstall is one agent together with the scope to install it in.
+type agentInstall struct {
+	agent skills.AgentTarget
+	scope skills.InstallScope
+}
 
-	seen := make(map[skills.AgentTarget]bool)
-	var agents []skills.AgentTarget
+// resolveAgents parses the --agent flag values into validated agent/scope
+// pairs. Values may carry their own scope (claude:global); others use
+// defaultScope. If no agents specified, defaults to all embedded agents.
+func resolveAgents(agentFlag []string, defaultScope skills.InstallScope) ([]agentInstall, error) {
+	seen := make(map[agentInstall]bool)
+	var targets []agentInstall
 	for _, raw := range agentFlag {
 		// Support comma-separated: --agent claude,opencode
 		for _, s := range strings.Split(raw, ",") {
@@ -229,22 +284,32 @@ func resolveAgents(agentFlag []string) ([]skills.AgentTarget, error) {
 			if s == "" {
 				continue
 			}
-			agent, err := skills.ValidateAgent(s)
+			name, rawScope := splitAgentScope(s)
+			agent, err := skills.ValidateAgent(name)
 			if err != nil {
 				return nil, err
 			}
-			if seen[agent] {
+			scope := defaultScope
+			if rawScope != "" {
+				if scope, err = skills.ValidateScope(rawScope); err != nil {
+					return nil, err
+				}
+			}
+			target := agentInstall{agent: agent, scope: scope}
+			if seen[target] {
 				continue
 			}
-			seen[agent] = true
-			agents = append(agents, agent)
+			seen[target] = true
+			targets = append(targets, target)
 		}
 	}
 
-	if len(agents) == 0 {
-		return skills.EmbeddedAgents, nil
+	if len(targets) == 0 {
+		for _, agent := range skills.EmbeddedAgents {
+			targets = append(targets, agentInstall{agent: agent, scope: defaultScope})
+		}
 	}
-	return agents, nil
+	return targets, nil
 }
 
 // agentLabel returns a display-friendly name for an agent target.
@@ -11,7 +11,7 @@ import (
 )
 
 func TestResolveAgents_Defaults(t *testing.T) {
-	agents, err := resolveAgents(nil)
+	agents, err := resolveAgents(nil, skills.ScopeGlobal)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -21,30 +21,30 @@ func TestResolveAgents_Defaults(t *testing.T) {
 }
 
 func TestResolveAgents_Explicit(t *testing.T) {
-	agents, err := resolveAgents([]string{"claude"})
+	agents, err := resolveAgents([]string{"claude"}, skills.ScopeGlobal)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(agents) != 1 || agents[0] != skills.AgentClaude {
+	if len(agents) != 1 || agents[0].agent != skills.AgentClaude {
 		t.Fatalf("expected [claude], got %v", agents)
 	}
 }
 
 func TestResolveAgents_CommaSeparated(t *testing.T) {
-	agents, err := resolveAgents([]string{"claude,opencode"})
+	agents, err := resolveAgents([]string{"claude,opencode"}, skills.ScopeGlobal)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if len(agents) != 2 {
 		t.Fatalf("expected 2 agents, got %d", len(agents))
 	}
-	if agents[0] != skills.AgentClaude || agents[1] != skills.AgentOpenCode {
+	if agents[0].agent != skills.AgentClaude || agents[1].agent != skills.AgentOpenCode {
 		t.Fatalf("expected [claude, opencode], got %v", agents)
 	}
 }
 
 func TestResolveAgents_Invalid(t *testing.T) {
-	_, err := resolveAgents([]string{"cursor"})
+	_, err := resolveAgents([]string{"cursor"}, skills.ScopeGlobal)
 	if err == nil {
 		t.Fatal("expected error for cursor in embedded fallback")
 	}
@@ -54,7 +54,7 @@ func TestResolveAgents_Invalid(t *testing.T) {
 }
 
 func TestResolveAgents_Empty(t *testing.T) {
-	agents, err := resolveAgents([]string{""})
+	agents, err := resolveAgents([]string{""}, skills.ScopeGlobal)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,6 +64,37 @@ func TestResolveAgents_Empty(t *testing.T) {
 	}
 }
 
+func TestResolveAgents_PerAgentScope(t *testing.T) {
+	agents, err := resolveAgents([]string{"claude:global", "opencode:project"}, skills.ScopeProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []agentInstall{
+		{agent: skills.AgentClaude, scope: skills.ScopeGlobal},
+		{agent: skills.AgentOpenCode, scope: skills.ScopeProject},
+	}
+	if len(agents) != len(want) || agents[0] != want[0] || agents[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, agents)
+	}
+
+	if _, err := resolveAgents([]string{"claude:everywhere"}, skills.ScopeGlobal); err == nil {
+		t.Fatal("expected error for invalid per-agent scope")
+	}
+}
+
+func TestGroupAgentsByScope(t *testing.T) {
+	groups := groupAgentsByScope([]string{"claude:global", "cursor", "opencode:project"}, "project")
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 scope groups, got %v", groups)
+	}
+	if groups[0].scope != "global" || len(groups[0].agents) != 1 || groups[0].agents[0] != "claude" {
+		t.Fatalf("unexpected global group: %v", groups[0])
+	}
+	if groups[1].scope != "project" || strings.Join(groups[1].agents, ",") != "cursor,opencode" {
+		t.Fatalf("unexpected project group: %v", groups[1])
+	}
+}
+
 func TestSkillsInstallEmbeddedFallback(t *testing.T) {
 	tmpDir := t.TempDir()
 	cwd := filepath.Join(tmpDir, "project")
@@ -1,6 +1,20 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
 
 const styledUsageTemplate = `{{if .Long}}{{.Long}}
 
@@ -31,3 +45,24 @@ func applyCommandStyle(command *cobra.Command) {
 		applyCommandStyle(child)
 	}
 }
+
+// applyColorMode sets the lipgloss color profile for styled output. In auto
+// mode styling is dropped when NO_COLOR is set or out is not a terminal, so
+// ANSI codes never leak into redirected files.
+func applyColorMode(mode string, out io.Writer) error {
+	switch mode {
+	case colorAlways:
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case colorNever:
+		lipgloss.SetColorProfile(termenv.Ascii)
+	case colorAuto, "":
+		if os.Getenv("NO_COLOR") != "" || !outputIsTerminalFunc(out) {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		} else {
+			lipgloss.SetColorProfile(termenv.EnvColorProfile())
+		}
+	default:
+		return fmt.Errorf("unsupported --color value %q (expected auto, always, or never)", mode)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/spf13/cobra"
+)
+
+// tabChangeOptions holds list tabs --changed-since and --show-closed.
+type tabChangeOptions struct {
+	changedSince string
+	showClosed   bool
+}
+
+func (o *tabChangeOptions) register(command *cobra.Command) {
+	command.Flags().StringVar(&o.changedSince, "changed-since", "", "only list tabs whose URL is not in this earlier list tabs --format json snapshot")
+	command.Flags().BoolVar(&o.showClosed, "show-closed", false, "with --changed-since, also list snapshot tabs whose URL is no longer open")
+}
+
+func (o tabChangeOptions) validate(format string, options listRenderOptions) error {
+	if o.showClosed && o.changedSince == "" {
+		return fmt.Errorf("--show-closed requires --changed-since")
+	}
+	if !o.showClosed {
+		return nil
+	}
+	if format != formatMarkdown && format != formatJSON && format != formatYAML {
+		return fmt.Errorf("--show-closed supports only --format markdown, json, or yaml")
+	}
+	if options.count || options.byHost || options.noHeaders || len(options.fields) > 0 {
+		return fmt.Errorf("--show-closed cannot be combined with --count, --by-host, --fields, or --no-headers")
+	}
+	return nil
+}
+
+// tabChanges is the --show-closed JSON form: tabs opened since the snapshot
+// and snapshot tabs that are gone.
+type tabChanges struct {
+	Opened []osascript.TabEntry `json:"opened"`
+	Closed []osascript.TabEntry `json:"closed"`
+}
+
+// diffTabsByURL compares two tab listings as sets of URLs: opened holds the
+// current tabs whose URL is not in previous, closed the previous tabs whose
+// URL is not in current, each in listing order.
+func diffTabsByURL(previous []osascript.TabEntry, current []osascript.TabEntry) (opened []osascript.TabEntry, closed []osascript.TabEntry) {
+	previousURLs := make(map[string]bool, len(previous))
+	for _, tab := range previous {
+		previousURLs[tab.URL] = true
+	}
+	currentURLs := make(map[string]bool, len(current))
+	for _, tab := range current {
+		currentURLs[tab.URL] = true
+	}
+	opened = []osascript.TabEntry{}
+	for _, tab := range current {
+		if !previousURLs[tab.URL] {
+			opened = append(opened, tab)
+		}
+	}
+	closed = []osascript.TabEntry{}
+	for _, tab := range previous {
+		if !currentURLs[tab.URL] {
+			closed = append(closed, tab)
+		}
+	}
+	return opened, closed
+}
+
+// loadTabSnapshot reads a list tabs --format json snapshot. It also accepts
+// the --envelope form and a combined list ({"tabs": [...]}).
+func loadTabSnapshot(path string) ([]osascript.TabEntry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read --changed-since snapshot: %w", err)
+	}
+	raw = bytes.TrimSpace(raw)
+	if bytes.HasPrefix(raw, []byte("{")) {
+		var wrapper struct {
+			Data json.RawMessage      `json:"data"`
+			Tabs []osascript.TabEntry `json:"tabs"`
+		}
+		if err := json.Unmarshal(raw, &wrapper); err != nil {
+			return nil, fmt.Errorf("decode --changed-since snapshot %s: %w", path, err)
+		}
+		if len(wrapper.Data) == 0 {
+			return wrapper.Tabs, nil
+		}
+		raw = wrapper.Data
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			if err := json.Unmarshal(raw, &wrapper); err != nil {
+				return nil, fmt.Errorf("decode --changed-since snapshot %s: %w", path, err)
+			}
+			return wrapper.Tabs, nil
+		}
+	}
+	var tabs []osascript.TabEntry
+	if err := json.Unmarshal(raw, &tabs); err != nil {
+		return nil, fmt.Errorf("decode --changed-since snapshot %s (expected list tabs --format json output): %w", path, err)
+	}
+	return tabs, nil
+}
+
+// renderTabChanges renders --show-closed output: the opened tabs followed
+// by a "# Closed Tabs" section in markdown, or a tabChanges object.
+func renderTabChanges(format string, opened []osascript.TabEntry, closed []osascript.TabEntry, options listRenderOptions) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		return json.MarshalIndent(tabChanges{Opened: opened, Closed: closed}, "", "  ")
+	case formatYAML:
+		return renderJSONAsYAML(func(format string) ([]byte, error) {
+			return renderTabChanges(format, opened, closed, options)
+		})
+	}
+	openedRendered, err := renderTabs(format, opened, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(closed) == 0 {
+		return append(openedRendered, []byte("\n# Closed Tabs\nNo closed tabs.\n")...), nil
+	}
+	closedRendered, err := renderTabs(format, closed, options)
+	if err != nil {
+		return nil, err
+	}
+	closedSection := strings.Replace(string(closedRendered), "# Open Tabs", "# Closed Tabs", 1)
+	return append(openedRendered, []byte("\n"+closedSection)...), nil
+}
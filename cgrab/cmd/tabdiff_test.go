@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestDiffTabsByURL(t *testing.T) {
+	previous := []osascript.TabEntry{
+		{Browser: "safari", TabIndex: 1, URL: "https://a.example"},
+		{Browser: "safari", TabIndex: 2, URL: "https://b.example"},
+		{Browser: "chrome", TabIndex: 1, URL: "https://b.example"},
+	}
+	current := []osascript.TabEntry{
+		{Browser: "chrome", TabIndex: 1, URL: "https://c.example"},
+		{Browser: "chrome", TabIndex: 2, URL: "https://b.example"},
+		{Browser: "chrome", TabIndex: 3, URL: "https://d.example"},
+	}
+
+	opened, closed := diffTabsByURL(previous, current)
+	if !reflect.DeepEqual(opened, []osascript.TabEntry{current[0], current[2]}) {
+		t.Fatalf("unexpected opened tabs: %+v", opened)
+	}
+	if !reflect.DeepEqual(closed, []osascript.TabEntry{previous[0]}) {
+		t.Fatalf("unexpected closed tabs: %+v", closed)
+	}
+
+	opened, closed = diffTabsByURL(nil, nil)
+	if opened == nil || closed == nil || len(opened) != 0 || len(closed) != 0 {
+		t.Fatalf("expected empty non-nil results, got %v and %v", opened, closed)
+	}
+}
+
+func TestLoadTabSnapshotAcceptsEnvelopeAndCombinedList(t *testing.T) {
+	dir := t.TempDir()
+	tab := `{"browser":"safari","windowIndex":1,"tabIndex":1,"isActive":false,"title":"A","url":"https://a.example"}`
+	for name, content := range map[string]string{
+		"plain.json":    "[" + tab + "]",
+		"envelope.json": `{"schemaVersion":1,"data":[` + tab + `]}`,
+		"combined.json": `{"tabs":[` + tab + `],"apps":[]}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+		tabs, err := loadTabSnapshot(path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(tabs) != 1 || tabs[0].URL != "https://a.example" {
+			t.Fatalf("%s: unexpected tabs %+v", name, tabs)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("# Open Tabs\n"), 0o600); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	if _, err := loadTabSnapshot(bad); err == nil || !strings.Contains(err.Error(), "list tabs --format json") {
+		t.Fatalf("expected a decode error naming the expected format, got %v", err)
+	}
+}
+
+func TestListTabsChangedSinceShowsNewAndClosedTabs(t *testing.T) {
+	restore := stubListSources(func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+		return []osascript.TabEntry{
+			{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Kept", URL: "https://kept.example"},
+			{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "New", URL: "https://new.example"},
+		}, nil, nil
+	}, nil)
+	defer restore()
+
+	snapshot, err := json.Marshal([]osascript.TabEntry{
+		{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Kept", URL: "https://kept.example"},
+		{Browser: "safari", WindowIndex: 1, TabIndex: 2, Title: "Gone", URL: "https://gone.example"},
+	})
+	if err != nil {
+		t.Fatalf("marshal snapshot: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, snapshot, 0o600); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	payload, _, err := runRootCommandToFile(t, "list", "tabs", "--changed-since", path)
+	if err != nil {
+		t.Fatalf("list tabs --changed-since returned error: %v", err)
+	}
+	if string(payload) != "# Open Tabs\n- safari w1:t2 - New - https://new.example\n" {
+		t.Fatalf("unexpected listing:\n%s", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "list", "tabs", "--changed-since", path, "--show-closed")
+	if err != nil {
+		t.Fatalf("list tabs --show-closed returned error: %v", err)
+	}
+	expected := "# Open Tabs\n- safari w1:t2 - New - https://new.example\n" +
+		"\n# Closed Tabs\n- safari w1:t2 - Gone - https://gone.example\n"
+	if string(payload) != expected {
+		t.Fatalf("unexpected listing:\n%s", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "--format", "json", "list", "tabs", "--changed-since", path, "--show-closed")
+	if err != nil {
+		t.Fatalf("list tabs --show-closed json returned error: %v", err)
+	}
+	var changes tabChanges
+	if err := json.Unmarshal(payload, &changes); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, payload)
+	}
+	if len(changes.Opened) != 1 || changes.Opened[0].URL != "https://new.example" ||
+		len(changes.Closed) != 1 || changes.Closed[0].URL != "https://gone.example" {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestListTabsShowClosedRequiresChangedSince(t *testing.T) {
+	restore := stubListSources(func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+		t.Fatalf("tabs should not be listed for a usage error")
+		return nil, nil, nil
+	}, nil)
+	defer restore()
+
+	_, _, err := runRootCommandToFile(t, "list", "tabs", "--show-closed")
+	if err == nil || ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	_, _, err = runRootCommandToFile(t, "list", "tabs", "--changed-since", filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for a missing snapshot, got %v", err)
+	}
+}
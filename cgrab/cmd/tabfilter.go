@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/spf13/cobra"
+)
+
+// tabFilter holds the flags that drop tabs before they are rendered or
+// considered as capture targets.
+type tabFilter struct {
+	onlyHTTP       bool
+	includeDomains []string
+	excludeDomains []string
+	audibleOnly    bool
+}
+
+func (f *tabFilter) register(command *cobra.Command) {
+	command.Flags().BoolVar(&f.onlyHTTP, "only-http", false, "only include tabs with http or https URLs")
+	command.Flags().BoolVar(&f.audibleOnly, "audible-only", false, "only include tabs playing audio (reported by the browser extension only)")
+	f.registerDomains(command)
+}
+
+func (f *tabFilter) registerDomains(command *cobra.Command) {
+	command.Flags().StringArrayVar(&f.includeDomains, "include-domain", nil, "only include tabs whose host is or ends with this domain (repeatable)")
+	command.Flags().StringArrayVar(&f.excludeDomains, "exclude-domain", nil, "drop tabs whose host is or ends with this domain (repeatable)")
+}
+
+func (f tabFilter) apply(tabs []osascript.TabEntry) []osascript.TabEntry {
+	if !f.onlyHTTP && !f.audibleOnly && len(f.includeDomains) == 0 && len(f.excludeDomains) == 0 {
+		return tabs
+	}
+	filtered := make([]osascript.TabEntry, 0, len(tabs))
+	for _, tab := range tabs {
+		if f.audibleOnly && !tab.IsAudible {
+			continue
+		}
+		if f.allowsURL(tab.URL) {
+			filtered = append(filtered, tab)
+		}
+	}
+	return filtered
+}
+
+// allowsURL reports whether a tab with rawURL passes the filter. URLs without
+// a parseable host match no domain: they fail --include-domain and pass
+// --exclude-domain.
+func (f tabFilter) allowsURL(rawURL string) bool {
+	if f.onlyHTTP && !isHTTPURL(rawURL) {
+		return false
+	}
+	host := urlHost(rawURL)
+	if len(f.includeDomains) > 0 && !hostMatchesAnyDomain(host, f.includeDomains) {
+		return false
+	}
+	return !hostMatchesAnyDomain(host, f.excludeDomains)
+}
+
+func urlHost(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func hostMatchesAnyDomain(host string, domains []string) bool {
+	if host == "" {
+		return false
+	}
+	for _, domain := range domains {
+		domain = normalizeDomain(domain)
+		if domain == "" {
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDomain(raw string) string {
+	domain := strings.ToLower(strings.TrimSpace(raw))
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.Trim(domain, ".")
+}
+
+func isHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	return (scheme == "http" || scheme == "https") && parsed.Host != ""
+}
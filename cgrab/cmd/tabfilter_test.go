@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestTabFilterMatchesDomainSuffixes(t *testing.T) {
+	tabs := []osascript.TabEntry{
+		{TabIndex: 1, URL: "https://docs.example.com/guide"},
+		{TabIndex: 2, URL: "https://example.com"},
+		{TabIndex: 3, URL: "https://notexample.com"},
+		{TabIndex: 4, URL: "https://mail.private.test/inbox"},
+		{TabIndex: 5, URL: ""},
+	}
+
+	included := tabFilter{includeDomains: []string{"Example.com"}}.apply(tabs)
+	if len(included) != 2 || included[0].TabIndex != 1 || included[1].TabIndex != 2 {
+		t.Fatalf("unexpected --include-domain result: %#v", included)
+	}
+
+	excluded := tabFilter{excludeDomains: []string{"*.private.test"}}.apply(tabs)
+	if len(excluded) != 4 {
+		t.Fatalf("expected only the private tab dropped, got %#v", excluded)
+	}
+	for _, tab := range excluded {
+		if tab.TabIndex == 4 {
+			t.Fatalf("expected private tab excluded, got %#v", excluded)
+		}
+	}
+}
+
+func TestTabFilterAudibleOnly(t *testing.T) {
+	tabs := []osascript.TabEntry{
+		{TabIndex: 1, URL: "https://music.example.com", IsAudible: true},
+		{TabIndex: 2, URL: "https://docs.example.com"},
+	}
+	audible := tabFilter{audibleOnly: true}.apply(tabs)
+	if len(audible) != 1 || audible[0].TabIndex != 1 {
+		t.Fatalf("expected only the audible tab, got %#v", audible)
+	}
+	rendered, err := renderTabs(formatMarkdown, audible, listRenderOptions{})
+	if err != nil || !strings.Contains(string(rendered), "(audible)") {
+		t.Fatalf("expected audible label in markdown, got %q (%v)", rendered, err)
+	}
+}
+
+func TestCaptureSkipsTabsExcludedByDomain(t *testing.T) {
+	stubCaptureEnvironment(t)
+	activated := false
+	activateTabFunc = func(context.Context, string, int, int) error {
+		activated = true
+		return nil
+	}
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Bank", URL: "https://bank.example/account"},
+			}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	_, _, err := runRootCommand("capture", "--title-match", "bank", "--exclude-domain", "bank.example")
+	if code := ExitCode(err); code != ExitCodeNoMatch {
+		t.Fatalf("expected exit code %d, got %d (err=%v)", ExitCodeNoMatch, code, err)
+	}
+	if activated {
+		t.Fatalf("expected excluded tab never to be activated")
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// captureTimings reports how long each capture phase took, for --timings.
+// ActivateMs is zero when nothing was activated (e.g. --focused).
+type captureTimings struct {
+	ActivateMs int64 `json:"activateMs"`
+	CaptureMs  int64 `json:"captureMs"`
+	TotalMs    int64 `json:"totalMs"`
+}
+
+func elapsedMs(start time.Time) int64 {
+	return nowFunc().Sub(start).Milliseconds()
+}
+
+// reportTimings completes timings with the total since startedAt and returns
+// it when --timings is set, or nil so the field is omitted.
+func (r captureRequest) reportTimings(timings *captureTimings, startedAt time.Time) *captureTimings {
+	if !r.timings {
+		return nil
+	}
+	timings.TotalMs = elapsedMs(startedAt)
+	return timings
+}
+
+// appendCaptureTimings adds a "timings" field to the JSON object the host
+// app rendered for a desktop capture, re-indenting the result.
+func appendCaptureTimings(document []byte, timings *captureTimings) ([]byte, error) {
+	appended, err := appendJSONObjectField(document, "timings", timings)
+	if err != nil {
+		return nil, fmt.Errorf("add capture timings: %w", err)
+	}
+	return appended, nil
+}
+
+// appendJSONObjectField adds key as the last field of a JSON object,
+// keeping the existing fields in order, and re-indents the result.
+func appendJSONObjectField(document []byte, key string, value any) ([]byte, error) {
+	trimmed := bytes.TrimSpace(document)
+	if !json.Valid(trimmed) || len(trimmed) == 0 || trimmed[len(trimmed)-1] != '}' {
+		return nil, fmt.Errorf("output is not a JSON object")
+	}
+	encodedKey, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	body := bytes.TrimSpace(trimmed[:len(trimmed)-1])
+	var combined bytes.Buffer
+	combined.Write(body)
+	if body[len(body)-1] != '{' {
+		combined.WriteByte(',')
+	}
+	combined.Write(encodedKey)
+	combined.WriteByte(':')
+	combined.Write(encoded)
+	combined.WriteByte('}')
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, combined.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+// stubSteppingClock makes every nowFunc call advance by step.
+func stubSteppingClock(t *testing.T, step time.Duration) {
+	t.Helper()
+	previous := nowFunc
+	t.Cleanup(func() { nowFunc = previous })
+	current := time.Date(2026, time.February, 15, 12, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time {
+		current = current.Add(step)
+		return current
+	}
+}
+
+func TestAppendCaptureTimingsAddsFieldToHostJSON(t *testing.T) {
+	timings := &captureTimings{ActivateMs: 1, CaptureMs: 2, TotalMs: 3}
+	rendered, err := appendCaptureTimings([]byte("{\n  \"appName\": \"Finder\"\n}\n"), timings)
+	if err != nil {
+		t.Fatalf("appendCaptureTimings returned error: %v", err)
+	}
+	want := "{\n  \"appName\": \"Finder\",\n  \"timings\": {\n    \"activateMs\": 1,\n    \"captureMs\": 2,\n    \"totalMs\": 3\n  }\n}\n"
+	if string(rendered) != want {
+		t.Fatalf("unexpected output:\n%s", rendered)
+	}
+
+	if rendered, err = appendCaptureTimings([]byte("{}"), timings); err != nil || !json.Valid(rendered) {
+		t.Fatalf("expected valid JSON for an empty object, got %q err=%v", rendered, err)
+	}
+	if _, err := appendCaptureTimings([]byte("# Finder\n"), timings); err == nil {
+		t.Fatalf("expected error for non-JSON host output")
+	}
+}
+
+func TestCaptureTimingsReportsPhaseDurations(t *testing.T) {
+	stubCaptureEnvironment(t)
+	restore := stubListSources(
+		func(context.Context, string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Doc", URL: "https://example.com"}}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Doc\n"}, nil
+	}
+	stubSteppingClock(t, 5*time.Millisecond)
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--tab", "1:1", "--format", "json", "--timings")
+	if err != nil {
+		t.Fatalf("capture --timings returned error: %v", err)
+	}
+	var decoded struct {
+		Timings *captureTimings `json:"timings"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("decode capture output: %v", err)
+	}
+	if decoded.Timings == nil || decoded.Timings.ActivateMs != 5 || decoded.Timings.CaptureMs != 5 || decoded.Timings.TotalMs <= decoded.Timings.CaptureMs {
+		t.Fatalf("unexpected timings: %+v", decoded.Timings)
+	}
+
+	if _, _, err := runRootCommandToFile(t, "capture", "--tab", "1:1", "--timings"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for --timings with markdown, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// normalizeMarkdownWhitespace trims trailing whitespace from every line,
+// collapses runs of three or more blank lines to two, and ends the text with
+// exactly one newline.
+func normalizeMarkdownWhitespace(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	normalized := make([]string, 0, len(lines))
+	blankRun := 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blankRun++
+			if blankRun > 2 {
+				continue
+			}
+		} else {
+			blankRun = 0
+		}
+		normalized = append(normalized, line)
+	}
+	trimmed := strings.TrimRight(strings.Join(normalized, "\n"), "\n")
+	if trimmed == "" {
+		return ""
+	}
+	return trimmed + "\n"
+}
+
+// truncatedMarker ends markdown cut short by --max-chars or --max-tokens.
+const truncatedMarker = "\n\n[truncated]\n"
+
+// charsPerToken is the rough characters-per-token ratio --max-tokens uses
+// to turn a token budget into a character budget.
+const charsPerToken = 4
+
+// captureCharBudget returns the character limit set by --max-chars or
+// --max-tokens, or 0 when captures are not trimmed.
+func (r captureRequest) captureCharBudget() int {
+	if r.maxTokens > 0 {
+		return r.maxTokens * charsPerToken
+	}
+	return r.maxChars
+}
+
+func (r captureRequest) validateCharBudget(desktop bool) error {
+	if r.maxChars < 0 || r.maxTokens < 0 {
+		return fmt.Errorf("--max-chars and --max-tokens cannot be negative")
+	}
+	if r.maxChars == 0 && r.maxTokens == 0 {
+		return nil
+	}
+	if r.maxChars > 0 && r.maxTokens > 0 {
+		return fmt.Errorf("--max-chars cannot be combined with --max-tokens")
+	}
+	if desktop {
+		return fmt.Errorf("--max-chars and --max-tokens apply only to browser capture")
+	}
+	if r.raw || r.method == browserMethodURLPDF {
+		return fmt.Errorf("--max-chars and --max-tokens cannot be combined with --raw or --method url-pdf")
+	}
+	return nil
+}
+
+// truncateMarkdown shortens markdown to at most limit characters, marker
+// included, and reports whether it did. The cut falls on the last paragraph
+// break inside the budget, else the last line break, else the last space, so
+// a word or line is not split unless it alone overflows the budget.
+func truncateMarkdown(markdown string, limit int) (string, bool) {
+	if limit <= 0 || utf8.RuneCountInString(markdown) <= limit {
+		return markdown, false
+	}
+	budget := max(limit-utf8.RuneCountInString(truncatedMarker), 0)
+	cut := 0
+	for index := range markdown {
+		if budget == 0 {
+			break
+		}
+		budget--
+		_, size := utf8.DecodeRuneInString(markdown[index:])
+		cut = index + size
+	}
+	kept := markdown[:cut]
+	// Only back off to a boundary that keeps at least half the budget;
+	// otherwise one long paragraph would shrink to almost nothing.
+	for _, boundary := range []string{"\n\n", "\n", " "} {
+		if at := strings.LastIndex(kept, boundary); at > 0 && at >= len(kept)/2 {
+			kept = kept[:at]
+			break
+		}
+	}
+	return strings.TrimRight(kept, " \t\n") + truncatedMarker, true
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+)
+
+func TestTruncateMarkdownCutsAtParagraphBoundary(t *testing.T) {
+	markdown := "# Title\n\nFirst paragraph here.\n\nSecond paragraph that runs long.\n"
+
+	if kept, truncated := truncateMarkdown(markdown, len(markdown)); truncated || kept != markdown {
+		t.Fatalf("expected markdown within budget to be unchanged, got %q", kept)
+	}
+
+	kept, truncated := truncateMarkdown(markdown, 50)
+	if !truncated || kept != "# Title\n\nFirst paragraph here.\n\n[truncated]\n" {
+		t.Fatalf("unexpected truncation: %q", kept)
+	}
+	if utf8.RuneCountInString(kept) > 50 {
+		t.Fatalf("expected at most 50 characters, got %d", utf8.RuneCountInString(kept))
+	}
+
+	kept, _ = truncateMarkdown(strings.Repeat("é", 40), 20)
+	if kept != strings.Repeat("é", 6)+truncatedMarker {
+		t.Fatalf("expected a rune-safe hard cut, got %q", kept)
+	}
+}
+
+func TestCaptureMaxTokensMarksJSONTruncated(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{
+			ExtractionMethod: "browser_extension",
+			Markdown:         "# Docs\n\n" + strings.Repeat("word ", 40) + "\n\nTail paragraph.\n",
+		}, nil
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome", "--format", "json", "--max-tokens", "20")
+	if err != nil {
+		t.Fatalf("capture --max-tokens returned error: %v", err)
+	}
+	var decoded browserCaptureOutput
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("decode capture output: %v", err)
+	}
+	if !decoded.Truncated || !strings.HasSuffix(decoded.Markdown, truncatedMarker) || utf8.RuneCountInString(decoded.Markdown) > 80 {
+		t.Fatalf("expected markdown trimmed to 80 characters, got truncated=%v %q", decoded.Truncated, decoded.Markdown)
+	}
+
+	for _, args := range [][]string{
+		{"capture", "--focused", "--max-chars", "-1"},
+		{"capture", "--focused", "--max-chars", "100", "--max-tokens", "10"},
+		{"capture", "--app", "Finder", "--max-chars", "100"},
+		{"capture", "--focused", "--raw", "--max-tokens", "10"},
+	} {
+		if _, _, err := runRootCommand(args...); ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestNormalizeMarkdownWhitespace(t *testing.T) {
+	input := "# Title  \n\n\n\n\nBody\t\nline\r\n\n\n"
+	if got := normalizeMarkdownWhitespace(input); got != "# Title\n\n\nBody\nline\n" {
+		t.Fatalf("unexpected normalized markdown: %q", got)
+	}
+	if got := normalizeMarkdownWhitespace(" \n\n"); got != "" {
+		t.Fatalf("expected blank markdown to normalize to empty, got %q", got)
+	}
+}
+
+func TestCaptureNormalizesMarkdownWhitespaceByDefault(t *testing.T) {
+	stubCaptureEnvironment(t)
+	captureBrowserFunc = func(context.Context, bridge.BrowserTarget, bridge.BrowserCaptureSource, int, bridge.BrowserCaptureMetadata) (bridge.BrowserCaptureAttempt, error) {
+		return bridge.BrowserCaptureAttempt{ExtractionMethod: "browser_extension", Markdown: "# Docs   \n\n\n\n\nBody\n\n"}, nil
+	}
+
+	payload, _, err := runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome")
+	if err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+	if string(payload) != "# Docs\n\n\nBody\n" {
+		t.Fatalf("expected normalized markdown, got %q", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome", "--normalize-whitespace=false")
+	if err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+	if string(payload) != "# Docs   \n\n\n\n\nBody\n\n" {
+		t.Fatalf("expected verbatim markdown with the opt-out, got %q", payload)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "capture", "--focused", "--browser", "chrome", "--format", "json")
+	if err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+	var decoded browserCaptureOutput
+	if err := json.Unmarshal(payload, &decoded); err != nil || decoded.Markdown != "# Docs   \n\n\n\n\nBody\n\n" {
+		t.Fatalf("expected JSON markdown kept verbatim, got %q (err=%v)", decoded.Markdown, err)
+	}
+}
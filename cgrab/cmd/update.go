@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	latestReleaseURL     = "https://api.github.com/repos/anthonylu23/context_grabber/releases/latest"
+	updateCheckTimeout   = 5 * time.Second
+	updateCheckUserAgent = "cgrab-update-check"
+)
+
+var latestReleaseFunc = fetchLatestRelease
+
+// latestRelease is the part of the GitHub releases API response update
+// --check reads.
+type latestRelease struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// updateCheckResult is the --format json/yaml form of update --check. Error
+// holds why the check could not finish; the command still succeeds.
+type updateCheckResult struct {
+	CurrentVersion  string `json:"currentVersion"`
+	LatestVersion   string `json:"latestVersion,omitempty"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	ReleaseURL      string `json:"releaseURL,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+func newUpdateCommand(global *globalOptions) *cobra.Command {
+	var check bool
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Check GitHub releases for a newer cgrab",
+		Long: "Check GitHub releases for a newer cgrab. Only --check is supported: " +
+			"install updates from the release .pkg or with go install.",
+		Example: "  cgrab update --check\n" +
+			"  cgrab update --check --format json",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !check {
+				return usageError(fmt.Errorf("update requires --check"))
+			}
+			switch global.format {
+			case formatMarkdown, formatJSON, formatYAML:
+			default:
+				return usageError(fmt.Errorf("update supports only --format markdown, json, or yaml"))
+			}
+
+			result := checkForUpdate(cmd.Context(), Version)
+			if result.Error != "" {
+				fmt.Fprintf(global.warnings(cmd.ErrOrStderr()), "warning: update check failed: %s\n", result.Error)
+			}
+			rendered, err := renderUpdateCheck(global.format, result)
+			if err != nil {
+				return err
+			}
+			return global.clipboardResult(output.Write(cmd.Context(), rendered, global.outputFile, global.clipboard, global.writeOptions(global.format)...), cmd.ErrOrStderr())
+		},
+	}
+	updateCmd.Flags().BoolVar(&check, "check", false, "report whether a newer release than this binary is available")
+	return updateCmd
+}
+
+// checkForUpdate compares current against the latest GitHub release. A
+// failed lookup is reported in Error rather than returned, so an offline
+// machine still gets an answer.
+func checkForUpdate(ctx context.Context, current string) updateCheckResult {
+	result := updateCheckResult{CurrentVersion: current}
+	release, err := latestReleaseFunc(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.LatestVersion = strings.TrimPrefix(release.TagName, "v")
+	result.ReleaseURL = release.HTMLURL
+	newer, ok := versionNewer(result.LatestVersion, current)
+	if !ok {
+		result.Error = fmt.Sprintf("cannot compare version %q with release %q", current, release.TagName)
+		return result
+	}
+	result.UpdateAvailable = newer
+	return result
+}
+
+func fetchLatestRelease(ctx context.Context) (latestRelease, error) {
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return latestRelease{}, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("User-Agent", updateCheckUserAgent)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return latestRelease{}, fmt.Errorf("query GitHub releases: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return latestRelease{}, fmt.Errorf("query GitHub releases: %s", response.Status)
+	}
+	var release latestRelease
+	if err := json.NewDecoder(response.Body).Decode(&release); err != nil {
+		return latestRelease{}, fmt.Errorf("decode GitHub release: %w", err)
+	}
+	if release.TagName == "" {
+		return latestRelease{}, fmt.Errorf("GitHub release has no tag")
+	}
+	return release, nil
+}
+
+// versionNewer reports whether latest is a higher major.minor.patch version
+// than current. ok is false when either is not such a version, e.g. a "dev"
+// build.
+func versionNewer(latest string, current string) (newer bool, ok bool) {
+	latestParts, latestOK := parseReleaseVersion(latest)
+	currentParts, currentOK := parseReleaseVersion(current)
+	if !latestOK || !currentOK {
+		return false, false
+	}
+	for i := range latestParts {
+		if latestParts[i] != currentParts[i] {
+			return latestParts[i] > currentParts[i], true
+		}
+	}
+	return false, true
+}
+
+// parseReleaseVersion parses "1.2.3" or "v1.2.3", ignoring any
+// "-prerelease" or "+build" suffix.
+func parseReleaseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if cut := strings.IndexAny(version, "-+"); cut >= 0 {
+		version = version[:cut]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 {
+			return parts, false
+		}
+		parts[i] = value
+	}
+	return parts, true
+}
+
+func renderUpdateCheck(format string, result updateCheckResult) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		return json.MarshalIndent(result, "", "  ")
+	case formatYAML:
+		return renderJSONAsYAML(func(format string) ([]byte, error) {
+			return renderUpdateCheck(format, result)
+		})
+	}
+	switch {
+	case result.UpdateAvailable:
+		return []byte(fmt.Sprintf("Update available: cgrab %s -> %s\n%s\n", result.CurrentVersion, result.LatestVersion, result.ReleaseURL)), nil
+	case result.Error != "":
+		return []byte(fmt.Sprintf("Could not check for updates (cgrab %s).\n", result.CurrentVersion)), nil
+	default:
+		return []byte(fmt.Sprintf("cgrab %s is up to date (latest release: %s).\n", result.CurrentVersion, result.LatestVersion)), nil
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func stubLatestRelease(t *testing.T, release latestRelease, err error) {
+	t.Helper()
+	previousRelease := latestReleaseFunc
+	previousVersion := Version
+	latestReleaseFunc = func(context.Context) (latestRelease, error) {
+		return release, err
+	}
+	Version = "0.1.0"
+	t.Cleanup(func() {
+		latestReleaseFunc = previousRelease
+		Version = previousVersion
+	})
+}
+
+func TestVersionNewer(t *testing.T) {
+	cases := []struct {
+		latest, current string
+		newer, ok       bool
+	}{
+		{"0.2.0", "0.1.0", true, true},
+		{"v0.10.0", "0.9.3", true, true},
+		{"0.1.0", "0.1.0", false, true},
+		{"0.1.0", "0.2.0-rc.1", false, true},
+		{"0.2.0", "dev", false, false},
+	}
+	for _, tc := range cases {
+		newer, ok := versionNewer(tc.latest, tc.current)
+		if newer != tc.newer || ok != tc.ok {
+			t.Fatalf("versionNewer(%q, %q) = %v, %v; want %v, %v", tc.latest, tc.current, newer, ok, tc.newer, tc.ok)
+		}
+	}
+}
+
+func TestUpdateCheckReportsAvailableRelease(t *testing.T) {
+	stubLatestRelease(t, latestRelease{TagName: "v0.2.0", HTMLURL: "https://github.com/anthonylu23/context_grabber/releases/tag/v0.2.0"}, nil)
+
+	payload, _, err := runRootCommandToFile(t, "update", "--check", "--format", "json")
+	if err != nil {
+		t.Fatalf("update --check returned error: %v", err)
+	}
+	var result updateCheckResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, payload)
+	}
+	expected := updateCheckResult{
+		CurrentVersion:  "0.1.0",
+		LatestVersion:   "0.2.0",
+		UpdateAvailable: true,
+		ReleaseURL:      "https://github.com/anthonylu23/context_grabber/releases/tag/v0.2.0",
+	}
+	if result != expected {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	payload, _, err = runRootCommandToFile(t, "update", "--check")
+	if err != nil {
+		t.Fatalf("update --check returned error: %v", err)
+	}
+	if string(payload) != "Update available: cgrab 0.1.0 -> 0.2.0\n"+expected.ReleaseURL+"\n" {
+		t.Fatalf("unexpected markdown:\n%s", payload)
+	}
+}
+
+func TestUpdateCheckFailsSoft(t *testing.T) {
+	stubLatestRelease(t, latestRelease{}, errors.New("query GitHub releases: context deadline exceeded"))
+
+	payload, stderr, err := runRootCommandToFile(t, "update", "--check")
+	if err != nil {
+		t.Fatalf("expected a failed lookup to succeed, got %v", err)
+	}
+	if string(payload) != "Could not check for updates (cgrab 0.1.0).\n" {
+		t.Fatalf("unexpected markdown:\n%s", payload)
+	}
+	if !strings.Contains(stderr, "update check failed: query GitHub releases: context deadline exceeded") {
+		t.Fatalf("expected a warning, got %q", stderr)
+	}
+
+	if _, _, err := runRootCommand("update"); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected update without --check to be a usage error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+	"github.com/spf13/cobra"
+)
+
+// urlCleaner holds the flags that normalize URLs before they are rendered,
+// so tracking parameters do not clutter output or defeat deduplication.
+type urlCleaner struct {
+	stripQuery    bool
+	stripFragment bool
+	keepParams    []string
+}
+
+func (c *urlCleaner) register(command *cobra.Command) {
+	command.Flags().BoolVar(&c.stripQuery, "strip-query", false, "remove the query string from URLs")
+	command.Flags().BoolVar(&c.stripFragment, "strip-fragment", false, "remove the #fragment from URLs")
+	command.Flags().StringSliceVar(&c.keepParams, "keep-params", nil, "with --strip-query, comma-separated query keys to keep (implies --strip-query)")
+}
+
+func (c urlCleaner) active() bool {
+	return c.stripQuery || c.stripFragment || len(c.keepParams) > 0
+}
+
+// clean returns rawURL without its query (except --keep-params keys) and,
+// with --strip-fragment, without its fragment. URLs that do not parse are
+// returned unchanged.
+func (c urlCleaner) clean(rawURL string) string {
+	if !c.active() || strings.TrimSpace(rawURL) == "" {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if c.stripQuery || len(c.keepParams) > 0 {
+		query := parsed.Query()
+		kept := url.Values{}
+		for _, key := range c.keepParams {
+			key = strings.TrimSpace(key)
+			if values, ok := query[key]; ok {
+				kept[key] = values
+			}
+		}
+		parsed.RawQuery = kept.Encode()
+		parsed.ForceQuery = false
+	}
+	if c.stripFragment {
+		parsed.Fragment = ""
+		parsed.RawFragment = ""
+	}
+	return parsed.String()
+}
+
+func (c urlCleaner) applyTabs(tabs []osascript.TabEntry) []osascript.TabEntry {
+	if !c.active() {
+		return tabs
+	}
+	cleaned := make([]osascript.TabEntry, len(tabs))
+	for i, tab := range tabs {
+		tab.URL = c.clean(tab.URL)
+		cleaned[i] = tab
+	}
+	return cleaned
+}
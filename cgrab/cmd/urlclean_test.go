@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/bridge"
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestURLCleanerClean(t *testing.T) {
+	const raw = "https://example.com/docs?utm_source=news&id=7&ref=x#intro"
+	cases := []struct {
+		name    string
+		cleaner urlCleaner
+		want    string
+	}{
+		{name: "inactive", cleaner: urlCleaner{}, want: raw},
+		{name: "strip query", cleaner: urlCleaner{stripQuery: true}, want: "https://example.com/docs#intro"},
+		{name: "strip fragment", cleaner: urlCleaner{stripFragment: true}, want: "https://example.com/docs?utm_source=news&id=7&ref=x"},
+		{name: "keep params", cleaner: urlCleaner{keepParams: []string{"id", "missing"}}, want: "https://example.com/docs?id=7#intro"},
+		{name: "both", cleaner: urlCleaner{stripQuery: true, stripFragment: true}, want: "https://example.com/docs"},
+	}
+	for _, tc := range cases {
+		if got := tc.cleaner.clean(raw); got != tc.want {
+			t.Fatalf("%s: got %q want %q", tc.name, got, tc.want)
+		}
+	}
+	if got := (urlCleaner{stripQuery: true}).clean("%zz?x=1"); got != "%zz?x=1" {
+		t.Fatalf("expected unparseable URL unchanged, got %q", got)
+	}
+}
+
+func TestListTabsStripQueryKeepsParams(t *testing.T) {
+	restore := stubListSources(
+		func(_ context.Context, _ string) ([]osascript.TabEntry, []string, error) {
+			return []osascript.TabEntry{
+				{Browser: "safari", WindowIndex: 1, TabIndex: 1, Title: "Video", URL: "https://video.example/watch?v=abc&utm_campaign=x"},
+			}, nil, nil
+		},
+		nil,
+	)
+	defer restore()
+
+	payload, _, err := runRootCommandToFile(t, "list", "tabs", "--format", "json", "--keep-params", "v")
+	if err != nil {
+		t.Fatalf("list tabs returned error: %v", err)
+	}
+	if !strings.Contains(string(payload), `"url": "https://video.example/watch?v=abc"`) {
+		t.Fatalf("expected tracking params stripped, got %s", payload)
+	}
+}
+
+func TestCleanCapturedURLsRewritesMetadataPayloadAndMarkdown(t *testing.T) {
+	const raw = "https://example.com/post?utm_source=feed"
+	payload := map[string]any{"url": raw, "title": "Post"}
+	attempt := bridge.BrowserCaptureAttempt{
+		Markdown: "Source: " + raw + "\n",
+		Payload:  payload,
+	}
+
+	cleanedAttempt, metadata := cleanCapturedURLs(urlCleaner{stripQuery: true}, attempt, bridge.BrowserCaptureMetadata{URL: raw})
+	if metadata.URL != "https://example.com/post" || cleanedAttempt.Payload["url"] != "https://example.com/post" {
+		t.Fatalf("expected cleaned metadata and payload, got %+v %+v", metadata, cleanedAttempt.Payload)
+	}
+	if strings.Contains(cleanedAttempt.Markdown, "utm_source") {
+		t.Fatalf("expected markdown URL cleaned, got %q", cleanedAttempt.Markdown)
+	}
+	if payload["url"] != raw {
+		t.Fatalf("expected original payload map untouched")
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const clearScreenSequence = "\x1b[H\x1b[2J"
+
+// runListWatch re-renders on every interval until the context is canceled.
+// Terminals are cleared and redrawn;
+// other writers get timestamped snapshots appended.
+func runListWatch(
+	ctx context.Context,
+	stdout io.Writer,
+	stderr io.Writer,
+	interval time.Duration,
+	render func(context.Context) ([]byte, error),
+) error {
+	redraw := isTerminalWriter(stdout)
+	return runWatchLoop(ctx, interval, func(ctx context.Context) {
+		rendered, err := render(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			writeWarnings(stderr, []string{err.Error()})
+		} else if redraw {
+			fmt.Fprint(stdout, clearScreenSequence)
+			stdout.Write(rendered)
+		} else {
+			fmt.Fprintf(stdout, "<!-- snapshot %s -->\n", nowFunc().UTC().Format(time.RFC3339))
+			stdout.Write(rendered)
+			fmt.Fprintln(stdout)
+		}
+	})
+}
+
+// runWatchLoop calls tick immediately and then on every interval until the
+// context is canceled, which ends the loop cleanly. main cancels the command
+// context on SIGINT/SIGTERM.
+func runWatchLoop(ctx context.Context, interval time.Duration, tick func(context.Context)) error {
+	for {
+		tick(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -10,13 +10,19 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
 )
 
 type BrowserTarget string
 
 const (
-	BrowserTargetSafari BrowserTarget = "safari"
-	BrowserTargetChrome BrowserTarget = "chrome"
+	BrowserTargetSafari       BrowserTarget = "safari"
+	BrowserTargetSafariTP     BrowserTarget = "safari-tp"
+	BrowserTargetChrome       BrowserTarget = "chrome"
+	BrowserTargetChromeBeta   BrowserTarget = "chrome-beta"
+	BrowserTargetChromeDev    BrowserTarget = "chrome-dev"
+	BrowserTargetChromeCanary BrowserTarget = "chrome-canary"
 )
 
 type BrowserCaptureSource string
@@ -25,6 +31,13 @@ const (
 	BrowserCaptureSourceAuto    BrowserCaptureSource = "auto"
 	BrowserCaptureSourceLive    BrowserCaptureSource = "live"
 	BrowserCaptureSourceRuntime BrowserCaptureSource = "runtime"
+	// BrowserCaptureSourceURLPDF re-renders the page URL to a PDF file instead of
+	// extracting text; see RenderURLToPDF.
+	BrowserCaptureSourceURLPDF BrowserCaptureSource = "url-pdf"
+	// BrowserCaptureSourceSelection captures like BrowserCaptureSourceAuto
+	// but keeps only the page's selected text (the bridge payload's
+	// selectionText, read from window.getSelection()).
+	BrowserCaptureSourceSelection BrowserCaptureSource = "selection"
 )
 
 type BrowserCaptureMetadata struct {
@@ -65,6 +78,7 @@ func (defaultBrowserCaptureRunner) Run(
 	args []string,
 	env []string,
 ) (string, string, error) {
+	logCommand(dir, name, args)
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
 	cmd.Env = env
@@ -99,15 +113,36 @@ func CaptureBrowser(
 	if source == "" {
 		source = BrowserCaptureSourceAuto
 	}
-	if target != BrowserTargetSafari && target != BrowserTargetChrome {
+	app, ok := osascript.LookupBrowser(string(target))
+	if !ok {
 		return BrowserCaptureAttempt{}, fmt.Errorf("unsupported browser target: %s", target)
 	}
-	switch source {
+	bridgeSource := source
+	if source == BrowserCaptureSourceSelection {
+		bridgeSource = BrowserCaptureSourceAuto
+	}
+	switch bridgeSource {
 	case BrowserCaptureSourceAuto, BrowserCaptureSourceLive, BrowserCaptureSourceRuntime:
 	default:
 		return BrowserCaptureAttempt{}, fmt.Errorf("unsupported browser capture source: %s", source)
 	}
 
+	// The Safari extension bridge only drives Safari itself; Technology
+	// Preview is reported as unavailable so callers can fall back to
+	// AppleScript extraction.
+	if app.Family == osascript.BrowserFamilySafari && target != BrowserTargetSafari {
+		return BrowserCaptureAttempt{
+			ExtractionMethod: "metadata_only",
+			ErrorCode:        "ERR_EXTENSION_UNAVAILABLE",
+			Warnings:         []string{fmt.Sprintf("%s is not supported by the Safari extension bridge.", app.DisplayName)},
+			Payload:          map[string]any{},
+		}, nil
+	}
+	if app.Family == osascript.BrowserFamilyChrome && target != BrowserTargetChrome &&
+		strings.TrimSpace(metadata.ChromeAppName) == "" {
+		metadata.ChromeAppName = app.AppName
+	}
+
 	repoRoot, err := resolveRepoRoot()
 	if err != nil {
 		return BrowserCaptureAttempt{}, err
@@ -126,9 +161,9 @@ func CaptureBrowser(
 	args := []string{
 		scriptPath,
 		"--target",
-		string(target),
+		app.Family,
 		"--source",
-		string(source),
+		string(bridgeSource),
 		"--timeout-ms",
 		strconv.Itoa(timeoutMs),
 	}
@@ -141,7 +176,7 @@ func CaptureBrowser(
 	if siteName := strings.TrimSpace(metadata.SiteName); siteName != "" {
 		args = append(args, "--site-name", siteName)
 	}
-	if target == BrowserTargetChrome {
+	if app.Family == osascript.BrowserFamilyChrome {
 		if chromeAppName := strings.TrimSpace(metadata.ChromeAppName); chromeAppName != "" {
 			args = append(args, "--chrome-app-name", chromeAppName)
 		}
@@ -175,5 +210,26 @@ func CaptureBrowser(
 	if attempt.Warnings == nil {
 		attempt.Warnings = []string{}
 	}
+	if source == BrowserCaptureSourceSelection {
+		attempt = selectionAttempt(attempt)
+	}
 	return attempt, nil
 }
+
+// selectionAttempt replaces a successful capture's markdown with the page's
+// selected text (extraction method "selection"). Without a selection the
+// full-page capture is kept with a warning.
+func selectionAttempt(attempt BrowserCaptureAttempt) BrowserCaptureAttempt {
+	if attempt.ExtractionMethod != "browser_extension" {
+		return attempt
+	}
+	selection, _ := attempt.Payload["selectionText"].(string)
+	selection = strings.TrimSpace(selection)
+	if selection == "" {
+		attempt.Warnings = append(attempt.Warnings, "No text is selected in the page; captured the full page instead.")
+		return attempt
+	}
+	attempt.ExtractionMethod = "selection"
+	attempt.Markdown = selection + "\n"
+	return attempt
+}
@@ -1,6 +1,7 @@
 package bridge
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -93,3 +94,68 @@ func mustWriteExecutableFile(t *testing.T, path string, content string) {
 		}
 	}
 }
+
+func TestLogCommandRedactsMetadataAndSummarizesScripts(t *testing.T) {
+	var logged bytes.Buffer
+	SetVerboseLog(&logged)
+	defer SetVerboseLog(nil)
+
+	logCommand("/repo", "/usr/local/bin/bun", []string{
+		"browser_capture.ts", "--target", "safari", "--title", "Secret Doc", "--url", "https://example.com/?token=1",
+	})
+	logCommand("", "/usr/bin/osascript", []string{"-e", "line one\nline two"})
+
+	output := logged.String()
+	if strings.Contains(output, "Secret Doc") || strings.Contains(output, "token=1") {
+		t.Fatalf("expected title and url to be redacted, got %q", output)
+	}
+	if !strings.Contains(output, "verbose: exec /usr/local/bin/bun browser_capture.ts --target safari --title <redacted> --url <redacted> (dir /repo)") {
+		t.Fatalf("unexpected bun log line: %q", output)
+	}
+	if !strings.Contains(output, "verbose: exec /usr/bin/osascript -e <script 2 lines>\n") {
+		t.Fatalf("unexpected osascript log line: %q", output)
+	}
+}
+
+func TestLogCommandSilentByDefault(t *testing.T) {
+	SetVerboseLog(nil)
+	logCommand("", "bun", []string{"--ping"})
+}
+
+func TestCaptureBrowserSelectionKeepsSelectedText(t *testing.T) {
+	tempRoot := t.TempDir()
+	mustWriteExecutableFile(t, filepath.Join(tempRoot, "packages", "shared-types", "package.json"), "{}")
+	mustWriteExecutableFile(t, filepath.Join(tempRoot, "cgrab", "internal", "bridge", "browser_capture.ts"), "// script")
+	bunPath := filepath.Join(tempRoot, "bin", "bun")
+	mustWriteExecutableFile(t, bunPath, "#!/bin/sh\necho bun\n")
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", tempRoot)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", bunPath)
+
+	selection := "  Selected words  "
+	var capturedArgs []string
+	restore := setBunCaptureRunnerForTesting(mockBunRunner(func(_ context.Context, _ string, _ string, args []string, _ []string) (string, string, error) {
+		capturedArgs = append([]string{}, args...)
+		return `{"extractionMethod":"browser_extension","warnings":[],"markdown":"# Full page\n","payload":{"selectionText":"` + selection + `"}}`, "", nil
+	}))
+	defer restore()
+
+	attempt, err := CaptureBrowser(context.Background(), BrowserTargetChrome, BrowserCaptureSourceSelection, 1200, BrowserCaptureMetadata{})
+	if err != nil {
+		t.Fatalf("CaptureBrowser returned error: %v", err)
+	}
+	if !strings.Contains(strings.Join(capturedArgs, " "), "--source auto") {
+		t.Fatalf("expected the bridge to run with --source auto, got %q", capturedArgs)
+	}
+	if attempt.ExtractionMethod != "selection" || attempt.Markdown != "Selected words\n" {
+		t.Fatalf("expected the selected text, got %+v", attempt)
+	}
+
+	selection = ""
+	attempt, err = CaptureBrowser(context.Background(), BrowserTargetChrome, BrowserCaptureSourceSelection, 1200, BrowserCaptureMetadata{})
+	if err != nil {
+		t.Fatalf("CaptureBrowser returned error: %v", err)
+	}
+	if attempt.ExtractionMethod != "browser_extension" || attempt.Markdown != "# Full page\n" || len(attempt.Warnings) != 1 {
+		t.Fatalf("expected a full-page fallback with a warning, got %+v", attempt)
+	}
+}
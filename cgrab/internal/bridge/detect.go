@@ -8,9 +8,28 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
 )
 
-const expectedProtocolVersion = "1"
+// supportedProtocolVersions are the bridge protocol versions this CLI can
+// read, oldest first. A bridge reporting any of them in its ping is ready.
+// Only list a version once the TypeScript transport in
+// packages/extension-shared accepts it, or doctor reports bridges as ready
+// that every capture would then reject.
+var supportedProtocolVersions = []string{"1"}
+
+// IsSupportedProtocolVersion reports whether version is in
+// supportedProtocolVersions.
+func IsSupportedProtocolVersion(version string) bool {
+	for _, supported := range supportedProtocolVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
 
 var installedHostBinaryPath = "/Applications/ContextGrabber.app/Contents/MacOS/ContextGrabberHost"
 
@@ -18,17 +37,28 @@ type BridgeStatus struct {
 	Target string `json:"target"`
 	Status string `json:"status"`
 	Detail string `json:"detail,omitempty"`
+	// ProtocolVersion is the negotiated protocol version of a ready bridge.
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
 }
 
 type DoctorReport struct {
-	OverallStatus       string         `json:"overallStatus"`
-	RepoRoot            string         `json:"repoRoot,omitempty"`
-	OsaScriptAvailable  bool           `json:"osascriptAvailable"`
-	BunAvailable        bool           `json:"bunAvailable"`
-	HostBinaryAvailable bool           `json:"hostBinaryAvailable"`
-	HostBinaryPath      string         `json:"hostBinaryPath,omitempty"`
-	Bridges             []BridgeStatus `json:"bridges"`
-	Warnings            []string       `json:"warnings,omitempty"`
+	OverallStatus        string `json:"overallStatus"`
+	RepoRoot             string `json:"repoRoot,omitempty"`
+	OsaScriptAvailable   bool   `json:"osascriptAvailable"`
+	AccessibilityGranted bool   `json:"accessibilityGranted"`
+	// AutomationStatus maps browser targets to their Automation (Apple
+	// Events) permission state: granted, denied, not_running, or unknown.
+	AutomationStatus    map[string]string `json:"automationStatus,omitempty"`
+	BunAvailable        bool              `json:"bunAvailable"`
+	HostBinaryAvailable bool              `json:"hostBinaryAvailable"`
+	HostBinaryPath      string            `json:"hostBinaryPath,omitempty"`
+	Bridges             []BridgeStatus    `json:"bridges"`
+	Warnings            []string          `json:"warnings,omitempty"`
+	GeneratedAt         time.Time         `json:"generatedAt"`
+	// Cached is set by callers that serve a previously saved report.
+	Cached bool `json:"cached"`
+	// Fixes is set by doctor --fix with the remediations it considered.
+	Fixes []DoctorFix `json:"fixes,omitempty"`
 }
 
 type pingResponse struct {
@@ -43,6 +73,7 @@ type commandRunner interface {
 type defaultCommandRunner struct{}
 
 func (defaultCommandRunner) Run(ctx context.Context, dir string, name string, args ...string) (string, string, error) {
+	logCommand(dir, name, args)
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
 	stdoutBytes, err := cmd.Output()
@@ -68,7 +99,7 @@ func setRunnerForTesting(mock commandRunner) func() {
 }
 
 func RunDoctor(ctx context.Context) (DoctorReport, error) {
-	var report DoctorReport
+	report := DoctorReport{GeneratedAt: time.Now().UTC()}
 	repoRoot, repoErr := resolveRepoRoot()
 	if repoErr != nil {
 		report.Warnings = append(
@@ -86,6 +117,28 @@ func RunDoctor(ctx context.Context) (DoctorReport, error) {
 	report.OsaScriptAvailable = isExecutableFile(osaPath)
 	if !report.OsaScriptAvailable {
 		report.Warnings = append(report.Warnings, fmt.Sprintf("osascript not executable: %s", osaPath))
+	} else {
+		report.AccessibilityGranted = probeAccessibilityPermission(ctx, osaPath)
+		if !report.AccessibilityGranted {
+			report.Warnings = append(
+				report.Warnings,
+				"Accessibility permission not granted; grant in System Settings > Privacy & Security > Accessibility",
+			)
+		}
+		report.AutomationStatus = probeAutomationPermissions(ctx, osaPath)
+		for _, target := range osascript.BrowserTargets() {
+			if report.AutomationStatus[target] != AutomationDenied {
+				continue
+			}
+			app, _ := osascript.LookupBrowser(target)
+			report.Warnings = append(
+				report.Warnings,
+				fmt.Sprintf(
+					"Automation permission for %s not granted; allow it in System Settings > Privacy & Security > Automation",
+					app.AppName,
+				),
+			)
+		}
 	}
 
 	bunPath, bunOK := resolveBunPath()
@@ -160,6 +213,42 @@ func checkBrowserBridges(
 	return statuses
 }
 
+// PingBrowserBridge checks whether target's extension bridge answers a
+// --ping, the same probe doctor runs, without capturing anything. Release
+// channels share their family's bridge package, except that the Safari
+// bridge only drives Safari itself.
+func PingBrowserBridge(ctx context.Context, target BrowserTarget) BridgeStatus {
+	_, packagePath, err := bridgePackageForTarget(target)
+	if err != nil {
+		return BridgeStatus{Target: string(target), Status: "unreachable", Detail: err.Error()}
+	}
+	repoRoot, err := resolveRepoRoot()
+	if err != nil {
+		return BridgeStatus{Target: string(target), Status: "unreachable", Detail: "repository root not resolved"}
+	}
+	bunPath, bunOK := resolveBunPath()
+	if !bunOK {
+		return BridgeStatus{Target: string(target), Status: "unreachable", Detail: "bun not available"}
+	}
+	return pingBridge(ctx, repoRoot, bunPath, string(target), packagePath)
+}
+
+// bridgePackageForTarget returns the browser and the repo-relative bridge
+// package that serves target.
+func bridgePackageForTarget(target BrowserTarget) (osascript.BrowserApp, string, error) {
+	app, ok := osascript.LookupBrowser(string(target))
+	if !ok {
+		return osascript.BrowserApp{}, "", fmt.Errorf("unsupported browser target")
+	}
+	if app.Family == osascript.BrowserFamilySafari && target != BrowserTargetSafari {
+		return osascript.BrowserApp{}, "", fmt.Errorf("%s is not supported by the Safari extension bridge", app.DisplayName)
+	}
+	if app.Family == osascript.BrowserFamilySafari {
+		return app, "packages/extension-safari", nil
+	}
+	return app, "packages/extension-chrome", nil
+}
+
 func pingBridge(ctx context.Context, repoRoot string, bunPath string, target string, packagePath string) BridgeStatus {
 	packageDir := filepath.Join(repoRoot, packagePath)
 	manifest := filepath.Join(packageDir, "package.json")
@@ -209,17 +298,22 @@ func pingBridge(ctx context.Context, repoRoot string, bunPath string, target str
 			Detail: "bridge reported not ready",
 		}
 	}
-	if ping.ProtocolVersion != expectedProtocolVersion {
+	if !IsSupportedProtocolVersion(ping.ProtocolVersion) {
 		return BridgeStatus{
 			Target: target,
 			Status: "protocol_mismatch",
-			Detail: fmt.Sprintf("bridge protocol=%s expected=%s", ping.ProtocolVersion, expectedProtocolVersion),
+			Detail: fmt.Sprintf(
+				"bridge protocol=%s supported=%s",
+				ping.ProtocolVersion,
+				strings.Join(supportedProtocolVersions, ","),
+			),
 		}
 	}
 	return BridgeStatus{
-		Target: target,
-		Status: "ready",
-		Detail: fmt.Sprintf("protocol=%s", ping.ProtocolVersion),
+		Target:          target,
+		Status:          "ready",
+		Detail:          fmt.Sprintf("negotiated protocol=%s", ping.ProtocolVersion),
+		ProtocolVersion: ping.ProtocolVersion,
 	}
 }
 
@@ -2,8 +2,10 @@ package bridge
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -110,6 +112,89 @@ func TestRunDoctorReadyWithInstalledHostFallbackOutsideRepo(t *testing.T) {
 	}
 }
 
+func TestRunDoctorReportsMissingAccessibilityPermission(t *testing.T) {
+	tempRoot := t.TempDir()
+	osaPath := filepath.Join(tempRoot, "bin", "osascript")
+	mustWriteFile(t, osaPath, "#!/bin/sh\n", 0o755)
+
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", "")
+	t.Setenv("CONTEXT_GRABBER_OSASCRIPT_BIN", osaPath)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", filepath.Join(tempRoot, "missing", "bun"))
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", filepath.Join(tempRoot, "missing", "ContextGrabberHost"))
+	t.Chdir(tempRoot)
+
+	restore := setRunnerForTesting(mockCommandRunner(func(_ context.Context, _ string, name string, _ ...string) (string, string, error) {
+		if name == osaPath {
+			return "", "execution error: System Events got an error: osascript is not allowed assistive access. (-1719)", errors.New("exit status 1")
+		}
+		return "", "", nil
+	}))
+	defer restore()
+
+	report, err := RunDoctor(context.Background())
+	if err != nil {
+		t.Fatalf("RunDoctor returned error: %v", err)
+	}
+	if report.AccessibilityGranted {
+		t.Fatalf("expected accessibility to be reported as not granted")
+	}
+	if !strings.Contains(strings.Join(report.Warnings, " | "), "Accessibility permission not granted") {
+		t.Fatalf("expected accessibility warning in %v", report.Warnings)
+	}
+}
+
+func TestRunDoctorReportsAutomationStatusPerBrowser(t *testing.T) {
+	tempRoot := t.TempDir()
+	osaPath := filepath.Join(tempRoot, "bin", "osascript")
+	mustWriteFile(t, osaPath, "#!/bin/sh\n", 0o755)
+
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", "")
+	t.Setenv("CONTEXT_GRABBER_OSASCRIPT_BIN", osaPath)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", filepath.Join(tempRoot, "missing", "bun"))
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", filepath.Join(tempRoot, "missing", "ContextGrabberHost"))
+	t.Setenv("CONTEXT_GRABBER_SAFARI_APP_NAME", "Safari Renamed")
+	t.Chdir(tempRoot)
+
+	restore := setRunnerForTesting(mockCommandRunner(func(_ context.Context, _ string, _ string, args ...string) (string, string, error) {
+		script := strings.Join(args, " ")
+		switch {
+		case strings.Contains(script, `set appName to "Google Chrome"`+"\n"):
+			return "", "execution error: Not authorized to send Apple events to Google Chrome. (-1743)", errors.New("exit status 1")
+		case strings.Contains(script, `set appName to "Safari Renamed"`+"\n"):
+			return "running\n", "", nil
+		case strings.Contains(script, `set appName to "Google Chrome Dev"`+"\n"):
+			return "", "execution error: connection invalid (-609)", errors.New("exit status 1")
+		case strings.Contains(script, "set appName to"):
+			return "not_running\n", "", nil
+		}
+		return "1", "", nil
+	}))
+	defer restore()
+
+	report, err := RunDoctor(context.Background())
+	if err != nil {
+		t.Fatalf("RunDoctor returned error: %v", err)
+	}
+	want := map[string]string{
+		"safari":        AutomationGranted,
+		"safari-tp":     AutomationNotRunning,
+		"chrome":        AutomationDenied,
+		"chrome-beta":   AutomationNotRunning,
+		"chrome-dev":    AutomationUnknown,
+		"chrome-canary": AutomationNotRunning,
+	}
+	if !reflect.DeepEqual(report.AutomationStatus, want) {
+		t.Fatalf("unexpected automation status:\nwant: %v\ngot:  %v", want, report.AutomationStatus)
+	}
+	warnings := strings.Join(report.Warnings, " | ")
+	if !strings.Contains(warnings, "Automation permission for Google Chrome not granted") {
+		t.Fatalf("expected chrome automation warning in %v", report.Warnings)
+	}
+	if strings.Count(warnings, "Automation permission for") != 1 {
+		t.Fatalf("expected only the denied browser to warn, got %v", report.Warnings)
+	}
+}
+
 func mustWriteFile(t *testing.T, path string, contents string, mode os.FileMode) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -122,3 +207,65 @@ func mustWriteFile(t *testing.T, path string, contents string, mode os.FileMode)
 		t.Fatalf("chmod failed for %s: %v", path, err)
 	}
 }
+
+func TestPingBrowserBridgeUsesFamilyPackage(t *testing.T) {
+	tempRoot := t.TempDir()
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "shared-types", "package.json"), "{}", 0o644)
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "extension-chrome", "package.json"), "{}", 0o644)
+	bunPath := filepath.Join(tempRoot, "bin", "bun")
+	mustWriteFile(t, bunPath, "#!/bin/sh\necho bun\n", 0o755)
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", tempRoot)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", bunPath)
+
+	var pingedDirs []string
+	restore := setRunnerForTesting(mockCommandRunner(func(_ context.Context, dir string, _ string, _ ...string) (string, string, error) {
+		pingedDirs = append(pingedDirs, dir)
+		return `{"ok":true,"protocolVersion":"1"}`, "", nil
+	}))
+	defer restore()
+
+	if status := PingBrowserBridge(context.Background(), BrowserTargetChromeBeta); status.Status != "ready" || status.Target != "chrome-beta" {
+		t.Fatalf("expected chrome-beta bridge ready, got %+v", status)
+	}
+	if status := PingBrowserBridge(context.Background(), BrowserTargetSafariTP); status.Status != "unreachable" {
+		t.Fatalf("expected Safari Technology Preview to be unsupported, got %+v", status)
+	}
+	if status := PingBrowserBridge(context.Background(), BrowserTargetSafari); status.Status != "unreachable" || !strings.Contains(status.Detail, "package not found") {
+		t.Fatalf("expected missing safari package, got %+v", status)
+	}
+	if len(pingedDirs) != 1 || pingedDirs[0] != filepath.Join(tempRoot, "packages", "extension-chrome") {
+		t.Fatalf("expected a single ping from the chrome package, got %q", pingedDirs)
+	}
+}
+
+func TestPingBrowserBridgeNegotiatesSupportedProtocolVersions(t *testing.T) {
+	tempRoot := t.TempDir()
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "shared-types", "package.json"), "{}", 0o644)
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "extension-chrome", "package.json"), "{}", 0o644)
+	bunPath := filepath.Join(tempRoot, "bin", "bun")
+	mustWriteFile(t, bunPath, "#!/bin/sh\necho bun\n", 0o755)
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", tempRoot)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", bunPath)
+
+	pingVersion := "1"
+	restore := setRunnerForTesting(mockCommandRunner(func(_ context.Context, _ string, _ string, _ ...string) (string, string, error) {
+		return `{"ok":true,"protocolVersion":"` + pingVersion + `"}`, "", nil
+	}))
+	defer restore()
+
+	status := PingBrowserBridge(context.Background(), BrowserTargetChrome)
+	if status.Status != "ready" || status.ProtocolVersion != "1" || status.Detail != "negotiated protocol=1" {
+		t.Fatalf("expected protocol 1 to be negotiated, got %+v", status)
+	}
+
+	// The TypeScript transport still rejects protocol 2 captures, so a v2
+	// bridge must not be reported as ready.
+	pingVersion = "2"
+	status = PingBrowserBridge(context.Background(), BrowserTargetChrome)
+	if status.Status != "protocol_mismatch" || status.ProtocolVersion != "" {
+		t.Fatalf("expected protocol 2 to be rejected, got %+v", status)
+	}
+	if status.Detail != "bridge protocol=2 supported=1" {
+		t.Fatalf("unexpected mismatch detail: %q", status.Detail)
+	}
+}
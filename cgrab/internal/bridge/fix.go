@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// bunInstallCommand is the documented Bun installer one-liner. doctor --fix
+// prints it instead of piping a remote script into a shell.
+const bunInstallCommand = "curl -fsSL https://bun.sh/install | bash"
+
+// DoctorFix records one remediation considered by FixDoctorIssues. Applied
+// fixes were run by the CLI; the others are steps the user must take.
+type DoctorFix struct {
+	Issue   string `json:"issue"`
+	Action  string `json:"action"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// swiftPathFunc locates the Swift toolchain. Overridable in tests.
+var swiftPathFunc = func() (string, bool) {
+	path, err := exec.LookPath("swift")
+	return path, err == nil
+}
+
+// FixDoctorIssues applies the remediations that are safe to run unattended
+// for the problems in report and describes the rest. Today the only applied
+// fix is building the host binary from a repository checkout; a missing Bun
+// install is reported with its install command.
+func FixDoctorIssues(ctx context.Context, report DoctorReport) []DoctorFix {
+	var fixes []DoctorFix
+	if !report.HostBinaryAvailable {
+		fixes = append(fixes, fixHostBinary(ctx, report.RepoRoot))
+	}
+	if !report.BunAvailable {
+		fixes = append(fixes, DoctorFix{
+			Issue:  "bun not found",
+			Action: "install Bun: " + bunInstallCommand,
+		})
+	}
+	return fixes
+}
+
+func fixHostBinary(ctx context.Context, repoRoot string) DoctorFix {
+	fix := DoctorFix{Issue: "ContextGrabberHost binary not found"}
+	if explicit := strings.TrimSpace(os.Getenv("CONTEXT_GRABBER_HOST_BIN")); explicit != "" {
+		fix.Action = fmt.Sprintf("CONTEXT_GRABBER_HOST_BIN points to %s, which is not executable; fix or unset it", explicit)
+		return fix
+	}
+	if strings.TrimSpace(repoRoot) == "" {
+		fix.Action = "install ContextGrabber.app, set CONTEXT_GRABBER_HOST_BIN, or run from a repository checkout so the host can be built"
+		return fix
+	}
+
+	hostDir := filepath.Join(repoRoot, "apps", "macos-host")
+	swiftPath, ok := swiftPathFunc()
+	if !ok {
+		fix.Action = fmt.Sprintf("install the Xcode command line tools (xcode-select --install), then run: cd %s && swift build", hostDir)
+		return fix
+	}
+
+	fix.Action = fmt.Sprintf("ran swift build in %s", hostDir)
+	if _, stderr, err := runner.Run(ctx, hostDir, swiftPath, "build"); err != nil {
+		detail := strings.TrimSpace(stderr)
+		if detail == "" {
+			detail = err.Error()
+		}
+		fix.Error = detail
+		return fix
+	}
+	fix.Applied = true
+	return fix
+}
@@ -0,0 +1,64 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func stubSwiftPath(t *testing.T, path string, ok bool) {
+	t.Helper()
+	previous := swiftPathFunc
+	swiftPathFunc = func() (string, bool) {
+		return path, ok
+	}
+	t.Cleanup(func() {
+		swiftPathFunc = previous
+	})
+}
+
+func TestFixDoctorIssuesBuildsHostInRepoCheckout(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", "")
+	stubSwiftPath(t, "/usr/bin/swift", true)
+	repoRoot := t.TempDir()
+	var gotDir, gotName string
+	var gotArgs []string
+	restore := setRunnerForTesting(mockCommandRunner(func(_ context.Context, dir string, name string, args ...string) (string, string, error) {
+		gotDir, gotName, gotArgs = dir, name, args
+		return "", "", nil
+	}))
+	defer restore()
+
+	fixes := FixDoctorIssues(context.Background(), DoctorReport{RepoRoot: repoRoot, BunAvailable: false})
+	if len(fixes) != 2 {
+		t.Fatalf("expected host and bun fixes, got %+v", fixes)
+	}
+	if !fixes[0].Applied || gotDir != filepath.Join(repoRoot, "apps", "macos-host") || gotName != "/usr/bin/swift" || strings.Join(gotArgs, " ") != "build" {
+		t.Fatalf("expected swift build in the host package, got %+v (dir=%s name=%s args=%v)", fixes[0], gotDir, gotName, gotArgs)
+	}
+	if fixes[1].Applied || !strings.Contains(fixes[1].Action, bunInstallCommand) {
+		t.Fatalf("expected manual bun install step, got %+v", fixes[1])
+	}
+}
+
+func TestFixDoctorIssuesReportsBuildFailureAndMissingToolchain(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", "")
+	stubSwiftPath(t, "/usr/bin/swift", true)
+	restore := setRunnerForTesting(mockCommandRunner(func(context.Context, string, string, ...string) (string, string, error) {
+		return "", "error: no such module 'AppKit'\n", errors.New("exit status 1")
+	}))
+	defer restore()
+
+	fixes := FixDoctorIssues(context.Background(), DoctorReport{RepoRoot: t.TempDir(), BunAvailable: true})
+	if len(fixes) != 1 || fixes[0].Applied || fixes[0].Error != "error: no such module 'AppKit'" {
+		t.Fatalf("expected failed build fix, got %+v", fixes)
+	}
+
+	stubSwiftPath(t, "", false)
+	fixes = FixDoctorIssues(context.Background(), DoctorReport{RepoRoot: t.TempDir(), BunAvailable: true})
+	if len(fixes) != 1 || fixes[0].Applied || !strings.Contains(fixes[0].Action, "xcode-select --install") {
+		t.Fatalf("expected manual toolchain step, got %+v", fixes)
+	}
+}
@@ -6,40 +6,112 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
 
-const hostAppBundlePathEnvVar = "CONTEXT_GRABBER_APP_BUNDLE_PATH"
+const (
+	hostAppBundlePathEnvVar    = "CONTEXT_GRABBER_APP_BUNDLE_PATH"
+	hostAppLaunchTimeoutEnvVar = "CONTEXT_GRABBER_APP_LAUNCH_TIMEOUT_MS"
+)
 
 var (
 	installedHostAppBundlePath = "/Applications/ContextGrabber.app"
 	hostAppLaunchTimeout       = 4 * time.Second
+	hostAppPollInterval        = 120 * time.Millisecond
 )
 
-func EnsureHostAppRunning(ctx context.Context) (bool, error) {
+// HostAppLaunchError reports why EnsureHostAppRunning could not bring the
+// ContextGrabber app up. Timeout distinguishes "launched but never became
+// ready" from a failure to launch at all.
+type HostAppLaunchError struct {
+	Timeout bool
+	Err     error
+}
+
+func (e *HostAppLaunchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HostAppLaunchError) Unwrap() error {
+	return e.Err
+}
+
+type hostAppLaunchOptions struct {
+	timeout time.Duration
+}
+
+// HostAppLaunchOption customizes EnsureHostAppRunning.
+type HostAppLaunchOption func(*hostAppLaunchOptions)
+
+// WithLaunchTimeout overrides how long EnsureHostAppRunning waits for the app
+// to appear after launching it. It takes precedence over the
+// CONTEXT_GRABBER_APP_LAUNCH_TIMEOUT_MS environment variable.
+func WithLaunchTimeout(timeout time.Duration) HostAppLaunchOption {
+	return func(options *hostAppLaunchOptions) {
+		options.timeout = timeout
+	}
+}
+
+func EnsureHostAppRunning(ctx context.Context, opts ...HostAppLaunchOption) (bool, error) {
+	options := hostAppLaunchOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.timeout <= 0 {
+		timeout, err := resolveHostAppLaunchTimeout()
+		if err != nil {
+			return false, &HostAppLaunchError{Err: err}
+		}
+		options.timeout = timeout
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
 	if hostAppRunning(ctx) {
 		return false, nil
 	}
 
 	hostBinaryPath, hostBinaryOK := resolveHostBinaryPathForLaunch()
 	if err := launchHostApp(ctx, hostBinaryPath, hostBinaryOK); err != nil {
-		return false, err
+		return false, &HostAppLaunchError{Err: err}
 	}
 
-	deadline := time.Now().Add(hostAppLaunchTimeout)
-	for time.Now().Before(deadline) {
+	timer := time.NewTimer(options.timeout)
+	defer timer.Stop()
+	for {
 		if hostAppRunning(ctx) {
 			return true, nil
 		}
 		select {
 		case <-ctx.Done():
 			return true, ctx.Err()
-		case <-time.After(120 * time.Millisecond):
+		case <-timer.C:
+			return true, &HostAppLaunchError{
+				Timeout: true,
+				Err: fmt.Errorf(
+					"context grabber app did not become ready within %s (set %s to wait longer)",
+					options.timeout,
+					hostAppLaunchTimeoutEnvVar,
+				),
+			}
+		case <-time.After(hostAppPollInterval):
 		}
 	}
+}
 
-	return true, fmt.Errorf("context grabber app did not become ready before timeout")
+func resolveHostAppLaunchTimeout() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(hostAppLaunchTimeoutEnvVar))
+	if raw == "" {
+		return hostAppLaunchTimeout, nil
+	}
+	ms, err := strconv.Atoi(raw)
+	if err != nil || ms <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", hostAppLaunchTimeoutEnvVar, raw)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
 }
 
 func resolveHostAppBundlePath() string {
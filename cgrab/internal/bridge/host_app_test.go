@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestEnsureHostAppRunningNoopWhenAlreadyRunning(t *testing.T) {
@@ -76,3 +77,63 @@ func TestEnsureHostAppRunningLaunchesInstalledApp(t *testing.T) {
 		t.Fatalf("expected app launch command to be called")
 	}
 }
+
+func TestEnsureHostAppRunningReturnsTypedTimeoutError(t *testing.T) {
+	appBundlePath := filepath.Join(t.TempDir(), "ContextGrabber.app")
+	if err := os.MkdirAll(appBundlePath, 0o755); err != nil {
+		t.Fatalf("mkdir app bundle path failed: %v", err)
+	}
+	t.Setenv("CONTEXT_GRABBER_APP_BUNDLE_PATH", appBundlePath)
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", filepath.Join(t.TempDir(), "missing", "ContextGrabberHost"))
+
+	restore := setRunnerForTesting(mockCommandRunner(func(
+		_ context.Context,
+		_ string,
+		name string,
+		_ ...string,
+	) (string, string, error) {
+		if name == "open" {
+			return "", "", nil
+		}
+		return "", "", errors.New("process not found")
+	}))
+	defer restore()
+
+	_, err := EnsureHostAppRunning(context.Background(), WithLaunchTimeout(10*time.Millisecond))
+	var launchErr *HostAppLaunchError
+	if !errors.As(err, &launchErr) {
+		t.Fatalf("expected HostAppLaunchError, got %v", err)
+	}
+	if !launchErr.Timeout {
+		t.Fatalf("expected timeout launch error, got %v", launchErr)
+	}
+}
+
+func TestEnsureHostAppRunningRejectsInvalidTimeoutEnv(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_APP_LAUNCH_TIMEOUT_MS", "soon")
+
+	_, err := EnsureHostAppRunning(context.Background())
+	var launchErr *HostAppLaunchError
+	if !errors.As(err, &launchErr) || launchErr.Timeout {
+		t.Fatalf("expected non-timeout launch error for invalid env, got %v", err)
+	}
+}
+
+func TestEnsureHostAppRunningHonorsCanceledContextBeforeProbe(t *testing.T) {
+	restore := setRunnerForTesting(mockCommandRunner(func(
+		_ context.Context,
+		_ string,
+		_ string,
+		_ ...string,
+	) (string, string, error) {
+		t.Fatalf("expected no process probe after cancellation")
+		return "", "", nil
+	}))
+	defer restore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := EnsureHostAppRunning(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
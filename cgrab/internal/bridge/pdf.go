@@ -0,0 +1,105 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+// minPDFTimeout bounds headless rendering; page loads take far longer than
+// the extension bridge round trip that --timeout-ms is tuned for.
+const minPDFTimeout = 30 * time.Second
+
+var pdfCaptureRunner browserCaptureRunner = defaultBrowserCaptureRunner{}
+
+// chromeBinaryPathFunc locates the executable of a Chrome-family browser
+// through LaunchServices. Overridable in tests.
+var chromeBinaryPathFunc = func(ctx context.Context, app osascript.BrowserApp) (string, error) {
+	return osascript.AppExecutablePath(ctx, app.BundleID)
+}
+
+// RenderURLToPDF loads pageURL again in a headless copy of the browser and
+// saves it to a PDF at outputPath, returning an attempt whose payload holds
+// the file path. This is a re-render, not a print of the live tab: the
+// headless browser uses a throwaway profile, so cookies, logins, and page
+// state from the user's session are absent. The running browser cannot be
+// printed from without its print dialog or a DevTools port it does not
+// expose, which is also why Safari is unsupported.
+func RenderURLToPDF(
+	ctx context.Context,
+	target BrowserTarget,
+	pageURL string,
+	outputPath string,
+	timeoutMs int,
+) (BrowserCaptureAttempt, error) {
+	app, ok := osascript.LookupBrowser(string(target))
+	if !ok {
+		return BrowserCaptureAttempt{}, fmt.Errorf("unsupported browser target: %s", target)
+	}
+	if app.Family != osascript.BrowserFamilyChrome {
+		return BrowserCaptureAttempt{}, fmt.Errorf("url-pdf capture is not supported for %s (Chrome-family browsers only)", app.DisplayName)
+	}
+	pageURL = strings.TrimSpace(pageURL)
+	if pageURL == "" {
+		return BrowserCaptureAttempt{}, fmt.Errorf("url-pdf capture requires a tab with a URL")
+	}
+
+	binary, err := chromeBinaryPathFunc(ctx, app)
+	if err != nil {
+		return BrowserCaptureAttempt{}, fmt.Errorf("%s executable not found: %w", app.DisplayName, err)
+	}
+
+	// A throwaway profile keeps headless Chrome from contending with the
+	// running browser's profile lock.
+	profileDir, err := os.MkdirTemp("", "cgrab-pdf-profile-*")
+	if err != nil {
+		return BrowserCaptureAttempt{}, fmt.Errorf("create pdf profile dir: %w", err)
+	}
+	defer os.RemoveAll(profileDir)
+
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	if timeout < minPDFTimeout {
+		timeout = minPDFTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-first-run",
+		"--no-pdf-header-footer",
+		"--user-data-dir=" + profileDir,
+		"--print-to-pdf=" + outputPath,
+		pageURL,
+	}
+	stdout, stderr, runErr := pdfCaptureRunner.Run(runCtx, "", binary, args, os.Environ())
+	if runErr != nil {
+		detail := strings.TrimSpace(stderr)
+		if detail == "" {
+			detail = strings.TrimSpace(stdout)
+		}
+		if detail == "" {
+			detail = runErr.Error()
+		}
+		return BrowserCaptureAttempt{}, fmt.Errorf("url-pdf capture failed for %s: %s", target, detail)
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil || info.Size() == 0 {
+		return BrowserCaptureAttempt{}, fmt.Errorf("url-pdf capture for %s produced no file at %s", target, outputPath)
+	}
+
+	return BrowserCaptureAttempt{
+		ExtractionMethod: "url_pdf",
+		Warnings:         []string{},
+		Payload: map[string]any{
+			"path":      outputPath,
+			"url":       pageURL,
+			"sizeBytes": info.Size(),
+		},
+	}, nil
+}
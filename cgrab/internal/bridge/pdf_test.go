@@ -0,0 +1,57 @@
+package bridge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+func TestRenderURLToPDFRunsHeadlessChromeChannel(t *testing.T) {
+	tempDir := t.TempDir()
+	binary := filepath.Join(tempDir, "Google Chrome Beta")
+	mustWriteExecutableFile(t, binary, "#!/bin/sh\n")
+	previousPath := chromeBinaryPathFunc
+	chromeBinaryPathFunc = func(_ context.Context, app osascript.BrowserApp) (string, error) {
+		if app.BundleID != "com.google.Chrome.beta" {
+			t.Fatalf("expected Chrome Beta bundle, got %q", app.BundleID)
+		}
+		return binary, nil
+	}
+	defer func() { chromeBinaryPathFunc = previousPath }()
+
+	var gotArgs []string
+	previousRunner := pdfCaptureRunner
+	pdfCaptureRunner = mockBunRunner(func(_ context.Context, _ string, name string, args []string, _ []string) (string, string, error) {
+		gotArgs = args
+		for _, arg := range args {
+			if path, ok := strings.CutPrefix(arg, "--print-to-pdf="); ok {
+				return "", "", os.WriteFile(path, []byte("%PDF-1.7"), 0o644)
+			}
+		}
+		return "", "", nil
+	})
+	defer func() { pdfCaptureRunner = previousRunner }()
+
+	outputPath := filepath.Join(tempDir, "page.pdf")
+	attempt, err := RenderURLToPDF(context.Background(), BrowserTargetChromeBeta, "https://example.com", outputPath, 1200)
+	if err != nil {
+		t.Fatalf("RenderURLToPDF returned error: %v", err)
+	}
+	if attempt.ExtractionMethod != "url_pdf" || attempt.Payload["path"] != outputPath {
+		t.Fatalf("unexpected attempt: %#v", attempt)
+	}
+	if gotArgs[len(gotArgs)-1] != "https://example.com" || gotArgs[0] != "--headless" {
+		t.Fatalf("unexpected headless args: %v", gotArgs)
+	}
+}
+
+func TestRenderURLToPDFRejectsSafari(t *testing.T) {
+	_, err := RenderURLToPDF(context.Background(), BrowserTargetSafari, "https://example.com", filepath.Join(t.TempDir(), "x.pdf"), 1200)
+	if err == nil || !strings.Contains(err.Error(), "Chrome-family") {
+		t.Fatalf("expected Safari to be rejected, got %v", err)
+	}
+}
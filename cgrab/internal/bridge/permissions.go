@@ -0,0 +1,95 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+const accessibilityProbeScript = `tell application "System Events" to count (UI elements of (first application process whose frontmost is true))`
+
+// accessibilityPermissionMarkers are substrings of the osascript errors
+// macOS returns when the calling process lacks Accessibility permission.
+var accessibilityPermissionMarkers = []string{
+	"not allowed assistive access",
+	"(-1719)",
+	"(-25211)",
+}
+
+// automationPermissionMarkers are substrings of the osascript errors macOS
+// returns when the user declined the Automation (Apple Events) prompt.
+var automationPermissionMarkers = []string{
+	"not authorized to send apple events",
+	"(-1743)",
+}
+
+// Automation permission states reported per browser target by doctor.
+const (
+	AutomationGranted    = "granted"
+	AutomationDenied     = "denied"
+	AutomationNotRunning = "not_running"
+	// AutomationUnknown is reported when the probe failed for a reason other
+	// than a declined permission.
+	AutomationUnknown = "unknown"
+)
+
+// automationProbeScript asks a running browser for its window count. The
+// application name is held in a variable so the script compiles even when
+// the browser is not installed; a browser that is not running is not
+// launched.
+const automationProbeScript = `set appName to %q
+if application appName is running then
+	tell application appName to count windows
+	return "running"
+end if
+return "not_running"`
+
+// probeAccessibilityPermission runs a trivial System Events UI query and
+// reports false only when it fails with a characteristic permission error.
+func probeAccessibilityPermission(ctx context.Context, osaPath string) bool {
+	stdout, stderr, err := runner.Run(ctx, "", osaPath, "-e", accessibilityProbeScript)
+	if err == nil {
+		return true
+	}
+	return !containsAnyFold(stderr+"\n"+stdout, accessibilityPermissionMarkers)
+}
+
+// probeAutomationPermissions sends a harmless Apple Event to each browser in
+// the osascript registry (honoring app-name overrides) and reports its
+// Automation state by target. Browsers that are not running are not
+// launched and are reported as AutomationNotRunning, since nothing was
+// checked for them.
+func probeAutomationPermissions(ctx context.Context, osaPath string) map[string]string {
+	targets := osascript.BrowserTargets()
+	statuses := make(map[string]string, len(targets))
+	for _, target := range targets {
+		app, ok := osascript.LookupBrowser(target)
+		if !ok {
+			continue
+		}
+		stdout, stderr, err := runner.Run(ctx, "", osaPath, "-e", fmt.Sprintf(automationProbeScript, app.AppName))
+		switch {
+		case err == nil && strings.TrimSpace(stdout) == AutomationNotRunning:
+			statuses[target] = AutomationNotRunning
+		case err == nil:
+			statuses[target] = AutomationGranted
+		case containsAnyFold(stderr+"\n"+stdout, automationPermissionMarkers):
+			statuses[target] = AutomationDenied
+		default:
+			statuses[target] = AutomationUnknown
+		}
+	}
+	return statuses
+}
+
+func containsAnyFold(haystack string, needles []string) bool {
+	lowered := strings.ToLower(haystack)
+	for _, needle := range needles {
+		if strings.Contains(lowered, strings.ToLower(needle)) {
+			return true
+		}
+	}
+	return false
+}
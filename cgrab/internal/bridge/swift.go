@@ -3,8 +3,11 @@ package bridge
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -28,6 +31,34 @@ type DesktopCaptureRequest struct {
 	BundleIdentifier string
 	Method           DesktopCaptureMethod
 	Format           DesktopCaptureFormat
+	// IncludeBounds asks the host to add the captured window's geometry
+	// (windowBounds) to JSON output.
+	IncludeBounds bool
+	// WindowIndex, when positive, asks the host to raise the app's Nth
+	// window (1-based, front to back) before capturing.
+	WindowIndex int
+}
+
+// WindowBounds is the captured window's frame in screen points, as reported
+// by the host with --include-bounds.
+type WindowBounds struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// DesktopCaptureWindowBounds reads windowBounds from JSON desktop capture
+// output. It returns nil when the host did not report bounds, e.g. when no
+// window was on screen or the host predates --include-bounds.
+func DesktopCaptureWindowBounds(rendered []byte) (*WindowBounds, error) {
+	var decoded struct {
+		WindowBounds *WindowBounds `json:"windowBounds"`
+	}
+	if err := json.Unmarshal(rendered, &decoded); err != nil {
+		return nil, fmt.Errorf("decode desktop capture output: %w", err)
+	}
+	return decoded.WindowBounds, nil
 }
 
 type desktopCaptureRunner interface {
@@ -41,6 +72,7 @@ func (defaultDesktopCaptureRunner) Run(
 	name string,
 	args []string,
 ) (string, string, error) {
+	logCommand("", name, args)
 	cmd := exec.CommandContext(ctx, name, args...)
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -60,6 +92,12 @@ func setSwiftCaptureRunnerForTesting(mock desktopCaptureRunner) func() {
 	}
 }
 
+// ErrHostBinaryNotFound is returned by CaptureDesktop when no
+// ContextGrabberHost binary can be resolved.
+var ErrHostBinaryNotFound = errors.New(
+	"ContextGrabberHost binary not found; build apps/macos-host, install ContextGrabber.app, or set CONTEXT_GRABBER_HOST_BIN",
+)
+
 func CaptureDesktop(ctx context.Context, request DesktopCaptureRequest) ([]byte, error) {
 	if request.Method == "" {
 		request.Method = DesktopCaptureMethodAuto
@@ -78,17 +116,21 @@ func CaptureDesktop(ctx context.Context, request DesktopCaptureRequest) ([]byte,
 	default:
 		return nil, fmt.Errorf("unsupported desktop capture format: %s", request.Format)
 	}
+	if request.IncludeBounds && request.Format != DesktopCaptureFormatJSON {
+		return nil, fmt.Errorf("desktop capture window bounds require JSON format")
+	}
 	if strings.TrimSpace(request.AppName) == "" && strings.TrimSpace(request.BundleIdentifier) == "" {
 		return nil, fmt.Errorf("desktop capture requires app name or bundle identifier")
 	}
+	if request.WindowIndex < 0 {
+		return nil, fmt.Errorf("desktop capture window index must be positive")
+	}
 
 	repoRoot, _ := resolveRepoRoot()
 
 	hostBinaryPath, hostBinaryOK := resolveHostBinaryPath(repoRoot)
 	if !hostBinaryOK {
-		return nil, fmt.Errorf(
-			"ContextGrabberHost binary not found; build apps/macos-host, install ContextGrabber.app, or set CONTEXT_GRABBER_HOST_BIN",
-		)
+		return nil, ErrHostBinaryNotFound
 	}
 
 	args := []string{"--capture"}
@@ -100,6 +142,12 @@ func CaptureDesktop(ctx context.Context, request DesktopCaptureRequest) ([]byte,
 	}
 	args = append(args, "--method", string(request.Method))
 	args = append(args, "--format", string(request.Format))
+	if request.IncludeBounds {
+		args = append(args, "--include-bounds")
+	}
+	if request.WindowIndex > 0 {
+		args = append(args, "--window-index", strconv.Itoa(request.WindowIndex))
+	}
 
 	stdout, stderr, runErr := swiftCaptureRunner.Run(ctx, hostBinaryPath, args)
 	if runErr != nil {
@@ -66,6 +66,75 @@ func TestCaptureDesktopBuildsExpectedHostCommand(t *testing.T) {
 	}
 }
 
+func TestCaptureDesktopPassesIncludeBounds(t *testing.T) {
+	hostPath := filepath.Join(t.TempDir(), "ContextGrabberHost")
+	if err := os.WriteFile(hostPath, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write host binary failed: %v", err)
+	}
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", hostPath)
+
+	var capturedArgs []string
+	restore := setSwiftCaptureRunnerForTesting(mockDesktopRunner(func(_ context.Context, _ string, args []string) (string, string, error) {
+		capturedArgs = append([]string{}, args...)
+		return `{"markdown":"# Finder","windowBounds":{"x":10,"y":20,"width":800,"height":600}}`, "", nil
+	}))
+	defer restore()
+
+	output, err := CaptureDesktop(context.Background(), DesktopCaptureRequest{
+		AppName:       "Finder",
+		Format:        DesktopCaptureFormatJSON,
+		IncludeBounds: true,
+	})
+	if err != nil {
+		t.Fatalf("CaptureDesktop returned error: %v", err)
+	}
+	if !strings.Contains(strings.Join(capturedArgs, " "), "--include-bounds") {
+		t.Fatalf("expected --include-bounds in host args, got %v", capturedArgs)
+	}
+	bounds, err := DesktopCaptureWindowBounds(output)
+	if err != nil || bounds == nil || *bounds != (WindowBounds{X: 10, Y: 20, Width: 800, Height: 600}) {
+		t.Fatalf("unexpected window bounds %+v err=%v", bounds, err)
+	}
+
+	if _, err := CaptureDesktop(context.Background(), DesktopCaptureRequest{
+		AppName:       "Finder",
+		Format:        DesktopCaptureFormatMarkdown,
+		IncludeBounds: true,
+	}); err == nil {
+		t.Fatalf("expected markdown output with bounds to be rejected")
+	}
+}
+
+func TestCaptureDesktopPassesWindowIndex(t *testing.T) {
+	hostPath := filepath.Join(t.TempDir(), "ContextGrabberHost")
+	if err := os.WriteFile(hostPath, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write host binary failed: %v", err)
+	}
+	t.Setenv("CONTEXT_GRABBER_HOST_BIN", hostPath)
+
+	var capturedArgs []string
+	restore := setSwiftCaptureRunnerForTesting(mockDesktopRunner(func(_ context.Context, _ string, args []string) (string, string, error) {
+		capturedArgs = append([]string{}, args...)
+		return "# Finder", "", nil
+	}))
+	defer restore()
+
+	if _, err := CaptureDesktop(context.Background(), DesktopCaptureRequest{AppName: "Finder", WindowIndex: 2}); err != nil {
+		t.Fatalf("CaptureDesktop returned error: %v", err)
+	}
+	if !strings.Contains(strings.Join(capturedArgs, " "), "--window-index 2") {
+		t.Fatalf("expected --window-index 2 in host args, got %v", capturedArgs)
+	}
+
+	capturedArgs = nil
+	if _, err := CaptureDesktop(context.Background(), DesktopCaptureRequest{AppName: "Finder", WindowIndex: -1}); err == nil {
+		t.Fatalf("expected a negative window index to be rejected")
+	}
+	if capturedArgs != nil {
+		t.Fatalf("expected the host not to run for an invalid window index")
+	}
+}
+
 func TestCaptureDesktopRejectsMissingTarget(t *testing.T) {
 	_, err := CaptureDesktop(context.Background(), DesktopCaptureRequest{
 		Method: DesktopCaptureMethodAuto,
@@ -0,0 +1,86 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/osascript"
+)
+
+type listTabsResponse struct {
+	Tabs []struct {
+		WindowIndex int       `json:"windowIndex"`
+		TabIndex    int       `json:"tabIndex"`
+		IsActive    bool      `json:"isActive"`
+		Title       string    `json:"title"`
+		URL         string    `json:"url"`
+		IsPinned    bool      `json:"isPinned"`
+		IsAudible   bool      `json:"isAudible"`
+		OpenedAt    time.Time `json:"openedAt"`
+	} `json:"tabs"`
+	Payload struct {
+		Message string `json:"message"`
+	} `json:"payload"`
+}
+
+// ListTabs asks target's extension bridge for the browser's open tabs via
+// the native-messaging CLI's --list-tabs, without AppleScript. Unlike
+// osascript.ListTabs it reports audible tabs and, when the extension saw
+// the tab open, OpenedAt.
+func ListTabs(ctx context.Context, target BrowserTarget) ([]osascript.TabEntry, error) {
+	app, packagePath, err := bridgePackageForTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	repoRoot, err := resolveRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("repository root not resolved: %w", err)
+	}
+	bunPath, bunOK := resolveBunPath()
+	if !bunOK {
+		return nil, fmt.Errorf("bun not available")
+	}
+	packageDir := filepath.Join(repoRoot, packagePath)
+	if _, err := os.Stat(filepath.Join(packageDir, "package.json")); err != nil {
+		return nil, fmt.Errorf("package not found: %s", packagePath)
+	}
+
+	stdout, stderr, runErr := runner.Run(ctx, packageDir, bunPath, "src/native-messaging-cli.ts", "--list-tabs")
+	var response listTabsResponse
+	parseErr := json.Unmarshal([]byte(strings.TrimSpace(stdout)), &response)
+	if runErr != nil {
+		message := strings.TrimSpace(response.Payload.Message)
+		if message == "" {
+			message = strings.TrimSpace(stderr)
+		}
+		if message == "" {
+			message = runErr.Error()
+		}
+		return nil, fmt.Errorf("%s tab listing failed: %s", app.DisplayName, message)
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("invalid %s tab listing: %w", app.DisplayName, parseErr)
+	}
+
+	tabs := make([]osascript.TabEntry, 0, len(response.Tabs))
+	for _, tab := range response.Tabs {
+		tabs = append(tabs, osascript.TabEntry{
+			Browser:         app.Target,
+			BrowserBundleID: app.BundleID,
+			WindowIndex:     tab.WindowIndex,
+			TabIndex:        tab.TabIndex,
+			IsActive:        tab.IsActive,
+			Title:           tab.Title,
+			URL:             tab.URL,
+			IsPinned:        tab.IsPinned,
+			IsAudible:       tab.IsAudible,
+			OpenedAt:        tab.OpenedAt,
+		})
+	}
+	return tabs, nil
+}
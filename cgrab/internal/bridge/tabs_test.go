@@ -0,0 +1,70 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListTabsParsesBridgeTabs(t *testing.T) {
+	tempRoot := t.TempDir()
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "shared-types", "package.json"), "{}", 0o644)
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "extension-chrome", "package.json"), "{}", 0o644)
+	bunPath := filepath.Join(tempRoot, "bin", "bun")
+	mustWriteFile(t, bunPath, "#!/bin/sh\necho bun\n", 0o755)
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", tempRoot)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", bunPath)
+
+	var capturedDir string
+	var capturedArgs []string
+	restore := setRunnerForTesting(mockCommandRunner(func(_ context.Context, dir string, _ string, args ...string) (string, string, error) {
+		capturedDir = dir
+		capturedArgs = args
+		return `{"tabs":[{"windowIndex":1,"tabIndex":2,"isActive":true,"title":"Docs","url":"https://example.com/docs","isAudible":true,"openedAt":"2026-02-15T12:00:00.000Z"}]}`, "", nil
+	}))
+	defer restore()
+
+	tabs, err := ListTabs(context.Background(), BrowserTargetChromeBeta)
+	if err != nil {
+		t.Fatalf("ListTabs returned error: %v", err)
+	}
+	if capturedDir != filepath.Join(tempRoot, "packages", "extension-chrome") || strings.Join(capturedArgs, " ") != "src/native-messaging-cli.ts --list-tabs" {
+		t.Fatalf("unexpected bridge invocation: dir=%q args=%q", capturedDir, capturedArgs)
+	}
+	if len(tabs) != 1 {
+		t.Fatalf("expected one tab, got %+v", tabs)
+	}
+	tab := tabs[0]
+	if tab.Browser != "chrome-beta" || tab.WindowIndex != 1 || tab.TabIndex != 2 || !tab.IsActive || !tab.IsAudible {
+		t.Fatalf("unexpected tab: %+v", tab)
+	}
+	if !tab.OpenedAt.Equal(time.Date(2026, time.February, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected openedAt: %v", tab.OpenedAt)
+	}
+}
+
+func TestListTabsReportsBridgeErrorMessage(t *testing.T) {
+	tempRoot := t.TempDir()
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "shared-types", "package.json"), "{}", 0o644)
+	mustWriteFile(t, filepath.Join(tempRoot, "packages", "extension-safari", "package.json"), "{}", 0o644)
+	bunPath := filepath.Join(tempRoot, "bin", "bun")
+	mustWriteFile(t, bunPath, "#!/bin/sh\necho bun\n", 0o755)
+	t.Setenv("CONTEXT_GRABBER_REPO_ROOT", tempRoot)
+	t.Setenv("CONTEXT_GRABBER_BUN_BIN", bunPath)
+
+	restore := setRunnerForTesting(mockCommandRunner(func(context.Context, string, string, ...string) (string, string, error) {
+		return `{"type":"extension.error","payload":{"message":"Tab listing requires CONTEXT_GRABBER_SAFARI_RUNTIME_TABS."}}`, "", errors.New("exit status 1")
+	}))
+	defer restore()
+
+	_, err := ListTabs(context.Background(), BrowserTargetSafari)
+	if err == nil || err.Error() != "Safari tab listing failed: Tab listing requires CONTEXT_GRABBER_SAFARI_RUNTIME_TABS." {
+		t.Fatalf("expected bridge error message, got %v", err)
+	}
+	if _, err := ListTabs(context.Background(), BrowserTargetSafariTP); err == nil || !strings.Contains(err.Error(), "not supported by the Safari extension bridge") {
+		t.Fatalf("expected Safari Technology Preview to be unsupported, got %v", err)
+	}
+}
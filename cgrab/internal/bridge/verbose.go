@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var verboseLog io.Writer
+
+// SetVerboseLog makes the bridge runners log every command they execute to w.
+// A nil writer disables logging.
+func SetVerboseLog(w io.Writer) {
+	verboseLog = w
+}
+
+// redactedFlags take values that can carry page titles or URLs, which are
+// left out of verbose logs.
+var redactedFlags = map[string]bool{
+	"--title":     true,
+	"--url":       true,
+	"--site-name": true,
+}
+
+func logCommand(dir string, name string, args []string) {
+	if verboseLog == nil {
+		return
+	}
+	line := "verbose: exec " + describeCommand(name, args)
+	if dir != "" {
+		line += " (dir " + dir + ")"
+	}
+	fmt.Fprintln(verboseLog, line)
+}
+
+func describeCommand(name string, args []string) string {
+	parts := []string{quoteArg(name)}
+	for i := 0; i < len(args); i++ {
+		parts = append(parts, quoteArg(args[i]))
+		if i+1 >= len(args) {
+			continue
+		}
+		switch {
+		case args[i] == "-e":
+			parts = append(parts, summarizeScript(args[i+1]))
+			i++
+		case redactedFlags[args[i]]:
+			parts = append(parts, "<redacted>")
+			i++
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func summarizeScript(script string) string {
+	return fmt.Sprintf("<script %d lines>", len(strings.Split(strings.TrimSpace(script), "\n")))
+}
+
+func quoteArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// settingKey maps a stable CLI key name onto a Settings field. Setters route
+// through the same normalizers used by LoadSettings/SaveSettings.
+type settingKey struct {
+	name string
+	get  func(Settings) string
+	set  func(*Settings, string) error
+}
+
+var settingKeys = []settingKey{
+	{
+		name: "output-subdir",
+		get: func(settings Settings) string {
+			return settings.CaptureOutputSubdir
+		},
+		set: func(settings *Settings, value string) error {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("output subdirectory cannot be empty")
+			}
+			cleaned, err := normalizeCaptureSubdir(value)
+			if err != nil {
+				return err
+			}
+			settings.CaptureOutputSubdir = cleaned
+			return nil
+		},
+	},
+	{
+		name: "skill-root",
+		get: func(settings Settings) string {
+			return settings.SkillRoot
+		},
+		set: func(settings *Settings, value string) error {
+			cleaned, err := normalizeSkillRoot(value)
+			if err != nil {
+				return err
+			}
+			settings.SkillRoot = cleaned
+			return nil
+		},
+	},
+	{
+		name: "target-order",
+		get: func(settings Settings) string {
+			return settings.FocusedTargetOrder
+		},
+		set: func(settings *Settings, value string) error {
+			settings.FocusedTargetOrder = NormalizeTargetOrder(value)
+			return nil
+		},
+	},
+	{
+		name: "min-content",
+		get: func(settings Settings) string {
+			return FormatMinContentByMethod(settings.MinContentByMethod)
+		},
+		set: func(settings *Settings, value string) error {
+			parsed, err := parseMinContentByMethod(value)
+			if err != nil {
+				return err
+			}
+			cleaned, err := normalizeMinContentByMethod(parsed)
+			if err != nil {
+				return err
+			}
+			settings.MinContentByMethod = cleaned
+			return nil
+		},
+	},
+}
+
+// parseMinContentByMethod parses "browser_extension=200,applescript_dom=50";
+// an empty value clears every threshold.
+func parseMinContentByMethod(value string) (map[string]int, error) {
+	parsed := map[string]int{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		method, rawLength, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid minimum content entry %q (expected <method>=<characters>)", part)
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(rawLength))
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum content length in %q: %w", part, err)
+		}
+		parsed[strings.TrimSpace(method)] = length
+	}
+	return parsed, nil
+}
+
+// FormatMinContentByMethod renders thresholds in the parseMinContentByMethod
+// form, sorted by method.
+func FormatMinContentByMethod(thresholds map[string]int) string {
+	methods := make([]string, 0, len(thresholds))
+	for method := range thresholds {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	parts := make([]string, 0, len(methods))
+	for _, method := range methods {
+		parts = append(parts, fmt.Sprintf("%s=%d", method, thresholds[method]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// SettingKeys returns the key names accepted by GetSetting and SetSetting.
+func SettingKeys() []string {
+	names := make([]string, 0, len(settingKeys))
+	for _, key := range settingKeys {
+		names = append(names, key.name)
+	}
+	return names
+}
+
+// GetSetting returns the value stored under key.
+func GetSetting(settings Settings, key string) (string, error) {
+	entry, err := lookupSettingKey(key)
+	if err != nil {
+		return "", err
+	}
+	return entry.get(settings), nil
+}
+
+// SetSetting validates value and stores it under key.
+func SetSetting(settings *Settings, key string, value string) error {
+	entry, err := lookupSettingKey(key)
+	if err != nil {
+		return err
+	}
+	return entry.set(settings, value)
+}
+
+func lookupSettingKey(key string) (settingKey, error) {
+	normalized := strings.ToLower(strings.TrimSpace(key))
+	for _, entry := range settingKeys {
+		if entry.name == normalized {
+			return entry, nil
+		}
+	}
+	return settingKey{}, fmt.Errorf(
+		"unknown config key %q (expected one of: %s)",
+		key,
+		strings.Join(SettingKeys(), ", "),
+	)
+}
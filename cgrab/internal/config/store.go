@@ -1,10 +1,12 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -17,8 +19,22 @@ const (
 
 type Settings struct {
 	CaptureOutputSubdir string `json:"captureOutputSubdir"`
+	// SkillRoot relocates the canonical global skill directory; empty uses
+	// ~/.agents/skills/context-grabber.
+	SkillRoot string `json:"skillRoot,omitempty"`
+	// FocusedTargetOrder is the comma-separated browser order tried by
+	// capture --focused; empty uses the built-in Safari-then-Chrome order.
+	FocusedTargetOrder string `json:"focusedTargetOrder,omitempty"`
+	// MinContentByMethod maps a browser extraction method (e.g.
+	// "browser_extension") to the minimum captured characters accepted from
+	// it, used when capture runs without --min-content-length.
+	MinContentByMethod map[string]int `json:"minContentByMethod,omitempty"`
 }
 
+// MinContentMethods are the extraction methods MinContentByMethod accepts:
+// those whose captures the minimum content check applies to.
+var MinContentMethods = []string{"browser_extension", "selection", "applescript_dom"}
+
 func DefaultSettings() Settings {
 	return Settings{
 		CaptureOutputSubdir: defaultCaptureSubdir,
@@ -63,10 +79,43 @@ func LoadSettings() (Settings, error) {
 	if err := json.Unmarshal(raw, &settings); err != nil {
 		return Settings{}, fmt.Errorf("decode config file: %w", err)
 	}
+	return normalizeSettings(settings)
+}
+
+// ParseSettings decodes settings exported by ExportSettings (or a config
+// file) for import. Unknown fields are rejected so a typo is not silently
+// dropped, and values go through the same normalizers as LoadSettings.
+func ParseSettings(raw []byte) (Settings, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	settings := DefaultSettings()
+	if err := decoder.Decode(&settings); err != nil {
+		return Settings{}, fmt.Errorf("decode settings: %w", err)
+	}
+	return normalizeSettings(settings)
+}
+
+// ExportSettings encodes settings in the config file format.
+func ExportSettings(settings Settings) ([]byte, error) {
+	payload, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	return append(payload, '\n'), nil
+}
+
+func normalizeSettings(settings Settings) (Settings, error) {
+	var err error
 	if settings.CaptureOutputSubdir, err = normalizeCaptureSubdir(settings.CaptureOutputSubdir); err != nil {
 		return Settings{}, err
 	}
-
+	if settings.SkillRoot, err = normalizeSkillRoot(settings.SkillRoot); err != nil {
+		return Settings{}, err
+	}
+	settings.FocusedTargetOrder = NormalizeTargetOrder(settings.FocusedTargetOrder)
+	if settings.MinContentByMethod, err = normalizeMinContentByMethod(settings.MinContentByMethod); err != nil {
+		return Settings{}, err
+	}
 	return settings, nil
 }
 
@@ -75,22 +124,20 @@ func SaveSettings(settings Settings) error {
 	if err != nil {
 		return err
 	}
-	cleanSubdir, err := normalizeCaptureSubdir(settings.CaptureOutputSubdir)
-	if err != nil {
+	if settings, err = normalizeSettings(settings); err != nil {
 		return err
 	}
-	settings.CaptureOutputSubdir = cleanSubdir
 
 	if err := os.MkdirAll(baseDir, 0o755); err != nil {
 		return fmt.Errorf("create base config directory: %w", err)
 	}
 	configFilePath := ResolveConfigFilePath(baseDir)
-	payload, err := json.MarshalIndent(settings, "", "  ")
+	payload, err := ExportSettings(settings)
 	if err != nil {
-		return fmt.Errorf("encode config: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(configFilePath, append(payload, '\n'), 0o644); err != nil {
+	if err := os.WriteFile(configFilePath, payload, 0o644); err != nil {
 		return fmt.Errorf("write config file: %w", err)
 	}
 	return nil
@@ -128,6 +175,62 @@ func EnsureBaseLayout(settings Settings) (baseDir string, captureDir string, err
 	return baseDir, captureDir, nil
 }
 
+// NormalizeTargetOrder returns a comma-separated list of browser targets
+// (e.g. "Chrome, safari") lowercased without blanks or duplicates. It does
+// not check the names against the browser registry; callers that accept the
+// list from a user do.
+func NormalizeTargetOrder(raw string) string {
+	var targets []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		value := strings.ToLower(strings.TrimSpace(part))
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		targets = append(targets, value)
+	}
+	return strings.Join(targets, ",")
+}
+
+// normalizeMinContentByMethod lowercases the method names and rejects
+// unknown methods and negative lengths. An empty map becomes nil so it is
+// omitted from the config file.
+func normalizeMinContentByMethod(raw map[string]int) (map[string]int, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string]int, len(raw))
+	for method, length := range raw {
+		key := strings.ToLower(strings.TrimSpace(method))
+		if !slices.Contains(MinContentMethods, key) {
+			return nil, fmt.Errorf(
+				"unsupported extraction method %q in minimum content lengths (expected one of: %s)",
+				method,
+				strings.Join(MinContentMethods, ", "),
+			)
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("minimum content length for %s cannot be negative", key)
+		}
+		normalized[key] = length
+	}
+	return normalized, nil
+}
+
+// normalizeSkillRoot accepts an absolute path or a ~/ path; empty keeps the
+// default skill root.
+func normalizeSkillRoot(raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return "", nil
+	}
+	if value != "~" && !strings.HasPrefix(value, "~/") && !filepath.IsAbs(value) {
+		return "", fmt.Errorf("skill root must be an absolute path or start with ~/, got %q", raw)
+	}
+	return filepath.Clean(value), nil
+}
+
 func normalizeCaptureSubdir(raw string) (string, error) {
 	value := strings.TrimSpace(raw)
 	if value == "" {
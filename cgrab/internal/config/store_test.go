@@ -2,6 +2,7 @@ package config
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -56,3 +57,91 @@ func TestNormalizeCaptureSubdirRejectsParentTraversal(t *testing.T) {
 		t.Fatalf("expected traversal path to be rejected")
 	}
 }
+
+func TestSetSettingRoutesThroughNormalizer(t *testing.T) {
+	settings := DefaultSettings()
+	if err := SetSetting(&settings, "output-subdir", " projects/./client-b "); err != nil {
+		t.Fatalf("SetSetting returned error: %v", err)
+	}
+	value, err := GetSetting(settings, "output-subdir")
+	if err != nil {
+		t.Fatalf("GetSetting returned error: %v", err)
+	}
+	if value != filepath.Join("projects", "client-b") {
+		t.Fatalf("unexpected output-subdir value: %q", value)
+	}
+	if err := SetSetting(&settings, "output-subdir", "../outside"); err == nil {
+		t.Fatalf("expected traversal path to be rejected")
+	}
+}
+
+func TestGetSettingRejectsUnknownKey(t *testing.T) {
+	_, err := GetSetting(DefaultSettings(), "nope")
+	if err == nil {
+		t.Fatalf("expected error for unknown key")
+	}
+	if !strings.Contains(err.Error(), "output-subdir") {
+		t.Fatalf("expected error to list valid keys, got %v", err)
+	}
+}
+
+func TestSetSettingSkillRootRequiresAbsolutePath(t *testing.T) {
+	settings := DefaultSettings()
+	if err := SetSetting(&settings, "skill-root", "~/dotfiles/skills/./context-grabber"); err != nil {
+		t.Fatalf("SetSetting returned error: %v", err)
+	}
+	if settings.SkillRoot != "~/dotfiles/skills/context-grabber" {
+		t.Fatalf("unexpected skill-root value: %q", settings.SkillRoot)
+	}
+	if err := SetSetting(&settings, "skill-root", "relative/skills"); err == nil {
+		t.Fatalf("expected relative skill root to be rejected")
+	}
+	if err := SetSetting(&settings, "skill-root", ""); err != nil || settings.SkillRoot != "" {
+		t.Fatalf("expected empty value to reset skill-root, got %q (%v)", settings.SkillRoot, err)
+	}
+}
+
+func TestSetSettingTargetOrderNormalizesList(t *testing.T) {
+	settings := Settings{}
+	if err := SetSetting(&settings, "target-order", "Chrome, safari,, chrome"); err != nil {
+		t.Fatalf("SetSetting returned error: %v", err)
+	}
+	if settings.FocusedTargetOrder != "chrome,safari" {
+		t.Fatalf("unexpected target-order value: %q", settings.FocusedTargetOrder)
+	}
+}
+
+func TestSetSettingMinContentValidatesMethods(t *testing.T) {
+	settings := Settings{}
+	if err := SetSetting(&settings, "min-content", "Browser_Extension=200, applescript_dom=0"); err != nil {
+		t.Fatalf("SetSetting returned error: %v", err)
+	}
+	if value, _ := GetSetting(settings, "min-content"); value != "applescript_dom=0,browser_extension=200" {
+		t.Fatalf("unexpected min-content value: %q", value)
+	}
+	for _, value := range []string{"metadata_only=10", "browser_extension=-1", "browser_extension"} {
+		if err := SetSetting(&settings, "min-content", value); err == nil {
+			t.Fatalf("expected %q to be rejected", value)
+		}
+	}
+	if err := SetSetting(&settings, "min-content", ""); err != nil || settings.MinContentByMethod != nil {
+		t.Fatalf("expected empty value to clear thresholds, got %v (%v)", settings.MinContentByMethod, err)
+	}
+}
+
+func TestParseSettingsRejectsUnknownFieldsAndNormalizes(t *testing.T) {
+	settings, err := ParseSettings([]byte(`{"captureOutputSubdir": " projects//a/ ", "focusedTargetOrder": "Chrome, safari"}`))
+	if err != nil {
+		t.Fatalf("ParseSettings returned error: %v", err)
+	}
+	if settings.CaptureOutputSubdir != filepath.Join("projects", "a") || settings.FocusedTargetOrder != "chrome,safari" {
+		t.Fatalf("expected normalized settings, got %+v", settings)
+	}
+
+	if _, err := ParseSettings([]byte(`{"skilRoot": "/tmp"}`)); err == nil {
+		t.Fatalf("expected unknown field to be rejected")
+	}
+	if _, err := ParseSettings([]byte(`{}`)); err != nil {
+		t.Fatalf("expected empty settings to take defaults, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Package eventlog appends structured CLI events to a JSON Lines file.
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Logger appends one JSON object per event to a file. The file is opened for
+// each event so long-running sessions survive log rotation. A nil *Logger
+// discards events.
+type Logger struct {
+	path string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+// New returns a Logger writing to path. The parent directory is created on
+// first write.
+func New(path string) *Logger {
+	return &Logger{path: path, now: time.Now}
+}
+
+// Log records event with the given fields. Reserved keys "time" and "event"
+// in fields are overwritten. Write failures are returned but callers are
+// expected to treat logging as best-effort.
+func (l *Logger) Log(event string, fields map[string]any) error {
+	if l == nil {
+		return nil
+	}
+
+	record := make(map[string]any, len(fields)+2)
+	for key, value := range fields {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		record[key] = value
+	}
+	record["time"] = l.now().UTC().Format(time.RFC3339Nano)
+	record["event"] = event
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode log event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write log file: %w", err)
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the Logger stored in ctx, or nil.
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return nil
+	}
+	logger, _ := ctx.Value(contextKey{}).(*Logger)
+	return logger
+}
+
+// Emit logs event to the Logger stored in ctx, if any, ignoring write errors.
+func Emit(ctx context.Context, event string, fields map[string]any) {
+	_ = FromContext(ctx).Log(event, fields)
+}
@@ -0,0 +1,50 @@
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "cgrab.jsonl")
+	logger := New(path)
+	logger.now = func() time.Time {
+		return time.Date(2026, time.February, 15, 13, 30, 45, 0, time.UTC)
+	}
+
+	if err := logger.Log("capture_start", map[string]any{"mode": "browser"}); err != nil {
+		t.Fatalf("Log returned error: %v", err)
+	}
+	if err := logger.Log("capture_end", map[string]any{"error": errors.New("bridge unreachable")}); err != nil {
+		t.Fatalf("Log returned error: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %q", len(lines), string(raw))
+	}
+	var second map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[1], err)
+	}
+	if second["event"] != "capture_end" || second["error"] != "bridge unreachable" || second["time"] != "2026-02-15T13:30:45Z" {
+		t.Fatalf("unexpected log record: %v", second)
+	}
+}
+
+func TestEmitWithoutLoggerIsNoop(t *testing.T) {
+	Emit(context.Background(), "list_start", nil)
+	if FromContext(context.Background()) != nil {
+		t.Fatalf("expected no logger in empty context")
+	}
+}
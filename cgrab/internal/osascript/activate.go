@@ -8,13 +8,15 @@ import (
 )
 
 func ActivateTab(ctx context.Context, browser string, windowIndex int, tabIndex int) error {
-	switch strings.ToLower(strings.TrimSpace(browser)) {
-	case "safari":
-		return activateSafariTab(ctx, windowIndex, tabIndex)
-	case "chrome":
-		return activateChromeTab(ctx, windowIndex, tabIndex)
+	app, ok := LookupBrowser(browser)
+	if !ok {
+		return fmt.Errorf("unsupported browser %q (expected one of: %s)", browser, strings.Join(BrowserTargets(), ", "))
+	}
+	switch app.Family {
+	case BrowserFamilySafari:
+		return activateSafariTab(ctx, app, windowIndex, tabIndex)
 	default:
-		return fmt.Errorf("unsupported browser %q (expected safari or chrome)", browser)
+		return activateChromeTab(ctx, app, windowIndex, tabIndex)
 	}
 }
 
@@ -42,26 +44,26 @@ func ActivateAppByBundleID(ctx context.Context, bundleIdentifier string) error {
 	return nil
 }
 
-func activateSafariTab(ctx context.Context, windowIndex int, tabIndex int) error {
+func activateSafariTab(ctx context.Context, app BrowserApp, windowIndex int, tabIndex int) error {
 	if windowIndex <= 0 || tabIndex <= 0 {
 		return fmt.Errorf("window and tab index must be positive")
 	}
 	_, err := runAppleScriptWithArgs(
 		ctx,
-		activateSafariTabScript,
+		scriptForBrowser(activateSafariTabScript, app),
 		strconv.Itoa(windowIndex),
 		strconv.Itoa(tabIndex),
 	)
 	return err
 }
 
-func activateChromeTab(ctx context.Context, windowIndex int, tabIndex int) error {
+func activateChromeTab(ctx context.Context, app BrowserApp, windowIndex int, tabIndex int) error {
 	if windowIndex <= 0 || tabIndex <= 0 {
 		return fmt.Errorf("window and tab index must be positive")
 	}
 	_, err := runAppleScriptWithArgs(
 		ctx,
-		activateChromeTabScript,
+		scriptForBrowser(activateChromeTabScript, app),
 		strconv.Itoa(windowIndex),
 		strconv.Itoa(tabIndex),
 	)
@@ -77,12 +79,12 @@ on run argv
 	set tabIndex to item 2 of argv as integer
 
 	tell application "System Events"
-		if not (exists process "Safari") then
-			error "Safari is not running."
+		if not (exists process "__BROWSER_APP__") then
+			error "__BROWSER_APP__ is not running."
 		end if
 	end tell
 
-	tell application "Safari"
+	tell application "__BROWSER_APP__"
 		if windowIndex > (count of windows) then
 			error "Safari window index out of range."
 		end if
@@ -107,12 +109,12 @@ on run argv
 	set tabIndex to item 2 of argv as integer
 
 	tell application "System Events"
-		if not (exists process "Google Chrome") then
-			error "Google Chrome is not running."
+		if not (exists process "__BROWSER_APP__") then
+			error "__BROWSER_APP__ is not running."
 		end if
 	end tell
 
-	tell application "Google Chrome"
+	tell application "__BROWSER_APP__"
 		if windowIndex > (count of windows) then
 			error "Chrome window index out of range."
 		end if
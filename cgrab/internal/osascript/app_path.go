@@ -0,0 +1,46 @@
+package osascript
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// appExecutablePathScript asks LaunchServices (through NSWorkspace) where
+// the app registered under a bundle identifier lives, without launching it,
+// and returns the bundle's executable path ("" when none is registered).
+const appExecutablePathScript = `ObjC.import("AppKit");
+function run(argv) {
+	const url = $.NSWorkspace.sharedWorkspace.URLForApplicationWithBundleIdentifier(argv[0]);
+	if (url.isNil()) {
+		return "";
+	}
+	const bundle = $.NSBundle.bundleWithURL(url);
+	if (bundle.isNil() || bundle.executablePath.isNil()) {
+		return "";
+	}
+	return bundle.executablePath.js;
+}`
+
+// AppExecutablePath returns the executable of the app with bundleID,
+// wherever LaunchServices has it registered (e.g. ~/Applications or a
+// renamed bundle), rather than assuming /Applications/<name>.app.
+func AppExecutablePath(ctx context.Context, bundleID string) (string, error) {
+	bundleID = strings.TrimSpace(bundleID)
+	if bundleID == "" {
+		return "", fmt.Errorf("bundle identifier is required")
+	}
+	stdout, stderr, err := runner.Run(ctx, resolveOsaScriptPath(), "-l", "JavaScript", "-e", appExecutablePathScript, bundleID)
+	if err != nil {
+		message := strings.TrimSpace(stderr)
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("look up %s with LaunchServices: %s", bundleID, message)
+	}
+	path := strings.TrimSpace(stdout)
+	if path == "" {
+		return "", fmt.Errorf("no app with bundle identifier %s is installed", bundleID)
+	}
+	return path, nil
+}
@@ -0,0 +1,34 @@
+package osascript
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAppExecutablePathQueriesLaunchServices(t *testing.T) {
+	var gotArgs []string
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		gotArgs = args
+		if args[len(args)-1] == "com.example.missing" {
+			return "\n", "", nil
+		}
+		return "/Users/me/Applications/Google Chrome Beta.app/Contents/MacOS/Google Chrome Beta\n", "", nil
+	}))
+	defer restore()
+
+	path, err := AppExecutablePath(context.Background(), "com.google.Chrome.beta")
+	if err != nil {
+		t.Fatalf("AppExecutablePath returned error: %v", err)
+	}
+	if path != "/Users/me/Applications/Google Chrome Beta.app/Contents/MacOS/Google Chrome Beta" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if gotArgs[0] != "-l" || gotArgs[1] != "JavaScript" || !strings.Contains(gotArgs[3], "URLForApplicationWithBundleIdentifier") {
+		t.Fatalf("expected a JXA LaunchServices lookup, got %q", gotArgs)
+	}
+
+	if _, err := AppExecutablePath(context.Background(), "com.example.missing"); err == nil || !strings.Contains(err.Error(), "is installed") {
+		t.Fatalf("expected a not-installed error, got %v", err)
+	}
+}
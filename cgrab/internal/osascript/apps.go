@@ -12,50 +12,99 @@ type AppEntry struct {
 	AppName          string `json:"appName"`
 	BundleIdentifier string `json:"bundleIdentifier"`
 	WindowCount      int    `json:"windowCount"`
+	// AppPath is the POSIX path of the application bundle, e.g.
+	// /Applications/Safari.app. It is empty when System Events cannot
+	// resolve the application file.
+	AppPath string `json:"appPath,omitempty"`
+	// IsFrontmost marks the app System Events reports as frontmost, i.e.
+	// the one the user is currently working in.
+	IsFrontmost bool `json:"isFrontmost,omitempty"`
 }
 
-func ListApps(ctx context.Context) ([]AppEntry, error) {
-	output, err := runAppleScript(ctx, appsScript)
+// ListApps returns the running apps with at least one window, along with
+// warnings for records that could not be parsed and were skipped.
+func ListApps(ctx context.Context) ([]AppEntry, []string, error) {
+	output, err := runReadOnlyAppleScript(ctx, appsScript)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if strings.TrimSpace(output) == "" {
-		return []AppEntry{}, nil
+		return []AppEntry{}, nil, nil
 	}
 
-	entries, err := parseAppEntries(output)
+	entries, warnings := parseAppEntries(output)
+	sortApps(entries)
+	return entries, warnings, nil
+}
+
+// FrontmostApp returns the application process System Events reports as
+// frontmost.
+func FrontmostApp(ctx context.Context) (AppEntry, error) {
+	output, err := runAppleScript(ctx, frontmostAppScript)
 	if err != nil {
-		return nil, err
+		return AppEntry{}, err
 	}
-	sortApps(entries)
-	return entries, nil
+	entries, warnings := parseAppEntries(output)
+	if len(entries) != 1 {
+		if len(warnings) > 0 {
+			return AppEntry{}, fmt.Errorf("unreadable frontmost app record: %s", strings.Join(warnings, "; "))
+		}
+		return AppEntry{}, fmt.Errorf("expected one frontmost app record, got %d", len(entries))
+	}
+	entries[0].IsFrontmost = true
+	return entries[0], nil
+}
+
+// FrontmostBrowser returns the browser target (e.g. "safari" or
+// "chrome-beta") of the frontmost application, or "" when the frontmost app
+// is not a supported browser.
+func FrontmostBrowser(ctx context.Context) (string, error) {
+	entry, err := FrontmostApp(ctx)
+	if err != nil {
+		return "", err
+	}
+	app, ok := LookupBrowserByBundleID(entry.BundleIdentifier)
+	if !ok {
+		return "", nil
+	}
+	return app.Target, nil
 }
 
-func parseAppEntries(output string) ([]AppEntry, error) {
+// parseAppEntries parses appsScript or frontmostAppScript output. A record
+// that cannot be parsed (wrong field count or a non-numeric window count) is
+// skipped with a warning rather than failing the whole listing.
+func parseAppEntries(output string) ([]AppEntry, []string) {
 	records := strings.Split(output, recordSeparator)
 	entries := make([]AppEntry, 0, len(records))
+	var warnings []string
 
 	for _, record := range records {
 		record = strings.TrimSpace(record)
 		if record == "" {
 			continue
 		}
+		// appsScript appends a fifth frontmost flag; frontmostAppScript
+		// returns the first four fields only.
 		fields := strings.Split(record, fieldSeparator)
-		if len(fields) != 3 {
-			return nil, fmt.Errorf("invalid app record field count %d", len(fields))
+		if len(fields) != 4 && len(fields) != 5 {
+			warnings = append(warnings, fmt.Sprintf("skipped app record with %d fields", len(fields)))
+			continue
 		}
 
 		windowCount, err := strconv.Atoi(strings.TrimSpace(fields[2]))
 		if err != nil {
-			return nil, fmt.Errorf("invalid window count %q: %w", fields[2], err)
+			warnings = append(warnings, fmt.Sprintf("skipped %s app record with invalid window count %q", strings.TrimSpace(fields[0]), fields[2]))
+			continue
 		}
 		entries = append(entries, AppEntry{
 			AppName:          strings.TrimSpace(fields[0]),
 			BundleIdentifier: strings.TrimSpace(fields[1]),
 			WindowCount:      windowCount,
+			AppPath:          strings.TrimSuffix(strings.TrimSpace(fields[3]), "/"),
+			IsFrontmost:      len(fields) == 5 && strings.TrimSpace(fields[4]) == "true",
 		})
 	}
-	return entries, nil
+	return entries, warnings
 }
 
 func sortApps(entries []AppEntry) {
@@ -82,12 +131,20 @@ tell application "System Events"
 			set windowCount to 0
 		end try
 		if windowCount is greater than 0 then
-			set appName to name of processRef as text
+			set appName to my stripSeparators(name of processRef as text)
 			set bundleID to ""
 			try
 				set bundleID to bundle identifier of processRef as text
 			end try
-			set end of resultRows to appName & fieldSep & bundleID & fieldSep & (windowCount as text)
+			set appPath to ""
+			try
+				set appPath to my stripSeparators(POSIX path of (application file of processRef as alias))
+			end try
+			set isFrontmost to false
+			try
+				set isFrontmost to frontmost of processRef
+			end try
+			set end of resultRows to appName & fieldSep & bundleID & fieldSep & (windowCount as text) & fieldSep & appPath & fieldSep & (isFrontmost as text)
 		end if
 	end repeat
 end tell
@@ -103,4 +160,25 @@ on joinRows(values, separator)
 	set AppleScript's text item delimiters to ""
 	return joined
 end joinRows
-`
+` + stripSeparatorsHandler
+
+const frontmostAppScript = `
+set fieldSep to ASCII character 30
+
+tell application "System Events"
+	set processRef to first application process whose frontmost is true
+	set windowCount to 0
+	try
+		set windowCount to count of windows of processRef
+	end try
+	set bundleId to ""
+	try
+		set bundleId to bundle identifier of processRef as text
+	end try
+	set appPath to ""
+	try
+		set appPath to my stripSeparators(POSIX path of (application file of processRef as alias))
+	end try
+	return my stripSeparators(name of processRef as text) & fieldSep & bundleId & fieldSep & (windowCount as text) & fieldSep & appPath
+end tell
+` + stripSeparatorsHandler
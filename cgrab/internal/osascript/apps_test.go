@@ -8,36 +8,63 @@ import (
 
 func TestParseAppEntries(t *testing.T) {
 	raw := strings.Join([]string{
-		"Finder" + fieldSeparator + "com.apple.finder" + fieldSeparator + "3",
-		"Terminal" + fieldSeparator + "com.apple.Terminal" + fieldSeparator + "1",
+		"Finder" + fieldSeparator + "com.apple.finder" + fieldSeparator + "3" + fieldSeparator + "/System/Library/CoreServices/Finder.app/",
+		"Terminal" + fieldSeparator + "com.apple.Terminal" + fieldSeparator + "1" + fieldSeparator + "/System/Applications/Utilities/Terminal.app/" + fieldSeparator + "true",
 	}, recordSeparator)
 
-	entries, err := parseAppEntries(raw)
-	if err != nil {
-		t.Fatalf("parseAppEntries returned error: %v", err)
+	entries, warnings := parseAppEntries(raw)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
 	}
 	if len(entries) != 2 {
 		t.Fatalf("expected 2 entries, got %d", len(entries))
 	}
-	if entries[0].AppName != "Finder" || entries[0].WindowCount != 3 {
+	if entries[0].AppName != "Finder" || entries[0].WindowCount != 3 || entries[0].AppPath != "/System/Library/CoreServices/Finder.app" {
 		t.Fatalf("unexpected first entry: %#v", entries[0])
 	}
+	if entries[0].IsFrontmost || !entries[1].IsFrontmost {
+		t.Fatalf("expected only Terminal to be frontmost: %#v", entries)
+	}
+}
+
+func TestParseAppEntriesSkipsMalformedRecordsWithWarnings(t *testing.T) {
+	raw := strings.Join([]string{
+		"Finder" + fieldSeparator + "com.apple.finder" + fieldSeparator + "3" + fieldSeparator + "",
+		"Broken" + fieldSeparator + "com.example.broken" + fieldSeparator + "many" + fieldSeparator + "",
+		"Truncated" + fieldSeparator + "com.example.truncated",
+		"Terminal" + fieldSeparator + "com.apple.Terminal" + fieldSeparator + "1" + fieldSeparator + "",
+	}, recordSeparator)
+
+	entries, warnings := parseAppEntries(raw)
+	if len(entries) != 2 || entries[0].AppName != "Finder" || entries[1].AppName != "Terminal" {
+		t.Fatalf("expected the well-formed records to be kept, got %#v", entries)
+	}
+	want := []string{
+		`skipped Broken app record with invalid window count "many"`,
+		"skipped app record with 2 fields",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected warnings:\nwant: %q\ngot:  %q", want, warnings)
+	}
 }
 
 func TestListAppsReturnsSortedResults(t *testing.T) {
 	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, _ ...string) (string, string, error) {
 		raw := strings.Join([]string{
-			"Terminal" + fieldSeparator + "com.apple.Terminal" + fieldSeparator + "1",
-			"Finder" + fieldSeparator + "com.apple.finder" + fieldSeparator + "2",
+			"Terminal" + fieldSeparator + "com.apple.Terminal" + fieldSeparator + "1" + fieldSeparator + "/System/Applications/Utilities/Terminal.app/",
+			"Finder" + fieldSeparator + "com.apple.finder" + fieldSeparator + "2" + fieldSeparator + "",
 		}, recordSeparator)
 		return raw, "", nil
 	}))
 	defer restore()
 
-	entries, err := ListApps(context.Background())
+	entries, warnings, err := ListApps(context.Background())
 	if err != nil {
 		t.Fatalf("ListApps returned error: %v", err)
 	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
 	if len(entries) != 2 {
 		t.Fatalf("expected 2 entries, got %d", len(entries))
 	}
@@ -45,3 +72,37 @@ func TestListAppsReturnsSortedResults(t *testing.T) {
 		t.Fatalf("expected Finder first after sorting, got %s", entries[0].AppName)
 	}
 }
+
+func TestFrontmostAppParsesSingleRecord(t *testing.T) {
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, _ ...string) (string, string, error) {
+		return "Google Chrome" + fieldSeparator + "com.google.Chrome" + fieldSeparator + "2" + fieldSeparator + "/Applications/Google Chrome.app/\n", "", nil
+	}))
+	defer restore()
+
+	entry, err := FrontmostApp(context.Background())
+	if err != nil {
+		t.Fatalf("FrontmostApp returned error: %v", err)
+	}
+	if entry.BundleIdentifier != "com.google.Chrome" || entry.WindowCount != 2 {
+		t.Fatalf("unexpected frontmost app: %#v", entry)
+	}
+}
+
+func TestFrontmostBrowserMapsBundleIdentifierToTarget(t *testing.T) {
+	frontmost := "Google Chrome Beta" + fieldSeparator + "com.google.Chrome.beta" + fieldSeparator + "1" + fieldSeparator + "/Applications/Google Chrome Beta.app/\n"
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, _ ...string) (string, string, error) {
+		return frontmost, "", nil
+	}))
+	defer restore()
+
+	target, err := FrontmostBrowser(context.Background())
+	if err != nil || target != "chrome-beta" {
+		t.Fatalf("expected chrome-beta, got %q err=%v", target, err)
+	}
+
+	frontmost = "Finder" + fieldSeparator + "com.apple.finder" + fieldSeparator + "1" + fieldSeparator + "/System/Library/CoreServices/Finder.app/\n"
+	target, err = FrontmostBrowser(context.Background())
+	if err != nil || target != "" {
+		t.Fatalf("expected no browser for Finder, got %q err=%v", target, err)
+	}
+}
@@ -0,0 +1,120 @@
+package osascript
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	BrowserFamilySafari = "safari"
+	BrowserFamilyChrome = "chrome"
+)
+
+// BrowserApp describes a scriptable browser. Release channels share their
+// family's AppleScript dictionary and differ only in application name.
+type BrowserApp struct {
+	Target      string
+	Family      string
+	AppName     string
+	DisplayName string
+	BundleID    string
+}
+
+var browserApps = []BrowserApp{
+	{Target: "safari", Family: BrowserFamilySafari, AppName: "Safari", DisplayName: "Safari", BundleID: "com.apple.Safari"},
+	{Target: "safari-tp", Family: BrowserFamilySafari, AppName: "Safari Technology Preview", DisplayName: "Safari Technology Preview", BundleID: "com.apple.SafariTechnologyPreview"},
+	{Target: "chrome", Family: BrowserFamilyChrome, AppName: "Google Chrome", DisplayName: "Chrome", BundleID: "com.google.Chrome"},
+	{Target: "chrome-beta", Family: BrowserFamilyChrome, AppName: "Google Chrome Beta", DisplayName: "Chrome Beta", BundleID: "com.google.Chrome.beta"},
+	{Target: "chrome-dev", Family: BrowserFamilyChrome, AppName: "Google Chrome Dev", DisplayName: "Chrome Dev", BundleID: "com.google.Chrome.dev"},
+	{Target: "chrome-canary", Family: BrowserFamilyChrome, AppName: "Google Chrome Canary", DisplayName: "Chrome Canary", BundleID: "com.google.Chrome.canary"},
+}
+
+// appNameEnvVars name the environment variables that replace a browser's
+// AppleScript application name, for renamed apps and forks that keep the
+// Safari or Chrome dictionary. The Chrome variable is also read by the
+// extension bridge's AppleScript fallback.
+var appNameEnvVars = map[string]string{
+	"safari": "CONTEXT_GRABBER_SAFARI_APP_NAME",
+	"chrome": "CONTEXT_GRABBER_CHROME_APP_NAME",
+}
+
+// withAppNameOverride returns app with its AppName replaced by the target's
+// environment override, if one is set.
+func withAppNameOverride(app BrowserApp) BrowserApp {
+	envVar, ok := appNameEnvVars[app.Target]
+	if !ok {
+		return app
+	}
+	if override := strings.TrimSpace(os.Getenv(envVar)); override != "" {
+		app.AppName = override
+	}
+	return app
+}
+
+// BrowserTargetAll is the explicit spelling of "no browser filter": like an
+// empty filter, it selects every registered browser.
+const BrowserTargetAll = "all"
+
+// IsAllBrowsers reports whether a browser filter is empty or "all".
+func IsAllBrowsers(target string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(target))
+	return normalized == "" || normalized == BrowserTargetAll
+}
+
+// LookupBrowser returns the browser registered under target (e.g. "chrome-beta").
+func LookupBrowser(target string) (BrowserApp, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(target))
+	for _, app := range browserApps {
+		if app.Target == normalized {
+			return withAppNameOverride(app), true
+		}
+	}
+	return BrowserApp{}, false
+}
+
+// LookupBrowserByBundleID returns the browser whose bundle identifier is bundleID.
+func LookupBrowserByBundleID(bundleID string) (BrowserApp, bool) {
+	trimmed := strings.TrimSpace(bundleID)
+	for _, app := range browserApps {
+		if strings.EqualFold(app.BundleID, trimmed) {
+			return withAppNameOverride(app), true
+		}
+	}
+	return BrowserApp{}, false
+}
+
+// BrowserTargets returns every supported browser target name.
+func BrowserTargets() []string {
+	targets := make([]string, 0, len(browserApps))
+	for _, app := range browserApps {
+		targets = append(targets, app.Target)
+	}
+	return targets
+}
+
+// BrowserFilterValues returns the accepted --browser values: "all" followed
+// by every registered target.
+func BrowserFilterValues() []string {
+	return append([]string{BrowserTargetAll}, BrowserTargets()...)
+}
+
+func browserRank(target string) int {
+	for index, app := range browserApps {
+		if app.Target == target {
+			return index
+		}
+	}
+	return len(browserApps)
+}
+
+// browserAppPlaceholder marks where scripts name the browser application,
+// and browserBundleIDPlaceholder where they use its bundle identifier.
+const (
+	browserAppPlaceholder      = "__BROWSER_APP__"
+	browserBundleIDPlaceholder = "__BROWSER_BUNDLE_ID__"
+)
+
+func scriptForBrowser(script string, app BrowserApp) string {
+	script = strings.ReplaceAll(script, browserBundleIDPlaceholder, app.BundleID)
+	return strings.ReplaceAll(script, browserAppPlaceholder, app.AppName)
+}
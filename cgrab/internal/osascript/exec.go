@@ -6,13 +6,46 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 const (
 	fieldSeparator  = "\x1e"
 	recordSeparator = "\x1f"
+
+	// stripSeparatorsHandler is appended to list scripts so free-text
+	// values (titles, URLs, app names) cannot contain the separators:
+	// each ASCII 30/31 byte is replaced with a space before joining.
+	stripSeparatorsHandler = `
+on stripSeparators(value)
+	set previousDelimiters to AppleScript's text item delimiters
+	set AppleScript's text item delimiters to {ASCII character 30, ASCII character 31}
+	set parts to text items of (value as text)
+	set AppleScript's text item delimiters to " "
+	set cleaned to parts as text
+	set AppleScript's text item delimiters to previousDelimiters
+	return cleaned
+end stripSeparators
+`
+
+	// retryEnvVar disables the transient-error retry when set to 0/false.
+	retryEnvVar       = "CONTEXT_GRABBER_OSASCRIPT_RETRY"
+	transientAttempts = 2
 )
 
+// transientErrorMarkers are osascript failures seen while a target app is
+// still launching; they usually succeed on a second try. AppleEvent timeouts
+// (-1712) are not listed: the app is up but slow, and retrying only doubles
+// an already long wait.
+var transientErrorMarkers = []string{
+	"connection is invalid",
+	"(-609)",
+	"application isn't running",
+	"(-600)",
+}
+
+var transientRetryDelay = 300 * time.Millisecond
+
 type scriptRunner interface {
 	Run(ctx context.Context, name string, args ...string) (stdout string, stderr string, err error)
 }
@@ -20,6 +53,7 @@ type scriptRunner interface {
 type defaultScriptRunner struct{}
 
 func (defaultScriptRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	logInvocation(name, args)
 	cmd := exec.CommandContext(ctx, name, args...)
 	stdoutBytes, err := cmd.Output()
 	if err == nil {
@@ -47,6 +81,34 @@ func runAppleScript(ctx context.Context, script string) (string, error) {
 	return runAppleScriptWithArgs(ctx, script)
 }
 
+// runReadOnlyAppleScript runs a script that only reads browser or app state,
+// retrying once after a short delay when osascript fails with a
+// known-transient error. Scripts with side effects (opening tabs,
+// activation) must not use it, since a failed attempt may still have acted.
+func runReadOnlyAppleScript(ctx context.Context, script string) (string, error) {
+	attempts := 1
+	if retryEnabled() {
+		attempts = transientAttempts
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		var output string
+		output, err = runAppleScriptWithArgs(ctx, script)
+		if err == nil {
+			return output, nil
+		}
+		if attempt >= attempts || !isTransientError(err) {
+			return "", err
+		}
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(transientRetryDelay):
+		}
+	}
+}
+
+// runAppleScriptWithArgs runs the script once with argv set to scriptArgs.
 func runAppleScriptWithArgs(ctx context.Context, script string, scriptArgs ...string) (string, error) {
 	osaPath := resolveOsaScriptPath()
 	args := []string{"-e", script}
@@ -65,6 +127,25 @@ func runAppleScriptWithArgs(ctx context.Context, script string, scriptArgs ...st
 	return strings.TrimSpace(stdout), nil
 }
 
+func isTransientError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, marker := range transientErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func retryEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(retryEnvVar))) {
+	case "0", "false", "no", "off":
+		return false
+	default:
+		return true
+	}
+}
+
 func resolveOsaScriptPath() string {
 	if configured := strings.TrimSpace(os.Getenv("CONTEXT_GRABBER_OSASCRIPT_BIN")); configured != "" {
 		return configured
@@ -0,0 +1,102 @@
+package osascript
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func stubRetryDelay(t *testing.T) {
+	t.Helper()
+	previous := transientRetryDelay
+	transientRetryDelay = time.Millisecond
+	t.Cleanup(func() {
+		transientRetryDelay = previous
+	})
+}
+
+func TestRunReadOnlyAppleScriptRetriesTransientErrors(t *testing.T) {
+	stubRetryDelay(t)
+	t.Setenv(retryEnvVar, "")
+	calls := 0
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		calls++
+		if calls == 1 {
+			return "", "execution error: Google Chrome got an error: Connection is invalid. (-609)", errors.New("exit status 1")
+		}
+		return "ok", "", nil
+	}))
+	defer restore()
+
+	output, err := runReadOnlyAppleScript(context.Background(), "return 1")
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if output != "ok" || calls != 2 {
+		t.Fatalf("expected second attempt output, got %q after %d calls", output, calls)
+	}
+}
+
+func TestRunReadOnlyAppleScriptDoesNotRetryOtherErrors(t *testing.T) {
+	stubRetryDelay(t)
+	t.Setenv(retryEnvVar, "")
+	calls := 0
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		calls++
+		return "", "execution error: Not authorized to send Apple events to Safari. (-1743)", errors.New("exit status 1")
+	}))
+	defer restore()
+
+	if _, err := runReadOnlyAppleScript(context.Background(), "return 1"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single attempt for a non-transient error, got %d", calls)
+	}
+}
+
+func TestRunReadOnlyAppleScriptRetryCanBeDisabled(t *testing.T) {
+	stubRetryDelay(t)
+	t.Setenv(retryEnvVar, "0")
+	calls := 0
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		calls++
+		return "", "Connection is invalid. (-609)", errors.New("exit status 1")
+	}))
+	defer restore()
+
+	if _, err := runReadOnlyAppleScript(context.Background(), "return 1"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected retries disabled by %s=0, got %d calls", retryEnvVar, calls)
+	}
+}
+
+func TestOnlyReadOnlyScriptsRetryAndTimeoutsAreNotTransient(t *testing.T) {
+	stubRetryDelay(t)
+	t.Setenv(retryEnvVar, "")
+	calls := 0
+	stderr := "Connection is invalid. (-609)"
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		calls++
+		return "", stderr, errors.New("exit status 1")
+	}))
+	defer restore()
+
+	if err := ActivateAppByName(context.Background(), "Finder"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected activation not to be retried, got %d calls", calls)
+	}
+
+	calls, stderr = 0, "execution error: Safari got an error: AppleEvent timed out. (-1712)"
+	if _, err := runReadOnlyAppleScript(context.Background(), "return 1"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected an AppleEvent timeout not to be retried, got %d calls", calls)
+	}
+}
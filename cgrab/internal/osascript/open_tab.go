@@ -0,0 +1,264 @@
+package osascript
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// openTabLoadTimeoutSeconds bounds how long OpenTab waits for the new tab
+// to finish loading before returning it anyway.
+const openTabLoadTimeoutSeconds = 15
+
+// OpenedTab is a tab created by OpenTab, with what CloseTab needs to find
+// it again after the user has switched windows or reordered tabs.
+type OpenedTab struct {
+	TabEntry
+	// TabID is Chrome's stable tab id; Safari tabs have none.
+	TabID string
+	// WindowID and LoadedURL identify a Safari tab: the window it was
+	// opened in and the address it showed once loaded.
+	WindowID  string
+	LoadedURL string
+}
+
+// OpenTab opens pageURL in a background tab at the end of the browser's
+// front window (or a new window when none is open), leaving that window's
+// current tab in front, waits for it to load, and returns the new tab.
+//
+// Opening and closing run once, without the transient-error retry: a
+// retried open could leave a second tab behind that nothing closes.
+func OpenTab(ctx context.Context, browser string, pageURL string) (OpenedTab, error) {
+	app, ok := LookupBrowser(browser)
+	if !ok {
+		return OpenedTab{}, fmt.Errorf("unsupported browser %q (expected one of: %s)", browser, strings.Join(BrowserTargets(), ", "))
+	}
+	if strings.TrimSpace(pageURL) == "" {
+		return OpenedTab{}, fmt.Errorf("url is required")
+	}
+	script := openSafariTabScript
+	if app.Family == BrowserFamilyChrome {
+		script = openChromeTabScript
+	}
+
+	output, err := runAppleScriptWithArgs(ctx, scriptForBrowser(script, app), pageURL, strconv.Itoa(openTabLoadTimeoutSeconds))
+	if err != nil {
+		return OpenedTab{}, err
+	}
+	// window index, tab index, and an identifier: Chrome's tab id, or
+	// Safari's window id followed by the loaded URL.
+	fields := strings.SplitN(strings.TrimSpace(output), fieldSeparator, 4)
+	if len(fields) < 3 {
+		return OpenedTab{}, fmt.Errorf("invalid open tab field count %d", len(fields))
+	}
+	windowIndex, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return OpenedTab{}, fmt.Errorf("invalid window index %q: %w", fields[0], err)
+	}
+	tabIndex, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return OpenedTab{}, fmt.Errorf("invalid tab index %q: %w", fields[1], err)
+	}
+	opened := OpenedTab{
+		TabEntry: TabEntry{
+			Browser:         app.Target,
+			BrowserBundleID: app.BundleID,
+			WindowIndex:     windowIndex,
+			TabIndex:        tabIndex,
+			URL:             pageURL,
+		},
+	}
+	if app.Family == BrowserFamilyChrome {
+		opened.TabID = fields[2]
+		return opened, nil
+	}
+	if len(fields) != 4 {
+		return OpenedTab{}, fmt.Errorf("invalid open tab field count %d", len(fields))
+	}
+	opened.WindowID = fields[2]
+	opened.LoadedURL = fields[3]
+	return opened, nil
+}
+
+// CloseTab closes a tab opened by OpenTab: in Chrome the tab with its id,
+// in Safari the tab in its window that still shows its loaded URL. When
+// the tab cannot be found it returns an error rather than closing another.
+func CloseTab(ctx context.Context, tab OpenedTab) error {
+	app, ok := LookupBrowser(tab.Browser)
+	if !ok {
+		return fmt.Errorf("unsupported browser %q (expected one of: %s)", tab.Browser, strings.Join(BrowserTargets(), ", "))
+	}
+	if app.Family == BrowserFamilyChrome {
+		if strings.TrimSpace(tab.TabID) == "" {
+			return fmt.Errorf("tab id is required")
+		}
+		_, err := runAppleScriptWithArgs(ctx, scriptForBrowser(closeChromeTabScript, app), tab.TabID)
+		return err
+	}
+	if strings.TrimSpace(tab.WindowID) == "" || strings.TrimSpace(tab.LoadedURL) == "" {
+		return fmt.Errorf("window id and loaded url are required")
+	}
+	_, err := runAppleScriptWithArgs(
+		ctx,
+		scriptForBrowser(closeSafariTabScript, app),
+		tab.WindowID,
+		strconv.Itoa(tab.TabIndex),
+		tab.LoadedURL,
+	)
+	return err
+}
+
+const openSafariTabScript = `
+on run argv
+	if (count of argv) is not 2 then
+		error "Expected arguments: <url> <timeoutSeconds>"
+	end if
+	set targetURL to item 1 of argv as text
+	set timeoutSeconds to item 2 of argv as integer
+
+	tell application "System Events"
+		if not (exists process "__BROWSER_APP__") then
+			error "__BROWSER_APP__ is not running."
+		end if
+	end tell
+
+	tell application "__BROWSER_APP__"
+		if (count of windows) is 0 then
+			make new document with properties {URL:targetURL}
+			set windowID to id of window 1
+			set tabIndex to 1
+		else
+			set windowID to id of window 1
+			tell window id windowID
+				set previousTab to current tab
+				make new tab at end of tabs with properties {URL:targetURL}
+				set tabIndex to count of tabs
+				-- Keep the user's tab in front; capture activates the new one.
+				set current tab to previousTab
+			end tell
+		end if
+		set tabRef to tab tabIndex of window id windowID
+		-- Page readiness needs "Allow JavaScript from Apple Events"; without
+		-- it the tab is returned as soon as it is created.
+		set deadline to (current date) + timeoutSeconds
+		repeat while (current date) < deadline
+			try
+				if (do JavaScript "document.readyState" in tabRef) is "complete" then exit repeat
+			on error
+				exit repeat
+			end try
+			delay 0.2
+		end repeat
+		set loadedURL to URL of tabRef
+		if loadedURL is missing value then set loadedURL to targetURL
+		set windowIndex to index of window id windowID
+	end tell
+	set separator to ASCII character 30
+	return (windowIndex as text) & separator & (tabIndex as text) & separator & (windowID as text) & separator & (loadedURL as text)
+end run
+`
+
+const openChromeTabScript = `
+on run argv
+	if (count of argv) is not 2 then
+		error "Expected arguments: <url> <timeoutSeconds>"
+	end if
+	set targetURL to item 1 of argv as text
+	set timeoutSeconds to item 2 of argv as integer
+
+	tell application "System Events"
+		if not (exists process "__BROWSER_APP__") then
+			error "__BROWSER_APP__ is not running."
+		end if
+	end tell
+
+	tell application "__BROWSER_APP__"
+		if (count of windows) is 0 then
+			set windowID to id of (make new window)
+			set tabID to id of active tab of window id windowID
+			set URL of active tab of window id windowID to targetURL
+		else
+			set windowID to id of window 1
+			tell window id windowID
+				set previousIndex to active tab index
+				set tabID to id of (make new tab at end of tabs with properties {URL:targetURL})
+				-- Keep the user's tab in front; capture activates the new one.
+				set active tab index to previousIndex
+			end tell
+		end if
+		set tabRef to tab id tabID of window id windowID
+		set deadline to (current date) + timeoutSeconds
+		repeat while (loading of tabRef) and (current date) < deadline
+			delay 0.2
+		end repeat
+		-- Report the position the tab has now; other tabs may have opened
+		-- in the same window while this one loaded.
+		set tabIndex to 0
+		repeat with candidateIndex from 1 to count of tabs of window id windowID
+			if id of tab candidateIndex of window id windowID is tabID then
+				set tabIndex to candidateIndex
+				exit repeat
+			end if
+		end repeat
+		set windowIndex to index of window id windowID
+	end tell
+	set separator to ASCII character 30
+	return (windowIndex as text) & separator & (tabIndex as text) & separator & (tabID as text)
+end run
+`
+
+const closeChromeTabScript = `
+on run argv
+	if (count of argv) is not 1 then
+		error "Expected arguments: <tabId>"
+	end if
+	set tabID to item 1 of argv as text
+
+	tell application "__BROWSER_APP__"
+		repeat with candidateWindow in windows
+			repeat with candidateTab in tabs of candidateWindow
+				if (id of candidateTab as text) is tabID then
+					close candidateTab
+					return
+				end if
+			end repeat
+		end repeat
+	end tell
+	error "__BROWSER_APP__ tab " & tabID & " is no longer open."
+end run
+`
+
+const closeSafariTabScript = `
+on run argv
+	if (count of argv) is not 3 then
+		error "Expected arguments: <windowId> <tabIndex> <loadedUrl>"
+	end if
+	set windowID to item 1 of argv as integer
+	set tabIndex to item 2 of argv as integer
+	set loadedURL to item 3 of argv as text
+
+	tell application "__BROWSER_APP__"
+		if not (exists window id windowID) then
+			error "__BROWSER_APP__ window of the opened tab is no longer open."
+		end if
+		tell window id windowID
+			-- Safari tabs have no id: close the remembered position only while
+			-- it still shows the opened page, else the last tab that does.
+			if tabIndex is less than or equal to (count of tabs) then
+				if (URL of tab tabIndex) is loadedURL then
+					close tab tabIndex
+					return
+				end if
+			end if
+			repeat with candidateIndex from (count of tabs) to 1 by -1
+				if (URL of tab candidateIndex) is loadedURL then
+					close tab candidateIndex
+					return
+				end if
+			end repeat
+		end tell
+	end tell
+	error "__BROWSER_APP__ tab showing " & loadedURL & " is no longer open."
+end run
+`
@@ -0,0 +1,107 @@
+package osascript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOpenTabReturnsNewTabPosition(t *testing.T) {
+	var gotArgs []string
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		gotArgs = args
+		return "1" + fieldSeparator + "7" + fieldSeparator + "812\n", "", nil
+	}))
+	defer restore()
+
+	opened, err := OpenTab(context.Background(), "chrome", "https://example.com/docs")
+	if err != nil {
+		t.Fatalf("OpenTab returned error: %v", err)
+	}
+	if opened.Browser != "chrome" || opened.WindowIndex != 1 || opened.TabIndex != 7 || opened.TabID != "812" || opened.URL != "https://example.com/docs" {
+		t.Fatalf("unexpected opened tab: %#v", opened)
+	}
+	if opened.IsActive {
+		t.Fatalf("expected the tab to open in the background")
+	}
+	joined := strings.Join(gotArgs, " ")
+	if !strings.Contains(joined, `tell application "Google Chrome"`) || !strings.Contains(joined, "https://example.com/docs") {
+		t.Fatalf("expected Chrome script with the URL argument, got %q", joined)
+	}
+}
+
+func TestOpenTabReportsSafariWindowAndLoadedURL(t *testing.T) {
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		return "2" + fieldSeparator + "3" + fieldSeparator + "4410" + fieldSeparator + "https://example.com/docs/\n", "", nil
+	}))
+	defer restore()
+
+	opened, err := OpenTab(context.Background(), "safari", "https://example.com/docs")
+	if err != nil {
+		t.Fatalf("OpenTab returned error: %v", err)
+	}
+	if opened.WindowIndex != 2 || opened.TabIndex != 3 || opened.WindowID != "4410" || opened.LoadedURL != "https://example.com/docs/" {
+		t.Fatalf("unexpected opened tab: %#v", opened)
+	}
+}
+
+func TestOpenAndCloseTabAreNotRetried(t *testing.T) {
+	stubRetryDelay(t)
+	t.Setenv(retryEnvVar, "")
+	calls := 0
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		calls++
+		return "", "Connection is invalid. (-609)", errors.New("exit status 1")
+	}))
+	defer restore()
+
+	if _, err := OpenTab(context.Background(), "chrome", "https://example.com"); err == nil {
+		t.Fatalf("expected OpenTab error")
+	}
+	if err := CloseTab(context.Background(), OpenedTab{TabEntry: TabEntry{Browser: "chrome"}, TabID: "812"}); err == nil {
+		t.Fatalf("expected CloseTab error")
+	}
+	if calls != 2 {
+		t.Fatalf("expected one attempt each, got %d calls", calls)
+	}
+}
+
+func TestCloseTabAddressesTabByIdentity(t *testing.T) {
+	var gotArgs [][]string
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		gotArgs = append(gotArgs, args)
+		return "", "", nil
+	}))
+	defer restore()
+
+	chrome := OpenedTab{TabEntry: TabEntry{Browser: "chrome", WindowIndex: 1, TabIndex: 7}, TabID: "812"}
+	if err := CloseTab(context.Background(), chrome); err != nil {
+		t.Fatalf("CloseTab chrome returned error: %v", err)
+	}
+	safari := OpenedTab{TabEntry: TabEntry{Browser: "safari", WindowIndex: 2, TabIndex: 3}, WindowID: "4410", LoadedURL: "https://example.com/docs/"}
+	if err := CloseTab(context.Background(), safari); err != nil {
+		t.Fatalf("CloseTab safari returned error: %v", err)
+	}
+	if got := gotArgs[0][2:]; strings.Join(got, " ") != "812" {
+		t.Fatalf("expected Chrome close by tab id, got %q", got)
+	}
+	if got := gotArgs[1][2:]; strings.Join(got, " ") != "4410 3 https://example.com/docs/" {
+		t.Fatalf("expected Safari close by window id and URL, got %q", got)
+	}
+}
+
+func TestCloseTabRejectsInvalidTargets(t *testing.T) {
+	if err := CloseTab(context.Background(), OpenedTab{TabEntry: TabEntry{Browser: "firefox"}, TabID: "1"}); err == nil {
+		t.Fatalf("expected error for unsupported browser")
+	}
+	if err := CloseTab(context.Background(), OpenedTab{TabEntry: TabEntry{Browser: "chrome", WindowIndex: 1, TabIndex: 1}}); err == nil {
+		t.Fatalf("expected error for a Chrome tab without an id")
+	}
+	if err := CloseTab(context.Background(), OpenedTab{TabEntry: TabEntry{Browser: "safari", WindowIndex: 1, TabIndex: 1}}); err == nil {
+		t.Fatalf("expected error for a Safari tab without a window id")
+	}
+	if _, err := OpenTab(context.Background(), "safari", "  "); err == nil {
+		t.Fatalf("expected error for empty url")
+	}
+}
@@ -0,0 +1,67 @@
+package osascript
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PageText is the visible text of a browser tab read via AppleScript.
+type PageText struct {
+	Title string
+	URL   string
+	Text  string
+}
+
+// SafariPageText reads document.body.innerText from the current tab of a
+// Safari-family browser (e.g. "safari" or "safari-tp") via `do JavaScript`.
+// It requires "Allow JavaScript from Apple Events" to be enabled in the
+// browser's Developer settings.
+func SafariPageText(ctx context.Context, browser string) (PageText, error) {
+	app, ok := LookupBrowser(browser)
+	if !ok || app.Family != BrowserFamilySafari {
+		return PageText{}, fmt.Errorf("page text is only available for Safari browsers, got %q", browser)
+	}
+	output, err := runAppleScript(ctx, scriptForBrowser(safariPageTextScript, app))
+	if err != nil {
+		return PageText{}, err
+	}
+
+	fields := strings.SplitN(output, fieldSeparator, 3)
+	if len(fields) != 3 {
+		return PageText{}, fmt.Errorf("invalid page text field count %d", len(fields))
+	}
+	return PageText{
+		Title: strings.TrimSpace(fields[0]),
+		URL:   strings.TrimSpace(fields[1]),
+		Text:  strings.TrimSpace(fields[2]),
+	}, nil
+}
+
+const safariPageTextScript = `
+set fieldSep to ASCII character 30
+
+tell application "System Events"
+	if not (exists process "__BROWSER_APP__") then
+		error "__BROWSER_APP__ is not running."
+	end if
+end tell
+
+tell application "__BROWSER_APP__"
+	if (count of windows) is 0 then
+		error "__BROWSER_APP__ has no open windows."
+	end if
+	set tabRef to current tab of front window
+	set pageTitle to ""
+	set pageURL to ""
+	try
+		set pageTitle to name of tabRef as text
+	end try
+	try
+		set pageURL to URL of tabRef as text
+	end try
+	set pageText to do JavaScript "document.body ? document.body.innerText : ''" in tabRef
+end tell
+
+return pageTitle & fieldSep & pageURL & fieldSep & (pageText as text)
+`
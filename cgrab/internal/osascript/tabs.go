@@ -2,33 +2,56 @@ package osascript
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type TabEntry struct {
-	Browser     string `json:"browser"`
-	WindowIndex int    `json:"windowIndex"`
-	TabIndex    int    `json:"tabIndex"`
-	IsActive    bool   `json:"isActive"`
-	Title       string `json:"title"`
-	URL         string `json:"url"`
+	Browser string `json:"browser"`
+	// BrowserBundleID is the bundle identifier of the app that owns the
+	// tab (e.g. com.google.Chrome), matching AppEntry.BundleIdentifier.
+	BrowserBundleID string `json:"browserBundleId,omitempty"`
+	WindowIndex     int    `json:"windowIndex"`
+	TabIndex        int    `json:"tabIndex"`
+	IsActive        bool   `json:"isActive"`
+	Title           string `json:"title"`
+	URL             string `json:"url"`
+	// IsLoading and IsPinned are only reported for Chrome; Safari rows leave
+	// them false.
+	IsLoading bool `json:"isLoading,omitempty"`
+	IsPinned  bool `json:"isPinned,omitempty"`
+	// IsAudible is only known from the browser extension, which reports
+	// tabs playing sound; AppleScript listings leave it false.
+	IsAudible bool `json:"isAudible,omitempty"`
+	// OpenedAt is when the browser extension first saw the tab; it is only
+	// set by extension-backed listings (bridge.ListTabs) and is zero here.
+	OpenedAt time.Time `json:"openedAt,omitzero"`
+	// WebAppName marks a tab in a Chrome app-mode window (an installed web
+	// app, or PWA) with the app's name. It is matched through the web app's
+	// shim process in System Events and left empty when that fails.
+	WebAppName string `json:"webAppName,omitempty"`
 }
 
 func ListTabs(ctx context.Context, browserFilter string) ([]TabEntry, []string, error) {
-	targets, err := resolveTabTargets(browserFilter)
+	targets, err := resolveTabTargets(ctx, browserFilter)
 	if err != nil {
 		return nil, nil, err
 	}
+	if len(targets) == 0 {
+		return []TabEntry{}, nil, nil
+	}
 
 	var allEntries []TabEntry
 	var warnings []string
 	successCount := 0
 
 	for _, browser := range targets {
-		entries, listErr := listTabsForBrowser(ctx, browser)
+		entries, browserWarnings, listErr := listTabsForBrowser(ctx, browser)
+		warnings = append(warnings, browserWarnings...)
 		if listErr != nil {
 			warnings = append(warnings, fmt.Sprintf("%s tabs unavailable: %v", browser, listErr))
 			continue
@@ -45,42 +68,107 @@ func ListTabs(ctx context.Context, browserFilter string) ([]TabEntry, []string,
 	return allEntries, warnings, nil
 }
 
-func resolveTabTargets(browserFilter string) ([]string, error) {
-	normalized := strings.ToLower(strings.TrimSpace(browserFilter))
-	switch normalized {
-	case "":
-		return []string{"safari", "chrome"}, nil
-	case "safari", "chrome":
-		return []string{normalized}, nil
-	default:
-		return nil, fmt.Errorf("unsupported --browser value %q (expected safari or chrome)", browserFilter)
+// noWindowsMarker is returned by the tab scripts when the browser process is
+// running without any windows, so it can be told apart from "not running".
+const noWindowsMarker = "__NO_WINDOWS__"
+
+// ErrBrowserNotRunning is returned by BrowserWindowCount when the browser
+// process is not running.
+var ErrBrowserNotRunning = errors.New("browser is not running")
+
+// BrowserWindowCount returns how many windows browser has open, or
+// ErrBrowserNotRunning when its process is not running.
+func BrowserWindowCount(ctx context.Context, browser string) (int, error) {
+	app, ok := LookupBrowser(browser)
+	if !ok {
+		return 0, fmt.Errorf("unsupported browser %q", browser)
+	}
+	output, err := runAppleScript(ctx, scriptForBrowser(windowCountScript, app))
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s window count %q: %w", app.DisplayName, output, err)
 	}
+	if count < 0 {
+		return 0, ErrBrowserNotRunning
+	}
+	return count, nil
 }
 
-func listTabsForBrowser(ctx context.Context, browser string) ([]TabEntry, error) {
-	script := safariTabsScript
-	if browser == "chrome" {
-		script = chromeTabsScript
+// resolveTabTargets returns the browsers to enumerate for browserFilter. An
+// empty or "all" filter selects every registered browser whose process is
+// running, so release channels are included without compiling scripts
+// against browsers that are not installed.
+func resolveTabTargets(ctx context.Context, browserFilter string) ([]string, error) {
+	if IsAllBrowsers(browserFilter) {
+		return runningBrowserTargets(ctx)
+	}
+	app, ok := LookupBrowser(browserFilter)
+	if !ok {
+		return nil, fmt.Errorf(
+			"unsupported --browser value %q (expected one of: %s)",
+			browserFilter,
+			strings.Join(BrowserFilterValues(), ", "),
+		)
 	}
+	return []string{app.Target}, nil
+}
 
-	output, err := runAppleScript(ctx, script)
+// runningBrowserTargets returns, in registry order, every browser target
+// whose application process is running.
+func runningBrowserTargets(ctx context.Context) ([]string, error) {
+	output, err := runReadOnlyAppleScript(ctx, processNamesScript)
 	if err != nil {
 		return nil, err
 	}
-	if strings.TrimSpace(output) == "" {
-		return []TabEntry{}, nil
+	running := map[string]bool{}
+	for _, name := range strings.Split(strings.TrimSpace(output), recordSeparator) {
+		running[strings.TrimSpace(name)] = true
+	}
+	var targets []string
+	for _, target := range BrowserTargets() {
+		if app, _ := LookupBrowser(target); running[app.AppName] {
+			targets = append(targets, target)
+		}
 	}
+	return targets, nil
+}
 
-	entries, err := parseTabEntries(browser, output)
+func listTabsForBrowser(ctx context.Context, browser string) ([]TabEntry, []string, error) {
+	app, ok := LookupBrowser(browser)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported browser %q", browser)
+	}
+	script := safariTabsScript
+	if app.Family == BrowserFamilyChrome {
+		script = chromeTabsScript
+	}
+
+	output, err := runReadOnlyAppleScript(ctx, scriptForBrowser(script, app))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return entries, nil
+	switch strings.TrimSpace(output) {
+	case "":
+		return []TabEntry{}, nil, nil
+	case noWindowsMarker:
+		return []TabEntry{}, []string{fmt.Sprintf("%s is running but has no open windows", app.DisplayName)}, nil
+	}
+
+	entries, warnings := parseTabEntries(browser, output)
+	return entries, warnings, nil
 }
 
-func parseTabEntries(browser string, output string) ([]TabEntry, error) {
+// parseTabEntries decodes the tab script output. A record that cannot be
+// parsed (wrong field count or a non-numeric index) is skipped with a warning
+// so one bad record does not hide the rest of the browser's tabs.
+func parseTabEntries(browser string, output string) ([]TabEntry, []string) {
 	records := strings.Split(output, recordSeparator)
 	entries := make([]TabEntry, 0, len(records))
+	var warnings []string
+	app, _ := LookupBrowser(browser)
 
 	for _, record := range records {
 		record = strings.TrimSpace(record)
@@ -88,41 +176,72 @@ func parseTabEntries(browser string, output string) ([]TabEntry, error) {
 			continue
 		}
 		fields := strings.Split(record, fieldSeparator)
-		if len(fields) != 5 {
-			return nil, fmt.Errorf("invalid tab record field count %d", len(fields))
+		if len(fields) != 5 && !(app.Family == BrowserFamilyChrome && (len(fields) == 7 || len(fields) == 8)) {
+			warnings = append(warnings, fmt.Sprintf("skipped %s tab record with %d fields", browser, len(fields)))
+			continue
 		}
 
 		windowIndex, err := strconv.Atoi(strings.TrimSpace(fields[0]))
 		if err != nil {
-			return nil, fmt.Errorf("invalid window index %q: %w", fields[0], err)
+			warnings = append(warnings, fmt.Sprintf("skipped %s tab record with invalid window index %q", browser, fields[0]))
+			continue
 		}
 		tabIndex, err := strconv.Atoi(strings.TrimSpace(fields[1]))
 		if err != nil {
-			return nil, fmt.Errorf("invalid tab index %q: %w", fields[1], err)
+			warnings = append(warnings, fmt.Sprintf("skipped %s tab record with invalid tab index %q", browser, fields[1]))
+			continue
 		}
 
-		entries = append(entries, TabEntry{
-			Browser:     browser,
-			WindowIndex: windowIndex,
-			TabIndex:    tabIndex,
-			IsActive:    parseAppleScriptBool(fields[2]),
-			Title:       strings.TrimSpace(fields[3]),
-			URL:         strings.TrimSpace(fields[4]),
-		})
+		entry := TabEntry{
+			Browser:         browser,
+			BrowserBundleID: app.BundleID,
+			WindowIndex:     windowIndex,
+			TabIndex:        tabIndex,
+			IsActive:        parseAppleScriptBool(fields[2]),
+			Title:           strings.TrimSpace(fields[3]),
+			URL:             normalizeTabURL(fields[4]),
+		}
+		if len(fields) >= 7 {
+			entry.IsLoading = parseAppleScriptBool(fields[5])
+			entry.IsPinned = parseAppleScriptBool(fields[6])
+		}
+		if len(fields) == 8 {
+			entry.WebAppName = strings.TrimSpace(fields[7])
+		}
+		entries = append(entries, entry)
 	}
 
-	return entries, nil
+	return entries, warnings
 }
 
-func sortTabs(entries []TabEntry) {
-	browserRank := map[string]int{
-		"safari": 0,
-		"chrome": 1,
+// internalURLPrefixes are browser-internal pages (start pages, new tabs) that
+// carry no useful location. Their URLs are reported as empty.
+var internalURLPrefixes = []string{
+	"favorites://",
+	"topsites://",
+	"bookmarks://",
+	"history://",
+	"about:blank",
+	"chrome://newtab",
+	"chrome://new-tab-page",
+	"chrome-search://",
+}
+
+func normalizeTabURL(raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	lowered := strings.ToLower(trimmed)
+	for _, prefix := range internalURLPrefixes {
+		if strings.HasPrefix(lowered, prefix) {
+			return ""
+		}
 	}
+	return trimmed
+}
 
+func sortTabs(entries []TabEntry) {
 	sort.SliceStable(entries, func(i, j int) bool {
-		leftRank := browserRank[entries[i].Browser]
-		rightRank := browserRank[entries[j].Browser]
+		leftRank := browserRank(entries[i].Browser)
+		rightRank := browserRank(entries[j].Browser)
 		if leftRank != rightRank {
 			return leftRank < rightRank
 		}
@@ -138,19 +257,44 @@ func parseAppleScriptBool(value string) bool {
 	return normalized == "true" || normalized == "yes" || normalized == "1"
 }
 
+const processNamesScript = `
+tell application "System Events"
+	set processNames to name of every application process
+end tell
+set AppleScript's text item delimiters to (ASCII character 31)
+set joined to processNames as text
+set AppleScript's text item delimiters to ""
+return joined
+`
+
+const windowCountScript = `
+tell application "System Events"
+	if not (exists process "__BROWSER_APP__") then
+		return "-1"
+	end if
+end tell
+
+tell application "__BROWSER_APP__"
+	return (count of windows) as text
+end tell
+`
+
 const safariTabsScript = `
 set fieldSep to ASCII character 30
 set rowSep to ASCII character 31
 set resultRows to {}
 
 tell application "System Events"
-	if not (exists process "Safari") then
+	if not (exists process "__BROWSER_APP__") then
 		return ""
 	end if
 end tell
 
-tell application "Safari"
+tell application "__BROWSER_APP__"
 	set windowCount to count of windows
+	if windowCount is 0 then
+		return "__NO_WINDOWS__"
+	end if
 	repeat with windowIndex from 1 to windowCount
 		set tabCount to count of tabs of window windowIndex
 		set activeIndex to index of current tab of window windowIndex
@@ -165,7 +309,7 @@ tell application "Safari"
 				set tabURL to URL of tabRef as text
 			end try
 			set activeText to ((tabIndex is activeIndex) as text)
-			set end of resultRows to (windowIndex as text) & fieldSep & (tabIndex as text) & fieldSep & activeText & fieldSep & tabTitle & fieldSep & tabURL
+			set end of resultRows to (windowIndex as text) & fieldSep & (tabIndex as text) & fieldSep & activeText & fieldSep & my stripSeparators(tabTitle) & fieldSep & my stripSeparators(tabURL)
 		end repeat
 	end repeat
 end tell
@@ -181,24 +325,58 @@ on joinRows(values, separator)
 	set AppleScript's text item delimiters to ""
 	return joined
 end joinRows
-`
+` + stripSeparatorsHandler
 
 const chromeTabsScript = `
 set fieldSep to ASCII character 30
 set rowSep to ASCII character 31
 set resultRows to {}
+set webAppWindowTitles to {}
+set webAppWindowApps to {}
 
 tell application "System Events"
-	if not (exists process "Google Chrome") then
+	if not (exists process "__BROWSER_APP__") then
 		return ""
 	end if
+	-- Installed web apps (PWAs) run app-mode windows through shim processes
+	-- whose bundle identifiers extend the browser's with ".app.".
+	try
+		repeat with shimProcess in (every process whose bundle identifier starts with "__BROWSER_BUNDLE_ID__.app.")
+			set shimName to name of shimProcess as text
+			try
+				repeat with shimWindow in windows of shimProcess
+					set end of webAppWindowTitles to (name of shimWindow as text)
+					set end of webAppWindowApps to shimName
+				end repeat
+			end try
+		end repeat
+	end try
 end tell
 
-tell application "Google Chrome"
+tell application "__BROWSER_APP__"
 	set windowCount to count of windows
+	if windowCount is 0 then
+		return "__NO_WINDOWS__"
+	end if
 	repeat with windowIndex from 1 to windowCount
 		set tabCount to count of tabs of window windowIndex
 		set activeIndex to active tab index of window windowIndex
+		-- App-mode windows have a single tab and share their title with the
+		-- web app's shim window.
+		set webAppName to ""
+		if tabCount is 1 and (count of webAppWindowTitles) > 0 then
+			try
+				set windowTitle to name of window windowIndex as text
+				if windowTitle is not "" then
+					repeat with webAppIndex from 1 to count of webAppWindowTitles
+						if (item webAppIndex of webAppWindowTitles) contains windowTitle then
+							set webAppName to item webAppIndex of webAppWindowApps
+							exit repeat
+						end if
+					end repeat
+				end if
+			end try
+		end if
 		repeat with tabIndex from 1 to tabCount
 			set tabRef to tab tabIndex of window windowIndex
 			set tabTitle to ""
@@ -210,7 +388,13 @@ tell application "Google Chrome"
 				set tabURL to URL of tabRef as text
 			end try
 			set activeText to ((tabIndex is activeIndex) as text)
-			set end of resultRows to (windowIndex as text) & fieldSep & (tabIndex as text) & fieldSep & activeText & fieldSep & tabTitle & fieldSep & tabURL
+			set loadingText to "false"
+			try
+				set loadingText to (loading of tabRef) as text
+			end try
+			-- Chrome's scripting dictionary does not expose pinned state yet.
+			set pinnedText to "false"
+			set end of resultRows to (windowIndex as text) & fieldSep & (tabIndex as text) & fieldSep & activeText & fieldSep & my stripSeparators(tabTitle) & fieldSep & my stripSeparators(tabURL) & fieldSep & loadingText & fieldSep & pinnedText & fieldSep & my stripSeparators(webAppName)
 		end repeat
 	end repeat
 end tell
@@ -226,4 +410,4 @@ on joinRows(values, separator)
 	set AppleScript's text item delimiters to ""
 	return joined
 end joinRows
-`
+` + stripSeparatorsHandler
@@ -13,16 +13,22 @@ func (m mockScriptRunner) Run(ctx context.Context, name string, args ...string)
 	return m(ctx, name, args...)
 }
 
+// runningProcesses answers processNamesScript with names, so tests can
+// choose which browsers an "all" listing enumerates.
+func runningProcesses(script string, names ...string) (string, bool) {
+	if script != processNamesScript {
+		return "", false
+	}
+	return strings.Join(names, recordSeparator), true
+}
+
 func TestParseTabEntries(t *testing.T) {
 	raw := strings.Join([]string{
 		"1" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Home" + fieldSeparator + "https://example.com",
 		"1" + fieldSeparator + "2" + fieldSeparator + "false" + fieldSeparator + "Docs" + fieldSeparator + "https://example.com/docs",
 	}, recordSeparator)
 
-	entries, err := parseTabEntries("safari", raw)
-	if err != nil {
-		t.Fatalf("parseTabEntries returned error: %v", err)
-	}
+	entries, _ := parseTabEntries("safari", raw)
 	if len(entries) != 2 {
 		t.Fatalf("expected 2 entries, got %d", len(entries))
 	}
@@ -34,9 +40,108 @@ func TestParseTabEntries(t *testing.T) {
 	}
 }
 
+func TestParseTabEntriesSetsBrowserBundleID(t *testing.T) {
+	record := "1" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Home" + fieldSeparator + "https://example.com"
+	cases := map[string]string{
+		"safari":        "com.apple.Safari",
+		"chrome":        "com.google.Chrome",
+		"chrome-canary": "com.google.Chrome.canary",
+	}
+	for browser, want := range cases {
+		entries, _ := parseTabEntries(browser, record)
+		if len(entries) != 1 || entries[0].BrowserBundleID != want {
+			t.Fatalf("browser=%q: expected bundle id %q, got %#v", browser, want, entries)
+		}
+	}
+}
+
+func TestParseTabEntriesReadsChromeLoadingAndPinned(t *testing.T) {
+	raw := "1" + fieldSeparator + "1" + fieldSeparator + "false" + fieldSeparator + "Slow" + fieldSeparator +
+		"https://example.com/slow" + fieldSeparator + "true" + fieldSeparator + "false"
+
+	entries, _ := parseTabEntries("chrome", raw)
+	if len(entries) != 1 || !entries[0].IsLoading || entries[0].IsPinned {
+		t.Fatalf("unexpected chrome entry: %#v", entries)
+	}
+
+	entries, warnings := parseTabEntries("safari", raw)
+	if len(entries) != 0 || len(warnings) != 1 {
+		t.Fatalf("expected safari row with status fields to be skipped, got %#v %v", entries, warnings)
+	}
+}
+
+func TestParseTabEntriesReadsChromeWebAppName(t *testing.T) {
+	raw := "2" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Inbox" + fieldSeparator +
+		"https://mail.example.com" + fieldSeparator + "false" + fieldSeparator + "false" + fieldSeparator + "Mail"
+
+	entries, warnings := parseTabEntries("chrome", raw)
+	if len(entries) != 1 || entries[0].WebAppName != "Mail" || len(warnings) != 0 {
+		t.Fatalf("unexpected chrome web app entry: %#v %v", entries, warnings)
+	}
+}
+
+func TestChromeTabsScriptMatchesWebAppShimsOfItsChannel(t *testing.T) {
+	app, _ := LookupBrowser("chrome-beta")
+	script := scriptForBrowser(chromeTabsScript, app)
+	if !strings.Contains(script, `bundle identifier starts with "com.google.Chrome.beta.app."`) {
+		t.Fatalf("expected web app shims of Chrome Beta to be matched, got:\n%s", script)
+	}
+	if strings.Contains(script, browserBundleIDPlaceholder) {
+		t.Fatalf("expected bundle id placeholder to be substituted")
+	}
+}
+
+func TestParseTabEntriesSkipsMalformedRecordsWithWarning(t *testing.T) {
+	raw := strings.Join([]string{
+		"1" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Home" + fieldSeparator + "https://example.com",
+		"1" + fieldSeparator + "2" + fieldSeparator + "false" + fieldSeparator + "Broken" + fieldSeparator + "extra" + fieldSeparator + "https://example.com/broken",
+		"1" + fieldSeparator + "3" + fieldSeparator + "false" + fieldSeparator + "Docs" + fieldSeparator + "https://example.com/docs",
+	}, recordSeparator)
+
+	entries, warnings := parseTabEntries("safari", raw)
+	if len(entries) != 2 || entries[1].Title != "Docs" {
+		t.Fatalf("expected malformed record skipped, got %#v", entries)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipped safari tab record with 6 fields") {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestParseTabEntriesSkipsRecordsWithInvalidIndexes(t *testing.T) {
+	raw := strings.Join([]string{
+		"x" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Bad window" + fieldSeparator + "https://example.com/a",
+		"1" + fieldSeparator + "two" + fieldSeparator + "false" + fieldSeparator + "Bad tab" + fieldSeparator + "https://example.com/b",
+		"1" + fieldSeparator + "3" + fieldSeparator + "false" + fieldSeparator + "Docs" + fieldSeparator + "https://example.com/docs",
+	}, recordSeparator)
+
+	entries, warnings := parseTabEntries("chrome", raw)
+	if len(entries) != 1 || entries[0].Title != "Docs" {
+		t.Fatalf("expected only the valid record, got %#v", entries)
+	}
+	if len(warnings) != 2 ||
+		warnings[0] != `skipped chrome tab record with invalid window index "x"` ||
+		warnings[1] != `skipped chrome tab record with invalid tab index "two"` {
+		t.Fatalf("unexpected warnings: %q", warnings)
+	}
+}
+
+func TestTabScriptsStripSeparatorsFromFreeText(t *testing.T) {
+	for name, script := range map[string]string{"safari": safariTabsScript, "chrome": chromeTabsScript, "apps": appsScript, "frontmost": frontmostAppScript} {
+		if !strings.Contains(script, "on stripSeparators(value)") {
+			t.Fatalf("%s script missing stripSeparators handler", name)
+		}
+	}
+	if !strings.Contains(safariTabsScript, "my stripSeparators(tabTitle)") || !strings.Contains(chromeTabsScript, "my stripSeparators(tabURL)") {
+		t.Fatalf("expected tab titles and URLs to be sanitized")
+	}
+}
+
 func TestListTabsPartialFailureStillReturnsSuccess(t *testing.T) {
 	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
 		script := args[len(args)-1]
+		if names, ok := runningProcesses(script, "Safari", "Google Chrome"); ok {
+			return names, "", nil
+		}
 		if strings.Contains(script, `tell application "Safari"`) {
 			record := "1" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Home" + fieldSeparator + "https://example.com"
 			return record, "", nil
@@ -61,7 +166,10 @@ func TestListTabsPartialFailureStillReturnsSuccess(t *testing.T) {
 }
 
 func TestListTabsAllFailuresReturnError(t *testing.T) {
-	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, _ ...string) (string, string, error) {
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		if names, ok := runningProcesses(args[len(args)-1], "Safari", "Google Chrome"); ok {
+			return names, "", nil
+		}
 		return "", "bridge unavailable", errors.New("failed")
 	}))
 	defer restore()
@@ -74,3 +182,166 @@ func TestListTabsAllFailuresReturnError(t *testing.T) {
 		t.Fatalf("expected warnings for safari and chrome, got %d", len(warnings))
 	}
 }
+
+func TestListTabsTargetsChromeChannelByAppName(t *testing.T) {
+	var scripts []string
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		scripts = append(scripts, args[len(args)-1])
+		record := "1" + fieldSeparator + "1" + fieldSeparator + "true" + fieldSeparator + "Beta" + fieldSeparator + "https://example.com"
+		return record, "", nil
+	}))
+	defer restore()
+
+	entries, _, err := ListTabs(context.Background(), "chrome-beta")
+	if err != nil {
+		t.Fatalf("ListTabs returned error: %v", err)
+	}
+	if len(scripts) != 1 || !strings.Contains(scripts[0], `tell application "Google Chrome Beta"`) {
+		t.Fatalf("expected one script targeting Google Chrome Beta, got %q", scripts)
+	}
+	if strings.Contains(scripts[0], browserAppPlaceholder) {
+		t.Fatalf("expected app placeholder to be substituted, got %q", scripts[0])
+	}
+	if len(entries) != 1 || entries[0].Browser != "chrome-beta" {
+		t.Fatalf("expected one chrome-beta entry, got %#v", entries)
+	}
+}
+
+func TestAppNameEnvOverridesScriptAndActivateTargets(t *testing.T) {
+	t.Setenv("CONTEXT_GRABBER_CHROME_APP_NAME", "Chromium Fork")
+	t.Setenv("CONTEXT_GRABBER_SAFARI_APP_NAME", " ")
+	var scripts []string
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		if names, ok := runningProcesses(args[len(args)-1], "Safari", "Chromium Fork", "Google Chrome"); ok {
+			return names, "", nil
+		}
+		scripts = append(scripts, strings.Join(args, " "))
+		return "", "", nil
+	}))
+	defer restore()
+
+	if _, _, err := ListTabs(context.Background(), ""); err != nil {
+		t.Fatalf("ListTabs returned error: %v", err)
+	}
+	if err := ActivateTab(context.Background(), "chrome", 1, 2); err != nil {
+		t.Fatalf("ActivateTab returned error: %v", err)
+	}
+	if len(scripts) != 3 || !strings.Contains(scripts[0], `tell application "Safari"`) {
+		t.Fatalf("expected blank Safari override to be ignored, got %q", scripts)
+	}
+	for _, script := range scripts[1:] {
+		if !strings.Contains(script, `tell application "Chromium Fork"`) || strings.Contains(script, "Google Chrome") {
+			t.Fatalf("expected Chrome scripts to target the overridden app, got %q", script)
+		}
+	}
+	if app, _ := LookupBrowser("chrome-beta"); app.AppName != "Google Chrome Beta" {
+		t.Fatalf("expected release channels to keep their app names, got %q", app.AppName)
+	}
+}
+
+func TestListTabsWarnsWhenBrowserHasNoWindows(t *testing.T) {
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		if names, ok := runningProcesses(args[len(args)-1], "Safari", "Google Chrome"); ok {
+			return names, "", nil
+		}
+		if strings.Contains(args[len(args)-1], `tell application "Google Chrome"`) {
+			return noWindowsMarker + "\n", "", nil
+		}
+		return "", "", nil
+	}))
+	defer restore()
+
+	entries, warnings, err := ListTabs(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListTabs returned error: %v", err)
+	}
+	if len(entries) != 0 || len(warnings) != 1 || warnings[0] != "Chrome is running but has no open windows" {
+		t.Fatalf("expected a single no-windows warning, got entries=%#v warnings=%q", entries, warnings)
+	}
+}
+
+func TestBrowserWindowCountDistinguishesNotRunning(t *testing.T) {
+	output := "-1"
+	restore := setRunnerForTesting(mockScriptRunner(func(context.Context, string, ...string) (string, string, error) {
+		return output, "", nil
+	}))
+	defer restore()
+
+	if _, err := BrowserWindowCount(context.Background(), "chrome"); !errors.Is(err, ErrBrowserNotRunning) {
+		t.Fatalf("expected ErrBrowserNotRunning, got %v", err)
+	}
+	output = "0\n"
+	if count, err := BrowserWindowCount(context.Background(), "safari"); err != nil || count != 0 {
+		t.Fatalf("expected zero windows, got %d err=%v", count, err)
+	}
+}
+
+func TestListTabsRejectsUnknownBrowser(t *testing.T) {
+	_, _, err := ListTabs(context.Background(), "firefox")
+	if err == nil || !strings.Contains(err.Error(), "safari-tp") {
+		t.Fatalf("expected error listing supported browsers, got %v", err)
+	}
+}
+
+func TestResolveTabTargetsTreatsAllAsEveryRunningBrowser(t *testing.T) {
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, args ...string) (string, string, error) {
+		names, _ := runningProcesses(args[len(args)-1], "Finder", "Google Chrome Canary", "Safari", "Safari Technology Preview\n")
+		return names, "", nil
+	}))
+	defer restore()
+
+	for _, filter := range []string{"", "all", " ALL "} {
+		targets, err := resolveTabTargets(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("resolveTabTargets(%q) returned error: %v", filter, err)
+		}
+		if strings.Join(targets, ",") != "safari,safari-tp,chrome-canary" {
+			t.Fatalf("resolveTabTargets(%q): expected the running registry browsers, got %v", filter, targets)
+		}
+	}
+}
+
+func TestSafariPageTextSplitsTitleURLAndText(t *testing.T) {
+	restore := setRunnerForTesting(mockScriptRunner(func(_ context.Context, _ string, _ ...string) (string, string, error) {
+		return "Home" + fieldSeparator + "https://example.com" + fieldSeparator + "Line one\nLine two\n", "", nil
+	}))
+	defer restore()
+
+	page, err := SafariPageText(context.Background(), "safari")
+	if err != nil {
+		t.Fatalf("SafariPageText returned error: %v", err)
+	}
+	if page.Title != "Home" || page.URL != "https://example.com" || page.Text != "Line one\nLine two" {
+		t.Fatalf("unexpected page text: %#v", page)
+	}
+}
+
+func TestDefaultRunnerLogsInvocationWhenVerbose(t *testing.T) {
+	var logged strings.Builder
+	SetVerboseLog(&logged)
+	defer SetVerboseLog(nil)
+
+	_, _, _ = defaultScriptRunner{}.Run(context.Background(), "true", "-e", "tell application \"Safari\"\nend tell", "Finder")
+	if logged.String() != "verbose: exec true -e <script 2 lines> \"Finder\"\n" {
+		t.Fatalf("unexpected verbose log: %q", logged.String())
+	}
+}
+
+func TestParseTabEntriesNormalizesInternalURLs(t *testing.T) {
+	raw := strings.Join([]string{
+		"1" + fieldSeparator + "1" + fieldSeparator + "false" + fieldSeparator + "Favorites" + fieldSeparator + "favorites://",
+		"1" + fieldSeparator + "2" + fieldSeparator + "false" + fieldSeparator + "New Tab" + fieldSeparator + "chrome://newtab/",
+		"1" + fieldSeparator + "3" + fieldSeparator + "false" + fieldSeparator + "Docs" + fieldSeparator + " https://example.com/docs \t",
+	}, recordSeparator)
+
+	entries, _ := parseTabEntries("chrome", raw)
+	if entries[0].URL != "" || entries[0].Title != "Favorites" {
+		t.Fatalf("expected internal URL dropped and title kept, got %#v", entries[0])
+	}
+	if entries[1].URL != "" {
+		t.Fatalf("expected new tab URL dropped, got %q", entries[1].URL)
+	}
+	if entries[2].URL != "https://example.com/docs" {
+		t.Fatalf("expected trimmed URL, got %q", entries[2].URL)
+	}
+}
@@ -0,0 +1,34 @@
+package osascript
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var verboseLog io.Writer
+
+// SetVerboseLog makes the default runner log every osascript invocation to w.
+// Script bodies are summarized rather than printed. A nil writer disables
+// logging.
+func SetVerboseLog(w io.Writer) {
+	verboseLog = w
+}
+
+func logInvocation(name string, args []string) {
+	if verboseLog == nil {
+		return
+	}
+	parts := []string{name}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-e" && i+1 < len(args) {
+			lines := len(strings.Split(strings.TrimSpace(args[i+1]), "\n"))
+			parts = append(parts, "-e", fmt.Sprintf("<script %d lines>", lines))
+			i++
+			continue
+		}
+		parts = append(parts, strconv.Quote(args[i]))
+	}
+	fmt.Fprintln(verboseLog, "verbose: exec "+strings.Join(parts, " "))
+}
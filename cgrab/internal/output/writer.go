@@ -1,26 +1,147 @@
 package output
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
-func Write(ctx context.Context, payload []byte, outputFile string, clipboard bool) error {
-	if outputFile != "" {
-		if err := os.WriteFile(outputFile, payload, 0o644); err != nil {
-			return fmt.Errorf("write output file: %w", err)
-		}
+// ChecksumExtension is appended to an output file's name for the checksum
+// file WithChecksumFile writes next to it.
+const ChecksumExtension = ".sha256"
+
+type writeConfig struct {
+	gzip         bool
+	noStdout     bool
+	compactJSON  bool
+	checksumFile bool
+	envelope     *Envelope
+}
+
+// Envelope wraps JSON output in a command-independent shape so generic
+// tooling can read any command's output the same way.
+type Envelope struct {
+	Command     string          `json:"command"`
+	Format      string          `json:"format"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// clipboardTextFunc copies text to the macOS clipboard. Tests stub it to
+// avoid touching the real clipboard.
+var clipboardTextFunc = copyToClipboard
+
+// ClipboardWarning reports a failed clipboard copy after the file and stdout
+// output were already written. Callers can treat it as a warning instead of
+// failing the command.
+type ClipboardWarning struct {
+	Err error
+}
+
+func (w *ClipboardWarning) Error() string {
+	return fmt.Sprintf("clipboard copy failed: %v", w.Err)
+}
+
+func (w *ClipboardWarning) Unwrap() error {
+	return w.Err
+}
+
+// Option adjusts how Write stores output.
+type Option func(*writeConfig)
+
+// WithGzip gzip-compresses the file output. Files whose name ends in .gz are
+// always compressed; stdout and clipboard output never are.
+func WithGzip(enabled bool) Option {
+	return func(config *writeConfig) {
+		config.gzip = enabled
 	}
+}
 
-	if clipboard {
-		if err := copyToClipboard(ctx, payload); err != nil {
+// WithoutStdout skips the stdout copy that is otherwise printed when no
+// output file is given, e.g. for clipboard-only output.
+func WithoutStdout() Option {
+	return func(config *writeConfig) {
+		config.noStdout = true
+	}
+}
+
+// WithCompactJSON rewrites the payload onto a single line. Callers enable it
+// only for JSON output; Write fails if the payload is not a single JSON
+// document.
+func WithCompactJSON(enabled bool) Option {
+	return func(config *writeConfig) {
+		config.compactJSON = enabled
+	}
+}
+
+// WithChecksumFile writes "<file>.sha256" next to the output file, holding
+// the SHA-256 of the exact file bytes (after any envelope, compaction, or
+// gzip) in the "<hex>  <name>" form shasum -a 256 -c checks. It has no
+// effect without an output file.
+func WithChecksumFile(enabled bool) Option {
+	return func(config *writeConfig) {
+		config.checksumFile = enabled
+	}
+}
+
+// WithEnvelope nests the JSON payload under the "data" key of an Envelope
+// describing the command that produced it.
+func WithEnvelope(command string, format string, generatedAt time.Time) Option {
+	return func(config *writeConfig) {
+		config.envelope = &Envelope{Command: command, Format: format, GeneratedAt: generatedAt}
+	}
+}
+
+func Write(ctx context.Context, payload []byte, outputFile string, clipboard bool, opts ...Option) error {
+	config := writeConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.envelope != nil {
+		wrapped, err := wrapEnvelope(*config.envelope, payload)
+		if err != nil {
 			return err
 		}
+		payload = wrapped
+	}
+	if config.compactJSON {
+		compacted, err := compactJSON(payload)
+		if err != nil {
+			return err
+		}
+		payload = compacted
+	}
+
+	if outputFile != "" {
+		filePayload := payload
+		if config.gzip || strings.HasSuffix(strings.ToLower(outputFile), ".gz") {
+			compressed, err := gzipBytes(payload)
+			if err != nil {
+				return err
+			}
+			filePayload = compressed
+		}
+		if err := os.WriteFile(outputFile, filePayload, 0o644); err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+		if config.checksumFile {
+			if err := writeChecksumFile(outputFile, filePayload); err != nil {
+				return err
+			}
+		}
 	}
 
-	if outputFile == "" {
+	if outputFile == "" && !config.noStdout {
 		if _, err := os.Stdout.Write(payload); err != nil {
 			return fmt.Errorf("write stdout: %w", err)
 		}
@@ -31,9 +152,60 @@ func Write(ctx context.Context, payload []byte, outputFile string, clipboard boo
 		}
 	}
 
+	// The clipboard goes last so a missing pbcopy cannot lose output that
+	// was already written.
+	if clipboard {
+		if err := clipboardTextFunc(ctx, payload); err != nil {
+			return &ClipboardWarning{Err: err}
+		}
+	}
+
 	return nil
 }
 
+func writeChecksumFile(outputFile string, payload []byte) error {
+	sum := sha256.Sum256(payload)
+	line := hex.EncodeToString(sum[:]) + "  " + filepath.Base(outputFile) + "\n"
+	if err := os.WriteFile(outputFile+ChecksumExtension, []byte(line), 0o644); err != nil {
+		return fmt.Errorf("write checksum file: %w", err)
+	}
+	return nil
+}
+
+func wrapEnvelope(envelope Envelope, payload []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(payload)
+	if !json.Valid(trimmed) {
+		return nil, fmt.Errorf("--envelope requires JSON output")
+	}
+	envelope.Data = json.RawMessage(trimmed)
+	wrapped, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode output envelope: %w", err)
+	}
+	return append(wrapped, '\n'), nil
+}
+
+func compactJSON(payload []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, bytes.TrimSpace(payload)); err != nil {
+		return nil, fmt.Errorf("compact JSON output: %w", err)
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+func gzipBytes(payload []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, fmt.Errorf("gzip output: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("gzip output: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
 func copyToClipboard(ctx context.Context, payload []byte) error {
 	cmd := exec.CommandContext(ctx, "pbcopy")
 	stdin, err := cmd.StdinPipe()
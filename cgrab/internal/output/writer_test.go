@@ -0,0 +1,221 @@
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readGzipFile(t *testing.T, path string) []byte {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("expected gzip data in %s: %v", path, err)
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompress %s: %v", path, err)
+	}
+	return decompressed
+}
+
+func TestWriteCompressesFilesEndingInGz(t *testing.T) {
+	payload := bytes.Repeat([]byte("# Captured Content\n"), 64)
+	path := filepath.Join(t.TempDir(), "capture.md.gz")
+
+	if err := Write(context.Background(), payload, path, false); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if got := readGzipFile(t, path); !bytes.Equal(got, payload) {
+		t.Fatalf("round-tripped payload mismatch: got %q", got)
+	}
+}
+
+func TestWriteWithGzipCompressesAnyFileName(t *testing.T) {
+	payload := []byte(`{"ok":true}`)
+	path := filepath.Join(t.TempDir(), "capture.json")
+
+	if err := Write(context.Background(), payload, path, false, WithGzip(true)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if got := readGzipFile(t, path); !bytes.Equal(got, payload) {
+		t.Fatalf("round-tripped payload mismatch: got %q", got)
+	}
+}
+
+func TestWriteLeavesPlainFilesUncompressed(t *testing.T) {
+	payload := []byte("plain\n")
+	path := filepath.Join(t.TempDir(), "capture.md")
+
+	if err := Write(context.Background(), payload, path, false, WithGzip(false)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if !bytes.Equal(raw, payload) {
+		t.Fatalf("expected uncompressed payload, got %q", raw)
+	}
+}
+
+func TestWriteWithoutStdoutPrintsNothing(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	previous := os.Stdout
+	os.Stdout = writer
+	t.Cleanup(func() {
+		os.Stdout = previous
+	})
+
+	writeErr := Write(context.Background(), []byte("# Captured\n"), "", false, WithoutStdout())
+	writer.Close()
+	printed, _ := io.ReadAll(reader)
+	if writeErr != nil {
+		t.Fatalf("Write returned error: %v", writeErr)
+	}
+	if len(printed) != 0 {
+		t.Fatalf("expected no stdout output, got %q", printed)
+	}
+}
+
+func TestWriteWithEnvelopeWrapsJSONPayload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tabs.json")
+	generatedAt := time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC)
+	payload := []byte("[\n  {\"title\": \"A\"}\n]\n")
+
+	if err := Write(context.Background(), payload, path, false, WithEnvelope("list tabs", "json", generatedAt)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output file: %v", err)
+	}
+	var envelope Envelope
+	if err := json.Unmarshal(written, &envelope); err != nil {
+		t.Fatalf("expected JSON envelope, got %q: %v", written, err)
+	}
+	if envelope.Command != "list tabs" || envelope.Format != "json" || !envelope.GeneratedAt.Equal(generatedAt) {
+		t.Fatalf("unexpected envelope metadata: %+v", envelope)
+	}
+	if !bytes.Contains(envelope.Data, []byte(`"title"`)) {
+		t.Fatalf("expected payload under data, got %s", envelope.Data)
+	}
+}
+
+func TestWriteWithEnvelopeRejectsNonJSONPayload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.md")
+	err := Write(context.Background(), []byte("# Captured\n"), path, false, WithEnvelope("capture", "markdown", time.Now()))
+	if err == nil {
+		t.Fatal("expected error for non-JSON payload")
+	}
+}
+
+func TestWriteClipboardFailureIsWarningAfterFileWrite(t *testing.T) {
+	previous := clipboardTextFunc
+	t.Cleanup(func() {
+		clipboardTextFunc = previous
+	})
+	clipboardTextFunc = func(context.Context, []byte) error {
+		return errors.New(`exec: "pbcopy": executable file not found in $PATH`)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "capture.md")
+	err := Write(context.Background(), []byte("# Captured\n"), outputPath, true)
+	var warning *ClipboardWarning
+	if !errors.As(err, &warning) {
+		t.Fatalf("expected ClipboardWarning, got %v", err)
+	}
+	written, readErr := os.ReadFile(outputPath)
+	if readErr != nil || string(written) != "# Captured\n" {
+		t.Fatalf("expected file written before clipboard failure, got %q err=%v", written, readErr)
+	}
+}
+
+func TestJSONToYAMLKeepsKeyOrderAndQuotesAmbiguousStrings(t *testing.T) {
+	rendered, err := JSONToYAML([]byte(`{"tabs":[{"title":"a: b","count":"123","n":1}],"apps":[]}`))
+	if err != nil {
+		t.Fatalf("JSONToYAML returned error: %v", err)
+	}
+	want := "tabs:\n  - title: 'a: b'\n    count: \"123\"\n    n: 1\napps: []\n"
+	if string(rendered) != want {
+		t.Fatalf("unexpected YAML:\nwant: %q\ngot:  %q", want, rendered)
+	}
+}
+
+func TestYAMLToJSONRoundTripsJSONToYAML(t *testing.T) {
+	original := `{"markdown":"# Doc\n","capturedAt":"2026-02-15T13:30:45Z","count":"123","tabs":[{"n":1,"ok":true}],"empty":null}`
+	rendered, err := JSONToYAML([]byte(original))
+	if err != nil {
+		t.Fatalf("JSONToYAML returned error: %v", err)
+	}
+	converted, err := YAMLToJSON(rendered)
+	if err != nil {
+		t.Fatalf("YAMLToJSON returned error: %v", err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, converted); err != nil {
+		t.Fatalf("YAMLToJSON produced invalid JSON %q: %v", converted, err)
+	}
+	if compact.String() != original {
+		t.Fatalf("unexpected round trip:\nwant: %s\ngot:  %s", original, compact.String())
+	}
+}
+
+func TestWriteWithCompactJSONPrintsSingleLine(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "out.json")
+	if err := Write(context.Background(), []byte("{\n  \"a\": [\n    1,\n    2\n  ]\n}"), jsonPath, false, WithCompactJSON(true)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	written, _ := os.ReadFile(jsonPath)
+	if string(written) != "{\"a\":[1,2]}\n" {
+		t.Fatalf("expected compact JSON, got %q", written)
+	}
+
+	markdownPath := filepath.Join(dir, "out.md")
+	if err := Write(context.Background(), []byte("# Title\n"), markdownPath, false, WithCompactJSON(true)); err == nil {
+		t.Fatalf("expected compacting a non-JSON payload to fail")
+	}
+	if _, err := os.Stat(markdownPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no file after a failed compaction, got %v", err)
+	}
+}
+
+func TestWriteWithChecksumFileHashesWrittenBytes(t *testing.T) {
+	payload := []byte("# Captured Content\n")
+	path := filepath.Join(t.TempDir(), "capture.md.gz")
+
+	if err := Write(context.Background(), payload, path, false, WithChecksumFile(true)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	checksum, err := os.ReadFile(path + ChecksumExtension)
+	if err != nil {
+		t.Fatalf("read checksum file: %v", err)
+	}
+	sum := sha256.Sum256(written)
+	if want := hex.EncodeToString(sum[:]) + "  capture.md.gz\n"; string(checksum) != want {
+		t.Fatalf("unexpected checksum file: want %q, got %q", want, checksum)
+	}
+}
@@ -0,0 +1,120 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONToYAML re-encodes a JSON document as block-style YAML. Going through
+// JSON keeps the json struct tags as the single source of field names and
+// order, so YAML output matches --format json key for key.
+func JSONToYAML(payload []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(payload, &document); err != nil {
+		return nil, fmt.Errorf("convert output to YAML: %w", err)
+	}
+	if document.Kind == 0 {
+		return []byte("null\n"), nil
+	}
+	clearFlowStyle(&document)
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("convert output to YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("convert output to YAML: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// clearFlowStyle drops the flow and quoting styles the JSON parse leaves on
+// every node so the encoder picks block style and quotes only when needed.
+// Non-empty collections become block style; empty ones stay [] and {}.
+func clearFlowStyle(node *yaml.Node) {
+	if (node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode) && len(node.Content) == 0 {
+		node.Style = yaml.FlowStyle
+	} else {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		clearFlowStyle(child)
+	}
+}
+
+// YAMLToJSON re-encodes a YAML document, such as one written by JSONToYAML,
+// as indented JSON. Mapping keys keep their document order.
+func YAMLToJSON(payload []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(payload, &document); err != nil {
+		return nil, fmt.Errorf("convert YAML to JSON: %w", err)
+	}
+	if document.Kind == 0 {
+		return []byte("null\n"), nil
+	}
+	var compact bytes.Buffer
+	if err := writeJSONNode(&compact, &document); err != nil {
+		return nil, fmt.Errorf("convert YAML to JSON: %w", err)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, fmt.Errorf("convert YAML to JSON: %w", err)
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+func writeJSONNode(buffer *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeJSONNode(buffer, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSONNode(buffer, node.Alias)
+	case yaml.MappingNode:
+		buffer.WriteByte('{')
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[index].Value)
+			if err != nil {
+				return err
+			}
+			buffer.Write(key)
+			buffer.WriteByte(':')
+			if err := writeJSONNode(buffer, node.Content[index+1]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buffer.WriteByte('[')
+		for index, child := range node.Content {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSONNode(buffer, child); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+		return nil
+	default:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buffer.Write(encoded)
+		return nil
+	}
+}
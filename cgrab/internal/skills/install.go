@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/anthonylu23/context_grabber/cgrab/internal/config"
 )
 
 // AgentTarget identifies an AI coding agent for skill installation.
@@ -36,10 +38,44 @@ type InstallResult struct {
 	Symlinks []string
 }
 
-// globalSkillRoot returns the canonical global skill directory.
-// ~/.agents/skills/context-grabber
+// SkillRootEnvVar relocates the canonical global skill directory. It takes
+// precedence over the skill-root config setting.
+const SkillRootEnvVar = "CONTEXT_GRABBER_SKILL_ROOT"
+
+// globalSkillRoot returns the canonical global skill directory:
+// CONTEXT_GRABBER_SKILL_ROOT, then the skill-root setting, then
+// ~/.agents/skills/context-grabber. Install, uninstall, and symlink checks
+// all resolve through it so they agree on the same target.
 func globalSkillRoot() string {
-	return filepath.Join(homeDir(), ".agents", "skills", "context-grabber")
+	root := strings.TrimSpace(os.Getenv(SkillRootEnvVar))
+	if root == "" {
+		root = configuredSkillRootFunc()
+	}
+	if root == "" {
+		return filepath.Join(homeDir(), ".agents", "skills", "context-grabber")
+	}
+	if root == "~" {
+		return homeDir()
+	}
+	if rest, ok := strings.CutPrefix(root, "~/"); ok {
+		return filepath.Join(homeDir(), rest)
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		return abs
+	}
+	return root
+}
+
+// configuredSkillRootFunc reads the skill-root config setting. Overridable in
+// tests so they do not depend on the real config file.
+var configuredSkillRootFunc = defaultConfiguredSkillRoot
+
+func defaultConfiguredSkillRoot() string {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return ""
+	}
+	return settings.SkillRoot
 }
 
 // ResolveTargetDir returns the filesystem path where skill files should be
@@ -358,7 +358,13 @@ func setTestHome(t *testing.T) string {
 	tmpHome := t.TempDir()
 	original := homeDirFunc
 	homeDirFunc = func() string { return tmpHome }
-	t.Cleanup(func() { homeDirFunc = original })
+	originalConfigured := configuredSkillRootFunc
+	configuredSkillRootFunc = func() string { return "" }
+	t.Setenv(SkillRootEnvVar, "")
+	t.Cleanup(func() {
+		homeDirFunc = original
+		configuredSkillRootFunc = originalConfigured
+	})
 	return tmpHome
 }
 
@@ -559,3 +565,53 @@ func TestUninstallGlobal_AllAgents_RemovesCanonical(t *testing.T) {
 		t.Errorf("expected OpenCode symlink to be removed")
 	}
 }
+
+func TestGlobalInstallUsesSkillRootOverride(t *testing.T) {
+	tmpHome := setTestHome(t)
+	customRoot := filepath.Join(tmpHome, "dotfiles", "agent-skills", "context-grabber")
+	t.Setenv(SkillRootEnvVar, customRoot)
+
+	if _, err := Install([]AgentTarget{AgentClaude, AgentOpenCode}, ScopeGlobal, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, relPath := range SkillFileList {
+		if _, err := os.Stat(filepath.Join(customRoot, relPath)); err != nil {
+			t.Errorf("expected canonical file under custom root: %v", err)
+		}
+	}
+	claudeDir := filepath.Join(tmpHome, ".claude", "skills", "context-grabber")
+	if target, err := os.Readlink(claudeDir); err != nil || target != customRoot {
+		t.Fatalf("expected claude symlink to %s, got %q (%v)", customRoot, target, err)
+	}
+
+	// Claude's uninstall must see OpenCode's symlink to the custom root.
+	results, err := Uninstall([]AgentTarget{AgentClaude}, ScopeGlobal, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || len(results[0].Symlinks) != 1 || len(results[0].Paths) != 0 {
+		t.Fatalf("expected symlink removal with canonical preserved, got %+v", results)
+	}
+
+	results, err = Uninstall([]AgentTarget{AgentOpenCode}, ScopeGlobal, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results[0].Paths) == 0 {
+		t.Fatalf("expected canonical files removed with the last symlink")
+	}
+}
+
+func TestGlobalSkillRootResolution(t *testing.T) {
+	tmpHome := setTestHome(t)
+
+	configuredSkillRootFunc = func() string { return "~/shared/skills" }
+	if got, want := globalSkillRoot(), filepath.Join(tmpHome, "shared", "skills"); got != want {
+		t.Fatalf("expected config skill root %q, got %q", want, got)
+	}
+
+	t.Setenv(SkillRootEnvVar, "/opt/skills/context-grabber")
+	if got := globalSkillRoot(); got != "/opt/skills/context-grabber" {
+		t.Fatalf("expected env to take precedence, got %q", got)
+	}
+}
@@ -0,0 +1,289 @@
+// Package textdiff renders line-based unified diffs between two texts.
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change,
+// matching diff -u.
+const contextLines = 3
+
+// MaxLines bounds the changed region Unified will compare: the lines of
+// both texts left after their common prefix and suffix. Memory is linear,
+// but time grows with the size times the number of edits.
+const MaxLines = 20000
+
+// ErrTooLarge is returned (wrapped) by Unified when the changed region is
+// larger than MaxLines.
+var ErrTooLarge = errors.New("texts are too large to diff")
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// op is one line of the edit script. fromLine and toLine are the zero-based
+// positions in each text before the op is applied.
+type op struct {
+	kind     opKind
+	text     string
+	fromLine int
+	toLine   int
+}
+
+// Unified returns a unified diff turning from into to, labelled with
+// fromName and toName. It returns "" when the texts are equal, and
+// ErrTooLarge when the changed region is larger than MaxLines.
+func Unified(fromName string, toName string, from string, to string) (string, error) {
+	if from == to {
+		return "", nil
+	}
+	fromLines, toLines := splitLines(from), splitLines(to)
+	prefix, suffix := commonEnds(fromLines, toLines)
+	if changed := len(fromLines) + len(toLines) - 2*(prefix+suffix); changed > MaxLines {
+		return "", fmt.Errorf("%w: %d changed lines (limit %d)", ErrTooLarge, changed, MaxLines)
+	}
+	ops := editScript(fromLines, toLines)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range groupHunks(ops) {
+		writeHunk(&builder, ops[hunk[0]:hunk[1]])
+	}
+	return builder.String(), nil
+}
+
+// commonEnds returns how many leading and trailing lines from and to share,
+// without counting a line twice.
+func commonEnds(from []string, to []string) (int, int) {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+// splitLines splits text after each newline, keeping the newlines so a
+// missing final newline counts as a change.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// editScript computes a shortest edit script with the linear-space
+// refinement of Myers' O(ND) algorithm: each middle snake splits the texts
+// in two, so only two diagonal vectors are kept instead of one per edit.
+// Within each run of changes, deletions are listed before insertions.
+func editScript(from []string, to []string) []op {
+	offset := (len(from)+len(to)+1)/2 + 1
+	d := &differ{
+		from:     from,
+		to:       to,
+		forward:  make([]int, 2*offset+1),
+		backward: make([]int, 2*offset+1),
+		offset:   offset,
+		ops:      make([]op, 0, len(from)+len(to)),
+	}
+	d.compare(0, len(from), 0, len(to))
+	return orderChanges(d.ops)
+}
+
+type differ struct {
+	from     []string
+	to       []string
+	forward  []int
+	backward []int
+	offset   int
+	ops      []op
+}
+
+func (d *differ) equal(fromLine int, toLine int) {
+	d.ops = append(d.ops, op{kind: opEqual, text: d.from[fromLine], fromLine: fromLine, toLine: toLine})
+}
+
+// compare appends the ops turning from[fromStart:fromEnd] into
+// to[toStart:toEnd].
+func (d *differ) compare(fromStart int, fromEnd int, toStart int, toEnd int) {
+	prefix, suffix := commonEnds(d.from[fromStart:fromEnd], d.to[toStart:toEnd])
+	for i := 0; i < prefix; i++ {
+		d.equal(fromStart+i, toStart+i)
+	}
+	fromStart, toStart = fromStart+prefix, toStart+prefix
+	fromEnd, toEnd = fromEnd-suffix, toEnd-suffix
+
+	switch {
+	case fromStart == fromEnd:
+		for y := toStart; y < toEnd; y++ {
+			d.ops = append(d.ops, op{kind: opInsert, text: d.to[y], fromLine: fromStart, toLine: y})
+		}
+	case toStart == toEnd:
+		for x := fromStart; x < fromEnd; x++ {
+			d.ops = append(d.ops, op{kind: opDelete, text: d.from[x], fromLine: x, toLine: toStart})
+		}
+	default:
+		snakeFromStart, snakeToStart, snakeFromEnd, snakeToEnd := d.middleSnake(fromStart, fromEnd, toStart, toEnd)
+		d.compare(fromStart, snakeFromStart, toStart, snakeToStart)
+		for i := 0; i < snakeFromEnd-snakeFromStart; i++ {
+			d.equal(snakeFromStart+i, snakeToStart+i)
+		}
+		d.compare(snakeFromEnd, fromEnd, snakeToEnd, toEnd)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.equal(fromEnd+i, toEnd+i)
+	}
+}
+
+// middleSnake runs the forward and reverse searches over the ranges until
+// they overlap and returns the absolute start and end of the snake where
+// they meet. Both ranges must be non-empty and differ at both ends, so the
+// snake always splits the problem into two smaller ones.
+func (d *differ) middleSnake(fromStart int, fromEnd int, toStart int, toEnd int) (int, int, int, int) {
+	n, m := fromEnd-fromStart, toEnd-toStart
+	delta := n - m
+	odd := delta%2 != 0
+	forward, backward, offset := d.forward, d.backward, d.offset
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	for edits := 0; edits <= (n+m+1)/2; edits++ {
+		for k := -edits; k <= edits; k += 2 {
+			var x int
+			if k == -edits || (k != edits && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			snakeX := x
+			for x < n && x-k < m && d.from[fromStart+x] == d.to[toStart+x-k] {
+				x++
+			}
+			forward[offset+k] = x
+			if odd && delta-k >= -(edits-1) && delta-k <= edits-1 && x+backward[offset+delta-k] >= n {
+				return fromStart + snakeX, toStart + snakeX - k, fromStart + x, toStart + x - k
+			}
+		}
+		// The reverse search walks both ranges from their ends; its
+		// diagonal k meets the forward diagonal delta-k.
+		for k := -edits; k <= edits; k += 2 {
+			var x int
+			if k == -edits || (k != edits && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			snakeX := x
+			for x < n && x-k < m && d.from[fromEnd-1-x] == d.to[toEnd-1-(x-k)] {
+				x++
+			}
+			backward[offset+k] = x
+			if !odd && delta-k >= -edits && delta-k <= edits && x+forward[offset+delta-k] >= n {
+				return fromEnd - x, toEnd - (x - k), fromEnd - snakeX, toEnd - (snakeX - k)
+			}
+		}
+	}
+	panic("textdiff: middle snake not found")
+}
+
+// orderChanges rewrites each run of consecutive changes as its deletions
+// followed by its insertions, as diff -u prints them.
+func orderChanges(ops []op) []op {
+	ordered := make([]op, 0, len(ops))
+	for start := 0; start < len(ops); {
+		if ops[start].kind == opEqual {
+			ordered = append(ordered, ops[start])
+			start++
+			continue
+		}
+		end := start
+		for end < len(ops) && ops[end].kind != opEqual {
+			end++
+		}
+		fromLine, toLine := ops[start].fromLine, ops[start].toLine
+		deleted := 0
+		for _, current := range ops[start:end] {
+			if current.kind == opDelete {
+				ordered = append(ordered, op{kind: opDelete, text: current.text, fromLine: fromLine + deleted, toLine: toLine})
+				deleted++
+			}
+		}
+		inserted := 0
+		for _, current := range ops[start:end] {
+			if current.kind == opInsert {
+				ordered = append(ordered, op{kind: opInsert, text: current.text, fromLine: fromLine + deleted, toLine: toLine + inserted})
+				inserted++
+			}
+		}
+		start = end
+	}
+	return ordered
+}
+
+// groupHunks returns [start, end) op ranges covering every change plus
+// contextLines of surrounding equal lines. Changes separated by no more than
+// twice the context share a hunk.
+func groupHunks(ops []op) [][2]int {
+	var hunks [][2]int
+	for i, current := range ops {
+		if current.kind == opEqual {
+			continue
+		}
+		start := max(0, i-contextLines)
+		end := min(len(ops), i+1+contextLines)
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1][1] {
+			hunks[len(hunks)-1][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	return hunks
+}
+
+func writeHunk(builder *strings.Builder, ops []op) {
+	fromCount, toCount := 0, 0
+	for _, current := range ops {
+		if current.kind != opInsert {
+			fromCount++
+		}
+		if current.kind != opDelete {
+			toCount++
+		}
+	}
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(ops[0].fromLine, fromCount), hunkRange(ops[0].toLine, toCount))
+	for _, current := range ops {
+		builder.WriteByte(byte(current.kind))
+		builder.WriteString(current.text)
+		if !strings.HasSuffix(current.text, "\n") {
+			builder.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats a hunk header range. Empty ranges name the line before
+// the hunk, as diff -u does.
+func hunkRange(line int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line)
+	case 1:
+		return fmt.Sprintf("%d", line+1)
+	default:
+		return fmt.Sprintf("%d,%d", line+1, count)
+	}
+}
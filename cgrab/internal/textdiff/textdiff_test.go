@@ -0,0 +1,140 @@
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedReturnsEmptyForEqualTexts(t *testing.T) {
+	if diff, err := Unified("a", "b", "same\n", "same\n"); err != nil || diff != "" {
+		t.Fatalf("expected no diff, got %q", diff)
+	}
+}
+
+func TestUnifiedRendersHunksWithContext(t *testing.T) {
+	from := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	to := "one\ntwo\nthree\nFOUR\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	want := strings.Join([]string{
+		"--- old.md",
+		"+++ new.md",
+		"@@ -1,10 +1,11 @@",
+		" one",
+		" two",
+		" three",
+		"-four",
+		"+FOUR",
+		" five",
+		" six",
+		" seven",
+		" eight",
+		" nine",
+		" ten",
+		"+eleven",
+		"",
+	}, "\n")
+	if diff, _ := Unified("old.md", "new.md", from, to); diff != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", diff, want)
+	}
+}
+
+func TestUnifiedSplitsDistantChangesAndHandlesEmptyTexts(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = string(rune('a'+i)) + "\n"
+	}
+	from := strings.Join(lines, "")
+	changed := append([]string(nil), lines...)
+	changed[0] = "A\n"
+	changed[19] = "T\n"
+
+	diff, _ := Unified("x", "y", from, strings.Join(changed, ""))
+	if strings.Count(diff, "@@ -") != 2 || !strings.Contains(diff, "@@ -1,4 +1,4 @@") || !strings.Contains(diff, "@@ -17,4 +17,4 @@") {
+		t.Fatalf("expected two hunks, got:\n%s", diff)
+	}
+
+	if diff, _ := Unified("x", "y", "", "new\n"); !strings.Contains(diff, "@@ -0,0 +1 @@\n+new\n") {
+		t.Fatalf("unexpected diff from empty text:\n%s", diff)
+	}
+	if diff, _ := Unified("x", "y", "end", "end\n"); !strings.Contains(diff, "-end\n\\ No newline at end of file\n+end\n") {
+		t.Fatalf("expected missing newline marker, got:\n%s", diff)
+	}
+}
+
+// lcsLength is the quadratic reference the edit script is checked against.
+func lcsLength(from []string, to []string) int {
+	row := make([]int, len(to)+1)
+	for _, fromLine := range from {
+		diagonal := 0
+		for j, toLine := range to {
+			above := row[j+1]
+			if fromLine == toLine {
+				row[j+1] = diagonal + 1
+			} else {
+				row[j+1] = max(row[j+1], row[j])
+			}
+			diagonal = above
+		}
+	}
+	return row[len(to)]
+}
+
+func TestEditScriptIsShortestAndReproducesBothTexts(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, random.Intn(30))
+		for i := range lines {
+			lines[i] = fmt.Sprintf("%c\n", 'a'+random.Intn(4))
+		}
+		return lines
+	}
+	for trial := 0; trial < 500; trial++ {
+		from, to := randomLines(), randomLines()
+		ops := editScript(from, to)
+		var gotFrom, gotTo []string
+		edits := 0
+		for _, current := range ops {
+			if current.kind != opInsert {
+				gotFrom = append(gotFrom, current.text)
+			}
+			if current.kind != opDelete {
+				gotTo = append(gotTo, current.text)
+			}
+			if current.kind != opEqual {
+				edits++
+			}
+		}
+		if strings.Join(gotFrom, "") != strings.Join(from, "") || strings.Join(gotTo, "") != strings.Join(to, "") {
+			t.Fatalf("trial %d: edit script does not reproduce %q -> %q", trial, from, to)
+		}
+		if want := len(from) + len(to) - 2*lcsLength(from, to); edits != want {
+			t.Fatalf("trial %d: expected %d edits, got %d", trial, want, edits)
+		}
+	}
+}
+
+func TestUnifiedHandlesLargeRewritesAndRejectsOversizedInput(t *testing.T) {
+	var from, to strings.Builder
+	for i := 0; i < 4000; i++ {
+		fmt.Fprintf(&from, "old %d\n", i)
+		fmt.Fprintf(&to, "new %d\n", i)
+	}
+	diff, err := Unified("x", "y", from.String(), to.String())
+	if err != nil {
+		t.Fatalf("Unified returned error: %v", err)
+	}
+	if !strings.HasPrefix(diff, "--- x\n+++ y\n@@ -1,4000 +1,4000 @@\n-old 0\n") || strings.Count(diff, "\n+new ") != 4000 {
+		t.Fatalf("unexpected rewrite diff prefix %q", diff[:min(len(diff), 80)])
+	}
+
+	oversized := strings.Repeat("line\n", MaxLines/2+1)
+	if _, err := Unified("x", "y", "", oversized+oversized); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected an error for more than %d changed lines", MaxLines)
+	}
+	if _, err := Unified("x", "y", oversized+oversized, oversized+oversized+"tail\n"); err != nil {
+		t.Fatalf("expected a shared prefix not to count toward the limit, got %v", err)
+	}
+}
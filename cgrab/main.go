@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/anthonylu23/context_grabber/cgrab/cmd"
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		// After the first signal cancels ctx, restore the default handling so
+		// a second Ctrl-C kills a capture that is slow to unwind.
+		<-ctx.Done()
+		stop()
+	}()
+	err := cmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "error:", err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -3,6 +3,7 @@ import { readFile } from "node:fs/promises";
 import { dirname, join } from "node:path";
 import { stdin as input, stdout as output } from "node:process";
 import { fileURLToPath } from "node:url";
+import { loadRuntimeTabs } from "@context-grabber/extension-shared";
 import { type HostRequestMessage, PROTOCOL_VERSION } from "@context-grabber/shared-types";
 import {
   extractActiveTabContextFromChrome,
@@ -214,6 +215,11 @@ const main = async (): Promise<void> => {
     return;
   }
 
+  if (args.includes("--list-tabs")) {
+    emit({ tabs: await loadRuntimeTabs("CONTEXT_GRABBER_CHROME") });
+    return;
+  }
+
   await runCapture({
     now: () => new Date().toISOString(),
   });
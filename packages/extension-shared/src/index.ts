@@ -22,3 +22,9 @@ export {
 } from "./sanitize-snapshot.js";
 
 export { buildDocumentScript } from "./document-script.js";
+
+export {
+  type RuntimeTabEntry,
+  loadRuntimeTabs,
+  sanitizeRuntimeTabs,
+} from "./runtime-tabs.js";
@@ -0,0 +1,107 @@
+import { existsSync } from "node:fs";
+import { readFile } from "node:fs/promises";
+import { asString } from "./sanitize-snapshot.js";
+
+/**
+ * One tab from an extension runtime snapshot, as emitted by
+ * `native-messaging-cli.ts --list-tabs`. Window and tab indexes are 1-based
+ * to match AppleScript listings.
+ */
+export interface RuntimeTabEntry {
+  windowIndex: number;
+  tabIndex: number;
+  isActive: boolean;
+  title: string;
+  url: string;
+  isPinned?: boolean;
+  isAudible?: boolean;
+  /** RFC 3339 timestamp of when the extension first saw the tab. */
+  openedAt?: string;
+}
+
+const toPositiveInteger = (value: unknown): number | undefined => {
+  return typeof value === "number" && Number.isInteger(value) && value > 0 ? value : undefined;
+};
+
+/** Accepts epoch milliseconds or a date string and normalizes it to RFC 3339. */
+const toTimestamp = (value: unknown): string | undefined => {
+  if (typeof value !== "number" && typeof value !== "string") {
+    return undefined;
+  }
+  const date = new Date(value);
+  return Number.isNaN(date.getTime()) ? undefined : date.toISOString();
+};
+
+/**
+ * Validates a loosely-typed runtime tab snapshot, dropping entries without
+ * a usable position or URL.
+ */
+export const sanitizeRuntimeTabs = (raw: unknown): RuntimeTabEntry[] => {
+  const list = Array.isArray(raw)
+    ? raw
+    : Array.isArray((raw as { tabs?: unknown } | null)?.tabs)
+      ? (raw as { tabs: unknown[] }).tabs
+      : undefined;
+  if (!list) {
+    throw new Error("Runtime tab snapshot must be an array or an object with a tabs array.");
+  }
+
+  const tabs: RuntimeTabEntry[] = [];
+  for (const item of list) {
+    if (typeof item !== "object" || item === null) {
+      continue;
+    }
+    const entry = item as Record<string, unknown>;
+    const windowIndex = toPositiveInteger(entry.windowIndex);
+    const tabIndex = toPositiveInteger(entry.tabIndex);
+    const url = asString(entry.url);
+    if (windowIndex === undefined || tabIndex === undefined || url === undefined) {
+      continue;
+    }
+
+    const tab: RuntimeTabEntry = {
+      windowIndex,
+      tabIndex,
+      isActive: entry.isActive === true,
+      title: asString(entry.title) ?? "",
+      url,
+    };
+    if (entry.isPinned === true) {
+      tab.isPinned = true;
+    }
+    if (entry.isAudible === true) {
+      tab.isAudible = true;
+    }
+    const openedAt = toTimestamp(entry.openedAt);
+    if (openedAt !== undefined) {
+      tab.openedAt = openedAt;
+    }
+    tabs.push(tab);
+  }
+  return tabs;
+};
+
+/**
+ * Loads the tab snapshot the extension runtime publishes through
+ * `<envPrefix>_RUNTIME_TABS` (inline JSON) or `<envPrefix>_RUNTIME_TABS_PATH`
+ * (a JSON file), e.g. with envPrefix `CONTEXT_GRABBER_CHROME`.
+ */
+export const loadRuntimeTabs = async (envPrefix: string): Promise<RuntimeTabEntry[]> => {
+  const inlineTabs = process.env[`${envPrefix}_RUNTIME_TABS`];
+  if (inlineTabs && inlineTabs.length > 0) {
+    return sanitizeRuntimeTabs(JSON.parse(inlineTabs) as unknown);
+  }
+
+  const tabsPath = process.env[`${envPrefix}_RUNTIME_TABS_PATH`];
+  if (tabsPath && tabsPath.length > 0) {
+    if (!existsSync(tabsPath)) {
+      throw new Error(`Runtime tab snapshot not found at ${tabsPath}`);
+    }
+    const raw = await readFile(tabsPath, "utf8");
+    return sanitizeRuntimeTabs(JSON.parse(raw) as unknown);
+  }
+
+  throw new Error(
+    `Tab listing requires ${envPrefix}_RUNTIME_TABS or ${envPrefix}_RUNTIME_TABS_PATH.`,
+  );
+};
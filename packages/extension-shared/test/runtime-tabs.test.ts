@@ -0,0 +1,61 @@
+import { describe, expect, it } from "bun:test";
+import { loadRuntimeTabs, sanitizeRuntimeTabs } from "../src/runtime-tabs.js";
+
+describe("shared runtime tab snapshot", () => {
+  it("normalizes entries and drops unusable ones", () => {
+    const tabs = sanitizeRuntimeTabs({
+      tabs: [
+        {
+          windowIndex: 1,
+          tabIndex: 2,
+          isActive: true,
+          title: " Docs ",
+          url: "https://example.com/docs",
+          isAudible: true,
+          openedAt: 1771156800000,
+        },
+        { windowIndex: 0, tabIndex: 1, url: "https://example.com" },
+        { windowIndex: 1, tabIndex: 3, title: "No URL" },
+        "not a tab",
+      ],
+    });
+
+    expect(tabs).toEqual([
+      {
+        windowIndex: 1,
+        tabIndex: 2,
+        isActive: true,
+        title: "Docs",
+        url: "https://example.com/docs",
+        isAudible: true,
+        openedAt: "2026-02-15T12:00:00.000Z",
+      },
+    ]);
+  });
+
+  it("rejects snapshots without a tab list", () => {
+    expect(() => sanitizeRuntimeTabs({ windows: [] })).toThrow("tabs array");
+  });
+
+  it("requires a configured snapshot", async () => {
+    delete process.env.CONTEXT_GRABBER_TEST_RUNTIME_TABS;
+    delete process.env.CONTEXT_GRABBER_TEST_RUNTIME_TABS_PATH;
+    await expect(loadRuntimeTabs("CONTEXT_GRABBER_TEST")).rejects.toThrow(
+      "CONTEXT_GRABBER_TEST_RUNTIME_TABS",
+    );
+  });
+
+  it("reads inline snapshots", async () => {
+    process.env.CONTEXT_GRABBER_TEST_RUNTIME_TABS = JSON.stringify([
+      { windowIndex: 1, tabIndex: 1, url: "https://example.com" },
+    ]);
+    try {
+      const tabs = await loadRuntimeTabs("CONTEXT_GRABBER_TEST");
+      expect(tabs).toEqual([
+        { windowIndex: 1, tabIndex: 1, isActive: false, title: "", url: "https://example.com" },
+      ]);
+    } finally {
+      delete process.env.CONTEXT_GRABBER_TEST_RUNTIME_TABS;
+    }
+  });
+});